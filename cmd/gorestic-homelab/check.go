@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify repository integrity",
+	Long: `Run restic check against the configured repository, independent of a backup run.
+
+By default the check.subset from the config file is read. Use --subset to read a
+different subset of the data or --read-data to read all of it.`,
+	RunE:         runCheck,
+	SilenceUsage: true, // a failed check is not a usage error
+}
+
+var (
+	checkSubset   string
+	checkReadData bool
+	checkNoLock   bool
+)
+
+func init() {
+	checkCmd.Flags().StringVar(&checkSubset, "subset", "", "read this subset of the data, e.g. 5% or 1/10 (overrides check.subset)")
+	checkCmd.Flags().BoolVar(&checkReadData, "read-data", false, "read all data in the repository")
+	checkCmd.Flags().BoolVar(&checkNoLock, "no-lock", false, "do not lock the repository, e.g. when it is shared")
+	checkCmd.MarkFlagsMutuallyExclusive("subset", "read-data")
+}
+
+// checkOutput is the --json representation of a check result.
+type checkOutput struct {
+	Passed   bool   `json:"passed"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg.Restic.NoLock = checkNoLock
+	resticSvc := restic.New(log.Logger)
+	result, err := resticSvc.Check(cmd.Context(), cfg.Restic, checkSettings(cfg.Check, checkSubset, checkReadData))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to check repository")
+		return err
+	}
+
+	if err := writeCheckResult(os.Stdout, result, jsonOutput); err != nil {
+		return err
+	}
+
+	return result.Error
+}
+
+// checkSettings applies the command line flags to the configured check settings.
+// The check always runs, even if it is disabled for backup runs.
+func checkSettings(configured models.CheckSettings, subset string, readData bool) models.CheckSettings {
+	settings := configured
+	settings.Enabled = true
+	if subset != "" {
+		settings.Subset = subset
+		settings.ReadData = false
+	}
+	if readData {
+		settings.ReadData = true
+		settings.Subset = ""
+	}
+	return settings
+}
+
+// writeCheckResult prints the check outcome as text or JSON.
+func writeCheckResult(out io.Writer, result *models.CheckResult, asJSON bool) error {
+	duration := result.Duration.Round(time.Millisecond).String()
+
+	if asJSON {
+		output := checkOutput{Passed: result.Passed, Duration: duration}
+		if result.Error != nil {
+			output.Error = result.Error.Error()
+		}
+		return json.NewEncoder(out).Encode(output)
+	}
+
+	if result.Passed {
+		_, err := fmt.Fprintf(out, "Repository check passed (%s)\n", duration)
+		return err
+	}
+	_, err := fmt.Fprintf(out, "Repository check FAILED (%s): %v\n", duration, result.Error)
+	return err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSettings(t *testing.T) {
+	tests := []struct {
+		name       string
+		configured models.CheckSettings
+		subset     string
+		readData   bool
+		expected   models.CheckSettings
+	}{
+		{
+			name:       "config subset without flags",
+			configured: models.CheckSettings{Enabled: false, Subset: "1%"},
+			expected:   models.CheckSettings{Enabled: true, Subset: "1%"},
+		},
+		{
+			name:       "subset flag overrides config",
+			configured: models.CheckSettings{Enabled: true, Subset: "1%"},
+			subset:     "10%",
+			expected:   models.CheckSettings{Enabled: true, Subset: "10%"},
+		},
+		{
+			name:       "subset flag overrides configured read_data",
+			configured: models.CheckSettings{Enabled: true, ReadData: true},
+			subset:     "1/5",
+			expected:   models.CheckSettings{Enabled: true, Subset: "1/5"},
+		},
+		{
+			name:       "read-data flag drops subset",
+			configured: models.CheckSettings{Enabled: true, Subset: "1%"},
+			readData:   true,
+			expected:   models.CheckSettings{Enabled: true, ReadData: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, checkSettings(tt.configured, tt.subset, tt.readData))
+		})
+	}
+}
+
+func TestWriteCheckResult(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeCheckResult(&buf, &models.CheckResult{Passed: true, Duration: 1500 * time.Millisecond}, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Repository check passed (1.5s)\n", buf.String())
+}
+
+func TestWriteCheckResult_Failed(t *testing.T) {
+	var buf bytes.Buffer
+	result := &models.CheckResult{Passed: false, Duration: time.Second, Error: errors.New("pack abc is damaged")}
+	err := writeCheckResult(&buf, result, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "FAILED")
+	assert.Contains(t, buf.String(), "pack abc is damaged")
+}
+
+func TestWriteCheckResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	result := &models.CheckResult{Passed: false, Duration: time.Second, Error: errors.New("pack abc is damaged")}
+	err := writeCheckResult(&buf, result, true)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"passed":false,"duration":"1s","error":"pack abc is damaged"}`, buf.String())
+}
+
+func TestWriteCheckResult_JSONKeepsStdoutClean(t *testing.T) {
+	result := &models.CheckResult{Passed: true, Duration: time.Second}
+	stdout, _ := captureJSONOutput(t, func(out io.Writer) error {
+		return writeCheckResult(out, result, jsonOutput)
+	})
+
+	assert.JSONEq(t, `{"passed":true,"duration":"1s"}`, stdout)
+}
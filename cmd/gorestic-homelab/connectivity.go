@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/fgeck/gorestic-homelab/internal/services/ssh"
+	"github.com/fgeck/gorestic-homelab/internal/services/telegram"
+)
+
+// connectivityTimeout bounds the TCP check against the PostgreSQL host.
+const connectivityTimeout = 10 * time.Second
+
+// connectivityServices are the services used by validate --check-connectivity.
+type connectivityServices struct {
+	restic   restic.Service
+	ssh      ssh.Service
+	telegram telegram.Service
+	dial     func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// connectivityResult is the outcome of a single connectivity check.
+type connectivityResult struct {
+	Name string
+	Err  error
+}
+
+// checkConnectivity contacts every configured service without changing anything:
+// it reads the repository config, opens an SSH session without running a command,
+// calls the Telegram getMe API and opens a TCP connection to the PostgreSQL host.
+func checkConnectivity(ctx context.Context, cfg *models.BackupConfig, svcs connectivityServices) []connectivityResult {
+	var results []connectivityResult
+
+	err := svcs.restic.TestConnection(ctx, cfg.Restic)
+	results = append(results, connectivityResult{Name: "Restic repository", Err: err})
+
+	if cfg.SSHShutdown != nil {
+		result, err := svcs.ssh.TestConnection(ctx, *cfg.SSHShutdown)
+		if err == nil {
+			err = result.Error
+		}
+		results = append(results, connectivityResult{Name: "SSH " + cfg.SSHShutdown.Host, Err: err})
+	}
+
+	if cfg.Telegram != nil {
+		_, err := svcs.telegram.GetMe(ctx, *cfg.Telegram)
+		results = append(results, connectivityResult{Name: "Telegram", Err: err})
+	}
+
+	if cfg.Postgres != nil {
+		address := net.JoinHostPort(cfg.Postgres.Host, strconv.Itoa(cfg.Postgres.Port))
+		dialCtx, cancel := context.WithTimeout(ctx, connectivityTimeout)
+		conn, err := svcs.dial(dialCtx, "tcp", address)
+		cancel()
+		if err == nil {
+			_ = conn.Close()
+		}
+		results = append(results, connectivityResult{Name: "PostgreSQL " + address, Err: err})
+	}
+
+	return results
+}
+
+// writeConnectivityResults prints one OK/FAIL line per check and returns an
+// error if any check failed.
+func writeConnectivityResults(out io.Writer, results []connectivityResult) error {
+	if _, err := fmt.Fprintln(out, "Connectivity:"); err != nil {
+		return err
+	}
+
+	var failed []error
+	for _, result := range results {
+		status := "OK"
+		if result.Err != nil {
+			status = "FAIL (" + result.Err.Error() + ")"
+			failed = append(failed, fmt.Errorf("%s: %w", result.Name, result.Err))
+		}
+		if _, err := fmt.Fprintf(out, "  %s: %s\n", result.Name, status); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("connectivity check failed: %w", errors.Join(failed...))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	resticmocks "github.com/fgeck/gorestic-homelab/internal/services/restic/mocks"
+	sshmocks "github.com/fgeck/gorestic-homelab/internal/services/ssh/mocks"
+	telegrammocks "github.com/fgeck/gorestic-homelab/internal/services/telegram/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckConnectivity_AllServices(t *testing.T) {
+	resticSvc := resticmocks.NewMockService(t)
+	sshSvc := sshmocks.NewMockService(t)
+	telegramSvc := telegrammocks.NewMockService(t)
+
+	cfg := &models.BackupConfig{
+		Restic:      models.ResticConfig{Repository: "rest:http://backup.lan:8000/homelab"},
+		SSHShutdown: &models.SSHShutdownConfig{Host: "nas.lan", Port: 22},
+		Telegram:    &models.TelegramConfig{BotToken: "token", ChatIDs: []string{"1"}},
+		Postgres:    &models.PostgresConfig{Host: "db.lan", Port: 5432},
+	}
+
+	resticSvc.EXPECT().TestConnection(mock.Anything, cfg.Restic).Return(nil)
+	sshSvc.EXPECT().TestConnection(mock.Anything, *cfg.SSHShutdown).Return(&models.SSHResult{Error: errors.New("connection refused")}, nil)
+	telegramSvc.EXPECT().GetMe(mock.Anything, *cfg.Telegram).Return("homelab_bot", nil)
+
+	var dialed string
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = address
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+
+	results := checkConnectivity(context.Background(), cfg, connectivityServices{
+		restic:   resticSvc,
+		ssh:      sshSvc,
+		telegram: telegramSvc,
+		dial:     dial,
+	})
+
+	require.Len(t, results, 4)
+	assert.Equal(t, "Restic repository", results[0].Name)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "SSH nas.lan", results[1].Name)
+	assert.EqualError(t, results[1].Err, "connection refused")
+	assert.Equal(t, "Telegram", results[2].Name)
+	assert.NoError(t, results[2].Err)
+	assert.Equal(t, "PostgreSQL db.lan:5432", results[3].Name)
+	assert.NoError(t, results[3].Err)
+	assert.Equal(t, "db.lan:5432", dialed)
+}
+
+func TestCheckConnectivity_OnlyRepository(t *testing.T) {
+	resticSvc := resticmocks.NewMockService(t)
+	resticSvc.EXPECT().TestConnection(mock.Anything, mock.Anything).Return(errors.New("wrong repository password: exit status 12"))
+
+	// The other services are not configured, so their mocks must not be called.
+	results := checkConnectivity(context.Background(), &models.BackupConfig{}, connectivityServices{
+		restic:   resticSvc,
+		ssh:      sshmocks.NewMockService(t),
+		telegram: telegrammocks.NewMockService(t),
+	})
+
+	require.Len(t, results, 1)
+	assert.EqualError(t, results[0].Err, "wrong repository password: exit status 12")
+}
+
+func TestWriteConnectivityResults(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeConnectivityResults(&buf, []connectivityResult{
+		{Name: "Restic repository"},
+		{Name: "Telegram", Err: errors.New("telegram API returned status 401: Unauthorized")},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Telegram: telegram API returned status 401")
+	assert.Equal(t, "Connectivity:\n  Restic repository: OK\n  Telegram: FAIL (telegram API returned status 401: Unauthorized)\n", buf.String())
+}
+
+func TestWriteConnectivityResults_AllOK(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeConnectivityResults(&buf, []connectivityResult{{Name: "Restic repository"}})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Connectivity:\n  Restic repository: OK\n", buf.String())
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var generateConfigCmd = &cobra.Command{
+	Use:   "generate-config",
+	Short: "Write a commented configuration template",
+	Long: `Write a fully commented example configuration covering all sections.
+
+The template is printed to stdout unless --output is given. An existing file is
+only overwritten with --force.`,
+	RunE:         runGenerateConfig,
+	SilenceUsage: true,
+}
+
+var (
+	generateOutput string
+	generateForce  bool
+)
+
+func init() {
+	generateConfigCmd.Flags().StringVarP(&generateOutput, "output", "o", "", "write the template to this file instead of stdout")
+	generateConfigCmd.Flags().BoolVar(&generateForce, "force", false, "overwrite an existing output file")
+}
+
+func runGenerateConfig(cmd *cobra.Command, args []string) error {
+	if generateOutput == "" {
+		return config.WriteTemplate(os.Stdout)
+	}
+
+	if err := writeTemplateFile(generateOutput, generateForce); err != nil {
+		return err
+	}
+
+	log.Info().Str("file", generateOutput).Msg("config template written")
+	return nil
+}
+
+// writeTemplateFile writes the config template to path, refusing to replace an
+// existing file unless force is set. The file may contain secrets once edited,
+// so it is only readable by the owner.
+func writeTemplateFile(path string, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		if errors.Is(err, fs.ErrExist) {
+			return fmt.Errorf("%s already exists, use --force to overwrite", path)
+		}
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+
+	if err := config.WriteTemplate(f); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
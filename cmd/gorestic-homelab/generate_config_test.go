@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTemplateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	require.NoError(t, writeTemplateFile(path, false))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "restic:")
+}
+
+func TestWriteTemplateFile_ExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("keep me"), 0o600))
+
+	err := writeTemplateFile(path, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "use --force")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "keep me", string(content))
+}
+
+func TestWriteTemplateFile_Force(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("replace me"), 0o600))
+
+	require.NoError(t, writeTemplateFile(path, true))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "replace me")
+	assert.Contains(t, string(content), "restic:")
+}
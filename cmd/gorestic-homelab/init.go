@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Initialize the restic repository",
+	Long: `Initialize the configured restic repository if it does not exist yet.
+
+An existing repository is left untouched, so the command is safe to run repeatedly.`,
+	RunE:         runInit,
+	SilenceUsage: true,
+}
+
+// initOutput is the --json representation of an init result.
+type initOutput struct {
+	Created    bool   `json:"created"`
+	Repository string `json:"repository"`
+}
+
+func runInit(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	resticSvc := restic.New(log.Logger)
+	result, err := resticSvc.Init(cmd.Context(), cfg.Restic)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to initialize repository")
+		return err
+	}
+
+	return writeInitResult(os.Stdout, result, cfg.Restic.Repository, jsonOutput)
+}
+
+// writeInitResult prints the init outcome as text or JSON.
+func writeInitResult(out io.Writer, result *models.InitResult, repository string, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(out).Encode(initOutput{Created: result.Created, Repository: repository})
+	}
+
+	if result.Created {
+		_, err := fmt.Fprintf(out, "Created repository %s\n", repository)
+		return err
+	}
+	_, err := fmt.Fprintf(out, "Repository %s already initialized\n", repository)
+	return err
+}
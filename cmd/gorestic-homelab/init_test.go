@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteInitResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *models.InitResult
+		expected string
+	}{
+		{
+			name:     "newly created",
+			result:   &models.InitResult{Created: true},
+			expected: "Created repository /backups/repo\n",
+		},
+		{
+			name:     "already initialized",
+			result:   &models.InitResult{Created: false},
+			expected: "Repository /backups/repo already initialized\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeInitResult(&buf, tt.result, "/backups/repo", false)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteInitResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeInitResult(&buf, &models.InitResult{Created: false}, "/backups/repo", true)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"created":false,"repository":"/backups/repo"}`, buf.String())
+}
+
+func TestWriteInitResult_JSONKeepsStdoutClean(t *testing.T) {
+	stdout, _ := captureJSONOutput(t, func(out io.Writer) error {
+		return writeInitResult(out, &models.InitResult{Created: true}, "/backups/repo", jsonOutput)
+	})
+
+	assert.JSONEq(t, `{"created":true,"repository":"/backups/repo"}`, stdout)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/syslog"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// syslogTag identifies the process in syslog and the journal.
+const syslogTag = "gorestic-homelab"
+
+// logFileOut is the --log-file writer. It is only appended to and stays open
+// until the process exits.
+var logFileOut io.Writer
+
+func setupLogging() error {
+	// Keep stdout free for the run summary and --json command results
+	var out io.Writer = os.Stdout
+	if summaryJSON || jsonOutput {
+		out = os.Stderr
+	}
+
+	if logSyslog {
+		w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, syslogTag)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		out = syslogLevelWriter{w: w}
+	}
+
+	if logFile != "" && logFileOut == nil {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open log file: %w", err)
+		}
+		logFileOut = f
+	}
+
+	log.Logger = newLogger(out, logFileOut, jsonOutput || logSyslog)
+
+	// Set log level
+	switch {
+	case quiet:
+		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+	case verbose:
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	default:
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	}
+	return nil
+}
+
+// newLogger creates a logger writing to out, in JSON or human-readable console
+// format. If file is not nil, every log line is also written to it as JSON.
+// If out is a zerolog.LevelWriter, e.g. for syslog, it receives the level of each line.
+func newLogger(out, file io.Writer, asJSON bool) zerolog.Logger {
+	if !asJSON {
+		console := zerolog.ConsoleWriter{Out: out, TimeFormat: "15:04:05"}
+		console.FormatLevel = func(i interface{}) string {
+			if s, ok := i.(string); ok {
+				return strings.ToUpper(s)
+			}
+			return ""
+		}
+		out = console
+	}
+
+	if file != nil {
+		out = zerolog.MultiLevelWriter(out, file)
+	}
+	return zerolog.New(out).With().Timestamp().Logger()
+}
+
+// syslogPriority maps a zerolog level to a syslog priority. Fatal and panic
+// map to crit rather than emerg, which would be broadcast to all terminals.
+func syslogPriority(level zerolog.Level) syslog.Priority {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return syslog.LOG_DEBUG
+	case zerolog.WarnLevel:
+		return syslog.LOG_WARNING
+	case zerolog.ErrorLevel:
+		return syslog.LOG_ERR
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return syslog.LOG_CRIT
+	default:
+		return syslog.LOG_INFO
+	}
+}
+
+// syslogSender is the part of *syslog.Writer used for logging.
+type syslogSender interface {
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+	Crit(m string) error
+}
+
+// syslogLevelWriter sends each log line with the priority matching its level.
+type syslogLevelWriter struct {
+	w syslogSender
+}
+
+func (s syslogLevelWriter) Write(p []byte) (int, error) {
+	return s.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (s syslogLevelWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	send := s.w.Info
+	switch syslogPriority(level) {
+	case syslog.LOG_DEBUG:
+		send = s.w.Debug
+	case syslog.LOG_WARNING:
+		send = s.w.Warning
+	case syslog.LOG_ERR:
+		send = s.w.Err
+	case syslog.LOG_CRIT:
+		send = s.w.Crit
+	}
+	if err := send(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/syslog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger_TeesToFileAsJSON(t *testing.T) {
+	var console, file bytes.Buffer
+	logger := newLogger(&console, &file, false)
+
+	logger.Info().Str("snapshot_id", "abc123").Msg("snapshot created")
+
+	assert.Contains(t, console.String(), "INFO")
+	assert.Contains(t, console.String(), "snapshot created")
+	assert.NotContains(t, console.String(), `"level"`)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(file.Bytes(), &entry))
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "snapshot created", entry["message"])
+	assert.Equal(t, "abc123", entry["snapshot_id"])
+}
+
+func TestNewLogger_JSONWithoutFile(t *testing.T) {
+	var console bytes.Buffer
+	logger := newLogger(&console, nil, true)
+
+	logger.Warn().Msg("repository is locked")
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(console.Bytes(), &entry))
+	assert.Equal(t, "warn", entry["level"])
+}
+
+func TestSyslogPriority(t *testing.T) {
+	tests := []struct {
+		level    zerolog.Level
+		priority syslog.Priority
+	}{
+		{zerolog.TraceLevel, syslog.LOG_DEBUG},
+		{zerolog.DebugLevel, syslog.LOG_DEBUG},
+		{zerolog.InfoLevel, syslog.LOG_INFO},
+		{zerolog.NoLevel, syslog.LOG_INFO},
+		{zerolog.WarnLevel, syslog.LOG_WARNING},
+		{zerolog.ErrorLevel, syslog.LOG_ERR},
+		{zerolog.FatalLevel, syslog.LOG_CRIT},
+		{zerolog.PanicLevel, syslog.LOG_CRIT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			assert.Equal(t, tt.priority, syslogPriority(tt.level))
+		})
+	}
+}
+
+// fakeSyslog records the priority method called for each message.
+type fakeSyslog struct {
+	sent []string
+}
+
+func (f *fakeSyslog) record(priority, m string) error {
+	f.sent = append(f.sent, priority+" "+m)
+	return nil
+}
+
+func (f *fakeSyslog) Debug(m string) error   { return f.record("debug", m) }
+func (f *fakeSyslog) Info(m string) error    { return f.record("info", m) }
+func (f *fakeSyslog) Warning(m string) error { return f.record("warning", m) }
+func (f *fakeSyslog) Err(m string) error     { return f.record("err", m) }
+func (f *fakeSyslog) Crit(m string) error    { return f.record("crit", m) }
+
+func TestNewLogger_Syslog(t *testing.T) {
+	sys := &fakeSyslog{}
+	var file bytes.Buffer
+	logger := newLogger(syslogLevelWriter{w: sys}, &file, true)
+
+	logger.Warn().Msg("repository is locked")
+	logger.Error().Msg("backup failed")
+
+	require.Len(t, sys.sent, 2)
+	assert.True(t, strings.HasPrefix(sys.sent[0], `warning {"level":"warn"`))
+	assert.True(t, strings.HasPrefix(sys.sent[1], `err {"level":"error"`))
+	assert.Contains(t, file.String(), "backup failed")
+}
+
+// captureJSONOutput sets up logging for --json with stdout and stderr
+// redirected to files, logs a line and calls write with os.Stdout. It returns
+// what ended up on stdout and stderr.
+func captureJSONOutput(t *testing.T, write func(out io.Writer) error) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	require.NoError(t, err)
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	require.NoError(t, err)
+
+	origStdout, origStderr, origLogger, origLevel := os.Stdout, os.Stderr, log.Logger, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr, log.Logger, jsonOutput = origStdout, origStderr, origLogger, false
+		zerolog.SetGlobalLevel(origLevel)
+	})
+	os.Stdout, os.Stderr, jsonOutput = stdout, stderr, true
+
+	require.NoError(t, setupLogging())
+	log.Info().Msg("talking to the repository")
+	require.NoError(t, write(os.Stdout))
+
+	out, err := os.ReadFile(stdout.Name())
+	require.NoError(t, err)
+	logs, err := os.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	return string(out), string(logs)
+}
+
+func TestSetupLogging_JSONKeepsStdoutForResults(t *testing.T) {
+	snapshots := []models.Snapshot{{ID: "abc123", Hostname: "homelab", Time: time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)}}
+	stdout, stderr := captureJSONOutput(t, func(out io.Writer) error {
+		return json.NewEncoder(out).Encode(snapshots)
+	})
+
+	var decoded []models.Snapshot
+	require.NoError(t, json.Unmarshal([]byte(stdout), &decoded))
+	assert.Equal(t, snapshots, decoded)
+	assert.Contains(t, stderr, "talking to the repository")
+}
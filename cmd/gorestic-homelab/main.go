@@ -2,11 +2,37 @@
 package main
 
 import (
+	"errors"
 	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/fgeck/gorestic-homelab/internal/services/runner"
+)
+
+// Exit codes, so monitoring can tell a failed backup from a broken configuration.
+const (
+	exitSuccess = 0
+	exitFailure = 1 // runtime or backup failure
+	exitConfig  = 2 // configuration could not be loaded or is invalid
+	exitPartial = 3 // some snapshots were created before the run failed
 )
 
 func main() {
-	if err := Execute(); err != nil {
-		os.Exit(1)
+	os.Exit(exitCode(Execute()))
+}
+
+// exitCode maps the error returned by a command to the process exit code.
+func exitCode(err error) int {
+	var configErr *config.Error
+	var partialErr *runner.PartialError
+	switch {
+	case err == nil:
+		return exitSuccess
+	case errors.As(err, &configErr):
+		return exitConfig
+	case errors.As(err, &partialErr):
+		return exitPartial
+	default:
+		return exitFailure
 	}
 }
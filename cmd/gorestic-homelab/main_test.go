@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/fgeck/gorestic-homelab/internal/services/runner"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "success", err: nil, expected: 0},
+		{name: "runtime failure", err: errors.New("backup failed"), expected: 1},
+		{name: "config error", err: &config.Error{Err: errors.New("restic.repository is required")}, expected: 2},
+		{name: "wrapped config error", err: fmt.Errorf("load: %w", &config.Error{Err: errors.New("bad")}), expected: 2},
+		{name: "partial failure", err: fmt.Errorf("backup failed: %w", &runner.PartialError{Err: errors.New("disk full"), Snapshots: 1}), expected: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, exitCode(tt.err))
+		})
+	}
+}
+
+func TestRequireConfigSource(t *testing.T) {
+	t.Cleanup(func() { configFile, fromEnv = "", false })
+	var help bytes.Buffer
+	cmd := &cobra.Command{Use: "run", RunE: func(*cobra.Command, []string) error { return nil }}
+	cmd.SetOut(&help)
+
+	configFile, fromEnv = "", false
+	err := requireConfigSource(cmd)
+	require.Error(t, err)
+	assert.Equal(t, exitConfig, exitCode(err))
+	assert.Contains(t, help.String(), "Usage:")
+	assert.True(t, cmd.SilenceUsage, "the usage is not printed a second time")
+
+	configFile = "config.yaml"
+	assert.NoError(t, requireConfigSource(cmd))
+
+	configFile, fromEnv = "", true
+	assert.NoError(t, requireConfigSource(cmd))
+}
+
+func TestValidateConfig_MissingFileIsConfigError(t *testing.T) {
+	t.Cleanup(func() { configFile = "" })
+	configFile = filepath.Join(t.TempDir(), "missing.yaml")
+
+	err := validateConfig(validateCmd, nil)
+
+	require.Error(t, err)
+	assert.Equal(t, exitConfig, exitCode(err))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// writeConfig prints cfg with its secrets redacted as YAML, or as JSON when asJSON is set.
+func writeConfig(out io.Writer, cfg models.BackupConfig, asJSON bool) error {
+	resolved := printable(reflect.ValueOf(config.Redact(cfg)))
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(resolved)
+	}
+
+	enc := yaml.NewEncoder(out)
+	enc.SetIndent(2)
+	if err := enc.Encode(resolved); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// printable converts v into maps, slices and scalars keyed by the Go field
+// names, so durations print as "5m0s" and byte slices as text in both formats.
+func printable(v reflect.Value) any {
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		if v.IsNil() {
+			return nil
+		}
+		return string(v.Bytes())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return printable(v.Elem())
+	case reflect.Struct:
+		fields := make(map[string]any, v.NumField())
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fields[field.Name] = printable(v.Field(i))
+		}
+		return fields
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		entries := make(map[string]any, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			entries[iter.Key().String()] = printable(iter.Value())
+		}
+		return entries
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		items := make([]any, v.Len())
+		for i := range items {
+			items[i] = printable(v.Index(i))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
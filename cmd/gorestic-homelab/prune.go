@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove unreferenced data from the repository",
+	Long: `Run restic prune against the configured repository, independent of a backup run.
+
+By default retention.prune.max_unused from the config file is used. Use --dry-run
+to see how much space would be reclaimed without changing the repository.`,
+	RunE:         runPrune,
+	SilenceUsage: true, // a failed prune is not a usage error
+}
+
+var (
+	pruneMaxUnused           string
+	pruneDryRun              bool
+	pruneRepackCacheableOnly bool
+)
+
+func init() {
+	pruneCmd.Flags().StringVar(&pruneMaxUnused, "max-unused", "", "tolerate this much unused space, e.g. 5% or 1G (overrides retention.prune.max_unused)")
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "only report what would be removed")
+	pruneCmd.Flags().BoolVar(&pruneRepackCacheableOnly, "repack-cacheable-only", false, "only repack packs containing tree and metadata blobs")
+}
+
+// pruneOutput is the --json representation of a prune result.
+type pruneOutput struct {
+	Success    bool   `json:"success"`
+	DryRun     bool   `json:"dry_run"`
+	SpaceFreed int64  `json:"space_freed"`
+	Duration   string `json:"duration"`
+	Error      string `json:"error,omitempty"`
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg.Restic.DryRun = pruneDryRun
+	resticSvc := restic.New(log.Logger)
+	result, err := resticSvc.Prune(cmd.Context(), cfg.Restic, pruneSettings(cfg.Retention.Prune, pruneMaxUnused, pruneRepackCacheableOnly))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to prune repository")
+		return err
+	}
+
+	if err := writePruneResult(os.Stdout, result, pruneDryRun, jsonOutput); err != nil {
+		return err
+	}
+
+	return result.Error
+}
+
+// pruneSettings applies the command line flags to the configured prune settings.
+// The prune always runs, even if it is disabled for backup runs.
+func pruneSettings(configured models.PruneSettings, maxUnused string, repackCacheableOnly bool) models.PruneSettings {
+	settings := configured
+	settings.Enabled = true
+	if maxUnused != "" {
+		settings.MaxUnused = maxUnused
+	}
+	settings.RepackCacheableOnly = repackCacheableOnly
+	return settings
+}
+
+// writePruneResult prints the reclaimed space as text or JSON.
+func writePruneResult(out io.Writer, result *models.PruneResult, dryRun, asJSON bool) error {
+	duration := result.Duration.Round(time.Millisecond).String()
+
+	if asJSON {
+		output := pruneOutput{
+			Success:    result.Error == nil,
+			DryRun:     dryRun,
+			SpaceFreed: result.SpaceFreed,
+			Duration:   duration,
+		}
+		if result.Error != nil {
+			output.Error = result.Error.Error()
+		}
+		return json.NewEncoder(out).Encode(output)
+	}
+
+	var err error
+	switch {
+	case result.Error != nil:
+		_, err = fmt.Fprintf(out, "Prune FAILED (%s): %v\n", duration, result.Error)
+	case dryRun:
+		_, err = fmt.Fprintf(out, "Prune would reclaim %s (dry-run, %s)\n", format.Bytes(result.SpaceFreed), duration)
+	default:
+		_, err = fmt.Fprintf(out, "Prune reclaimed %s (%s)\n", format.Bytes(result.SpaceFreed), duration)
+	}
+	return err
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruneSettings(t *testing.T) {
+	tests := []struct {
+		name                string
+		configured          models.PruneSettings
+		maxUnused           string
+		repackCacheableOnly bool
+		expected            models.PruneSettings
+	}{
+		{
+			name:       "config max_unused without flags",
+			configured: models.PruneSettings{Enabled: false, MaxUnused: "5%"},
+			expected:   models.PruneSettings{Enabled: true, MaxUnused: "5%"},
+		},
+		{
+			name:       "max-unused flag overrides config",
+			configured: models.PruneSettings{Enabled: true, MaxUnused: "5%"},
+			maxUnused:  "1G",
+			expected:   models.PruneSettings{Enabled: true, MaxUnused: "1G"},
+		},
+		{
+			name:                "repack-cacheable-only flag",
+			repackCacheableOnly: true,
+			expected:            models.PruneSettings{Enabled: true, RepackCacheableOnly: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, pruneSettings(tt.configured, tt.maxUnused, tt.repackCacheableOnly))
+		})
+	}
+}
+
+func TestWritePruneResult(t *testing.T) {
+	result := &models.PruneResult{SpaceFreed: 1024 * 1024, Duration: 1500 * time.Millisecond}
+
+	var buf bytes.Buffer
+	require.NoError(t, writePruneResult(&buf, result, false, false))
+	assert.Equal(t, "Prune reclaimed 1.0 MiB (1.5s)\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writePruneResult(&buf, result, true, false))
+	assert.Equal(t, "Prune would reclaim 1.0 MiB (dry-run, 1.5s)\n", buf.String())
+}
+
+func TestWritePruneResult_Failed(t *testing.T) {
+	var buf bytes.Buffer
+	result := &models.PruneResult{Duration: time.Second, Error: errors.New("repository is already locked")}
+	err := writePruneResult(&buf, result, false, false)
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "FAILED")
+	assert.Contains(t, buf.String(), "repository is already locked")
+}
+
+func TestWritePruneResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	result := &models.PruneResult{SpaceFreed: 2048, Duration: time.Second}
+	err := writePruneResult(&buf, result, true, true)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"success":true,"dry_run":true,"space_freed":2048,"duration":"1s"}`, buf.String())
+}
+
+func TestWritePruneResult_JSONKeepsStdoutClean(t *testing.T) {
+	result := &models.PruneResult{SpaceFreed: 4096, Duration: time.Second}
+	stdout, _ := captureJSONOutput(t, func(out io.Writer) error {
+		return writePruneResult(out, result, false, jsonOutput)
+	})
+
+	assert.JSONEq(t, `{"success":true,"dry_run":false,"space_freed":4096,"duration":"1s"}`, stdout)
+}
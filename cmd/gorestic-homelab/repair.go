@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Repair a damaged repository",
+	Long: `Repair the configured repository, e.g. after a power loss corrupted the index.
+
+--index rebuilds the index from the pack files. --snapshots rewrites snapshots that
+reference missing data and forgets the originals. When both are given the index is
+repaired first. Run check afterwards to verify the repository.`,
+	RunE:         runRepair,
+	SilenceUsage: true, // a failed repair is not a usage error
+}
+
+var (
+	repairIndex     bool
+	repairSnapshots bool
+)
+
+func init() {
+	repairCmd.Flags().BoolVar(&repairIndex, "index", false, "rebuild the repository index")
+	repairCmd.Flags().BoolVar(&repairSnapshots, "snapshots", false, "rewrite snapshots referencing missing data and forget the originals")
+	repairCmd.MarkFlagsOneRequired("index", "snapshots")
+}
+
+// repairOutput is the --json representation of a repair result.
+type repairOutput struct {
+	Target   string `json:"target"`
+	Success  bool   `json:"success"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runRepair(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	resticSvc := restic.New(log.Logger)
+
+	steps := []struct {
+		enabled bool
+		target  string
+		repair  func() (*models.RepairResult, error)
+	}{
+		{repairIndex, "index", func() (*models.RepairResult, error) { return resticSvc.RepairIndex(cmd.Context(), cfg.Restic) }},
+		{repairSnapshots, "snapshots", func() (*models.RepairResult, error) { return resticSvc.RepairSnapshots(cmd.Context(), cfg.Restic) }},
+	}
+
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+
+		result, err := step.repair()
+		if err != nil {
+			log.Error().Err(err).Str("target", step.target).Msg("failed to repair repository")
+			return err
+		}
+
+		if err := writeRepairResult(os.Stdout, step.target, result, jsonOutput); err != nil {
+			return err
+		}
+		// Do not repair snapshots on top of a broken index.
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+
+	return nil
+}
+
+// writeRepairResult prints the outcome of one repair step as text or JSON.
+func writeRepairResult(out io.Writer, target string, result *models.RepairResult, asJSON bool) error {
+	duration := result.Duration.Round(time.Millisecond).String()
+
+	if asJSON {
+		output := repairOutput{Target: target, Success: result.Error == nil, Duration: duration}
+		if result.Error != nil {
+			output.Error = result.Error.Error()
+		}
+		return json.NewEncoder(out).Encode(output)
+	}
+
+	if result.Error == nil {
+		_, err := fmt.Fprintf(out, "Repaired %s (%s)\n", target, duration)
+		return err
+	}
+	_, err := fmt.Fprintf(out, "Repair of %s FAILED (%s): %v\n", target, duration, result.Error)
+	return err
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRepairResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *models.RepairResult
+		expected string
+	}{
+		{
+			name:     "success",
+			result:   &models.RepairResult{Duration: 1500 * time.Millisecond},
+			expected: "Repaired index (1.5s)\n",
+		},
+		{
+			name:     "failure",
+			result:   &models.RepairResult{Duration: time.Second, Error: errors.New("pack not found")},
+			expected: "Repair of index FAILED (1s): pack not found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeRepairResult(&buf, "index", tt.result, false)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteRepairResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeRepairResult(&buf, "snapshots", &models.RepairResult{Duration: 2 * time.Second, Error: errors.New("boom")}, true)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"target":"snapshots","success":false,"duration":"2s","error":"boom"}`, buf.String())
+}
+
+func TestWriteRepairResult_JSONKeepsStdoutClean(t *testing.T) {
+	stdout, _ := captureJSONOutput(t, func(out io.Writer) error {
+		return writeRepairResult(out, "index", &models.RepairResult{Duration: 2 * time.Second}, jsonOutput)
+	})
+
+	assert.JSONEq(t, `{"target":"index","success":true,"duration":"2s"}`, stdout)
+}
@@ -1,10 +1,10 @@
 package main
 
 import (
-	"os"
-	"strings"
+	"errors"
 
-	"github.com/rs/zerolog"
+	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/fgeck/gorestic-homelab/internal/models"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -15,9 +15,12 @@ var (
 
 	// Configuration flags.
 	configFile string
+	fromEnv    bool
 	verbose    bool
 	quiet      bool
 	jsonOutput bool
+	logFile    string
+	logSyslog  bool
 )
 
 var rootCmd = &cobra.Command{
@@ -26,51 +29,101 @@ var rootCmd = &cobra.Command{
 	Long: `gorestic-homelab is a Go-based backup orchestrator that handles:
   - Wake-on-LAN to wake backup targets
   - PostgreSQL backups via pg_dump
+  - MySQL/MariaDB backups via mysqldump
+  - SQLite snapshots via sqlite3 .backup
   - Restic backup operations
   - SSH shutdown of remote servers
   - Telegram notifications
 
-Use as a one-shot command with an external scheduler (cron, systemd timer, etc.)`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		setupLogging()
+Use as a one-shot command with an external scheduler (cron, systemd timer, etc.)
+or let the schedule command run backups itself.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupLogging()
 	},
 	Version: Version,
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "config file (required)")
+	rootCmd.PersistentFlags().BoolVar(&fromEnv, "from-env", false, "read the configuration from GORESTIC_* environment variables instead of a file")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose (debug) output")
 	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "enable quiet mode (errors only)")
-	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output logs in JSON format")
+	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "output logs and command results in JSON format (logs go to stderr)")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "also append logs to this file, always in JSON format")
+	rootCmd.PersistentFlags().BoolVar(&logSyslog, "log-syslog", false, "send logs to syslog/journald instead of stdout")
 
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(snapshotsCmd)
+	rootCmd.AddCommand(checkCmd)
+	rootCmd.AddCommand(pruneCmd)
+	rootCmd.AddCommand(initCmd)
+	rootCmd.AddCommand(unlockCmd)
+	rootCmd.AddCommand(repairCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(generateConfigCmd)
+	rootCmd.AddCommand(schemaCmd)
 }
 
-func setupLogging() {
-	// Set output format
-	if jsonOutput {
-		log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+// errNoConfigSource is returned by commands started without --config or --from-env.
+var errNoConfigSource = errors.New("config file or --from-env is required")
+
+// requireConfigSource prints the help and returns a config error, which exits
+// with exitConfig, unless --config or --from-env is given.
+func requireConfigSource(cmd *cobra.Command) error {
+	if configFile != "" || fromEnv {
+		return nil
+	}
+	log.Error().Msg(errNoConfigSource.Error())
+	_ = cmd.Help()
+	// The help was printed already, cobra only has to report the error
+	cmd.SilenceUsage = true
+	return &config.Error{Err: errNoConfigSource}
+}
+
+// parseConfig parses the file given via --config, or only the GORESTIC_*
+// environment variables with --from-env. Environment variables also
+// override values from the file.
+func parseConfig() (*models.BackupConfig, error) {
+	parser := config.NewParser()
+
+	var cfg *models.BackupConfig
+	var err error
+	if configFile == "" {
+		cfg, err = parser.LoadEnv()
 	} else {
-		output := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "15:04:05"}
-		output.FormatLevel = func(i interface{}) string {
-			if s, ok := i.(string); ok {
-				return strings.ToUpper(s)
-			}
-			return ""
+		cfg, err = parser.LoadFile(configFile)
+	}
+
+	// Switch to syslog before anything is logged about the config
+	if err == nil && cfg.LogOutput == models.LogOutputSyslog && !logSyslog {
+		logSyslog = true
+		if err := setupLogging(); err != nil {
+			return nil, err
 		}
-		log.Logger = zerolog.New(output).With().Timestamp().Logger()
 	}
 
-	// Set log level
-	switch {
-	case quiet:
-		zerolog.SetGlobalLevel(zerolog.ErrorLevel)
-	case verbose:
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	default:
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	for _, warning := range parser.Warnings() {
+		log.Warn().Msg(warning)
 	}
+	return cfg, err
+}
+
+// loadConfig parses and validates the configuration.
+func loadConfig() (*models.BackupConfig, error) {
+	cfg, err := parseConfig()
+	if err != nil {
+		log.Error().Err(err).Str("file", configFile).Msg("failed to load config")
+		return nil, err
+	}
+
+	if err := config.Validate(cfg); err != nil {
+		log.Error().Err(err).Msg("invalid configuration")
+		return nil, err
+	}
+
+	return cfg, nil
 }
 
 // Execute runs the root command.
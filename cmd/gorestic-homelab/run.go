@@ -2,11 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/fgeck/gorestic-homelab/internal/models"
 	"github.com/fgeck/gorestic-homelab/internal/services/runner"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -18,24 +22,63 @@ var runCmd = &cobra.Command{
 	Long: `Execute the complete backup workflow:
 1. Wake-on-LAN (if configured)
 2. Initialize restic repository (if needed)
-3. PostgreSQL dump (if configured)
-4. Backup to restic repository
-5. Apply retention policy
-6. Repository check (if enabled)
-7. SSH shutdown (if configured)
-8. Send Telegram notification (if configured)`,
+3. Check for stale locks (fail or auto-remove based on fail_on_locked)
+4. Pre-backup hooks (if configured)
+5. PostgreSQL, MySQL and SQLite dumps (if configured)
+6. Backup to restic repository
+7. Apply retention policy
+8. Prune unreferenced data (if enabled)
+9. Repository check (if enabled)
+10. Post-backup hooks (if configured, always run)
+11. SSH shutdown (if configured)
+12. Send Telegram notification (if configured)`,
 	RunE: runBackup,
 }
 
+var (
+	dryRun      bool
+	lockFile    string
+	metricsFile string
+	tempDir     string
+	summaryJSON bool
+	attachLog   bool
+	force       bool
+)
+
+// runSummaryOutput is the --summary-json representation of a run summary.
+type runSummaryOutput struct {
+	Success          bool    `json:"success"`
+	DryRun           bool    `json:"dry_run"`
+	FailedStep       string  `json:"failed_step,omitempty"`
+	Duration         string  `json:"duration"`
+	DurationSeconds  float64 `json:"duration_seconds"`
+	SnapshotID       string  `json:"snapshot_id,omitempty"`
+	FilesNew         int     `json:"files_new"`
+	DataAdded        int64   `json:"data_added"`
+	SnapshotsKept    int     `json:"snapshots_kept"`
+	SnapshotsRemoved int     `json:"snapshots_removed"`
+	Error            string  `json:"error,omitempty"`
+
+	StepSeconds map[string]float64 `json:"step_seconds,omitempty"`
+}
+
+func init() {
+	runCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show what would be done without modifying the repository or shutting down hosts")
+	runCmd.Flags().StringVar(&lockFile, "lock-file", "", "path of the lock file preventing concurrent runs (default: per-repository file in the temp dir)")
+	runCmd.Flags().StringVar(&tempDir, "temp-dir", "", "parent directory for the per-run directory holding database dumps (default: system temp dir)")
+	runCmd.Flags().StringVar(&metricsFile, "metrics-file", "", "write Prometheus metrics to this file for the node_exporter textfile collector")
+	runCmd.Flags().BoolVar(&summaryJSON, "summary-json", false, "print a JSON summary of the run to stdout (logs go to stderr)")
+	runCmd.Flags().BoolVar(&attachLog, "attach-log", false, "add the last lines of the failed restic command's output to Telegram notifications")
+	runCmd.Flags().BoolVar(&force, "force", false, "back up even if a snapshot younger than backup.min_interval exists")
+}
+
 func runBackup(cmd *cobra.Command, args []string) error {
-	if configFile == "" {
-		log.Error().Msg("config file is required")
-		return cmd.Help()
+	if err := requireConfigSource(cmd); err != nil {
+		return err
 	}
 
 	// Load configuration
-	parser := config.NewParser()
-	cfg, err := parser.LoadFile(configFile)
+	cfg, err := parseConfig()
 	if err != nil {
 		log.Error().Err(err).Str("file", configFile).Msg("failed to load config")
 		return err
@@ -47,6 +90,19 @@ func runBackup(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	cfg.DryRun = dryRun
+	cfg.AttachLog = attachLog
+	cfg.Force = force
+	if lockFile != "" {
+		cfg.LockFile = lockFile
+	}
+	if metricsFile != "" {
+		cfg.MetricsFile = metricsFile
+	}
+	if tempDir != "" {
+		cfg.TempDir = tempDir
+	}
+
 	log.Info().
 		Str("config", configFile).
 		Str("repository", cfg.Restic.Repository).
@@ -67,8 +123,14 @@ func runBackup(cmd *cobra.Command, args []string) error {
 	}()
 
 	// Run backup
-	runnerSvc := runner.New(log.Logger)
-	if err := runnerSvc.Run(ctx, *cfg); err != nil {
+	runnerSvc := runner.New(log.Logger, *cfg)
+	summary, err := runnerSvc.RunWithSummary(ctx, *cfg)
+	if summaryJSON {
+		if writeErr := writeRunSummary(os.Stdout, summary); writeErr != nil {
+			log.Error().Err(writeErr).Msg("failed to write run summary")
+		}
+	}
+	if err != nil {
 		log.Error().Err(err).Msg("backup failed")
 		return err
 	}
@@ -76,3 +138,29 @@ func runBackup(cmd *cobra.Command, args []string) error {
 	log.Info().Msg("backup completed successfully")
 	return nil
 }
+
+// writeRunSummary prints the run summary as a single JSON object.
+func writeRunSummary(out io.Writer, summary *models.RunSummary) error {
+	var stepSeconds map[string]float64
+	if len(summary.StepTimings) > 0 {
+		stepSeconds = make(map[string]float64, len(summary.StepTimings))
+		for step, d := range summary.StepTimings {
+			stepSeconds[step] = d.Seconds()
+		}
+	}
+
+	return json.NewEncoder(out).Encode(runSummaryOutput{
+		Success:          summary.Success,
+		DryRun:           summary.DryRun,
+		FailedStep:       summary.FailedStep,
+		Duration:         summary.Duration.Round(time.Millisecond).String(),
+		DurationSeconds:  summary.Duration.Seconds(),
+		SnapshotID:       summary.SnapshotID,
+		FilesNew:         summary.FilesNew,
+		DataAdded:        summary.DataAdded,
+		SnapshotsKept:    summary.SnapshotsKept,
+		SnapshotsRemoved: summary.SnapshotsRemoved,
+		Error:            summary.ErrorMessage,
+		StepSeconds:      stepSeconds,
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRunSummary_Success(t *testing.T) {
+	summary := &models.RunSummary{
+		Success:          true,
+		Duration:         90 * time.Second,
+		SnapshotID:       "abc123",
+		FilesNew:         10,
+		DataAdded:        2048,
+		SnapshotsKept:    5,
+		SnapshotsRemoved: 2,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeRunSummary(&buf, summary))
+
+	assert.JSONEq(t, `{
+		"success": true,
+		"dry_run": false,
+		"duration": "1m30s",
+		"duration_seconds": 90,
+		"snapshot_id": "abc123",
+		"files_new": 10,
+		"data_added": 2048,
+		"snapshots_kept": 5,
+		"snapshots_removed": 2
+	}`, buf.String())
+}
+
+func TestWriteRunSummary_Failure(t *testing.T) {
+	summary := &models.RunSummary{
+		Success:      false,
+		FailedStep:   "backup",
+		Duration:     1500 * time.Millisecond,
+		ErrorMessage: "backup failed: connection refused",
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeRunSummary(&buf, summary))
+
+	assert.JSONEq(t, `{
+		"success": false,
+		"dry_run": false,
+		"failed_step": "backup",
+		"duration": "1.5s",
+		"duration_seconds": 1.5,
+		"files_new": 0,
+		"data_added": 0,
+		"snapshots_kept": 0,
+		"snapshots_removed": 0,
+		"error": "backup failed: connection refused"
+	}`, buf.String())
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")), "summary is a single line")
+}
+
+func TestWriteRunSummary_StepTimings(t *testing.T) {
+	summary := &models.RunSummary{
+		Success:  true,
+		Duration: 3 * time.Second,
+		StepTimings: map[string]time.Duration{
+			"backup": 2500 * time.Millisecond,
+			"forget": 500 * time.Millisecond,
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeRunSummary(&buf, summary))
+
+	assert.JSONEq(t, `{
+		"success": true,
+		"dry_run": false,
+		"duration": "3s",
+		"duration_seconds": 3,
+		"files_new": 0,
+		"data_added": 0,
+		"snapshots_kept": 0,
+		"snapshots_removed": 0,
+		"step_seconds": {"backup": 2.5, "forget": 0.5}
+	}`, buf.String())
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/services/runner"
+	"github.com/fgeck/gorestic-homelab/internal/services/scheduler"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Run the backup workflow on a schedule",
+	Long: `Stay running and execute the backup workflow on a schedule instead of relying on cron.
+
+Use --cron for a standard five-field cron expression or --every for a fixed interval.
+A run that is still in progress when the next one is due causes that run to be skipped.
+
+On SIGINT/SIGTERM no further runs are started and the command exits once the in-flight
+run has finished. A second signal cancels the in-flight run.`,
+	RunE:         runSchedule,
+	SilenceUsage: true,
+}
+
+var (
+	scheduleCron  string
+	scheduleEvery time.Duration
+)
+
+func init() {
+	scheduleCmd.Flags().StringVar(&scheduleCron, "cron", "", `cron expression, e.g. "0 3 * * *"`)
+	scheduleCmd.Flags().DurationVar(&scheduleEvery, "every", 0, "run at a fixed interval, e.g. 6h")
+	scheduleCmd.MarkFlagsMutuallyExclusive("cron", "every")
+	scheduleCmd.MarkFlagsOneRequired("cron", "every")
+}
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	schedule, err := scheduler.ParseSchedule(scheduleCron, scheduleEvery)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Str("config", configFile).
+		Str("repository", cfg.Restic.Repository).
+		Str("host", cfg.Backup.Host).
+		Msg("configuration loaded")
+
+	// The first signal stops the scheduler, the second cancels the in-flight run
+	scheduleCtx, stopSchedule := context.WithCancel(context.Background())
+	defer stopSchedule()
+	runCtx, cancelRun := context.WithCancel(context.Background())
+	defer cancelRun()
+
+	sigChan := make(chan os.Signal, 2)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+
+	go func() {
+		sig := <-sigChan
+		log.Warn().Str("signal", sig.String()).Msg("received signal, stopping scheduler after the in-flight run")
+		stopSchedule()
+
+		sig = <-sigChan
+		log.Warn().Str("signal", sig.String()).Msg("received second signal, cancelling in-flight run")
+		cancelRun()
+	}()
+
+	runnerSvc := runner.New(log.Logger, *cfg)
+	job := func() error {
+		log.Info().Msg("starting scheduled backup")
+		return runnerSvc.Run(runCtx, *cfg)
+	}
+
+	if err := scheduler.New(log.Logger, schedule, job).Run(scheduleCtx); err != nil {
+		return err
+	}
+
+	log.Info().Msg("scheduler stopped")
+	return nil
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema of the configuration file",
+	Long: `Print a JSON Schema describing the configuration file, for validation and
+autocompletion in editors, e.g. with the VS Code YAML extension:
+
+  gorestic-homelab schema > gorestic-homelab.schema.json
+
+and in the config file:
+
+  # yaml-language-server: $schema=./gorestic-homelab.schema.json
+
+Files that are only pulled in via include may lack keys the schema requires.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return config.WriteSchema(os.Stdout)
+	},
+	SilenceUsage: true,
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var snapshotsCmd = &cobra.Command{
+	Use:   "snapshots",
+	Short: "List repository snapshots",
+	Long: `List the snapshots stored in the configured restic repository.
+
+With --latest only the newest snapshot of each group is listed, grouped by
+--group-by (host and paths by default).`,
+	RunE: listSnapshots,
+}
+
+var (
+	snapshotTags     []string
+	snapshotsNoLock  bool
+	snapshotsLatest  bool
+	snapshotsGroupBy string
+)
+
+func init() {
+	snapshotsCmd.Flags().StringSliceVar(&snapshotTags, "tag", nil, "only list snapshots with this tag (repeatable)")
+	snapshotsCmd.Flags().BoolVar(&snapshotsNoLock, "no-lock", false, "do not lock the repository, e.g. when it is shared")
+	snapshotsCmd.Flags().BoolVar(&snapshotsLatest, "latest", false, "only list the latest snapshot of each group")
+	snapshotsCmd.Flags().StringVar(&snapshotsGroupBy, "group-by", restic.DefaultSnapshotGroupBy, "group --latest snapshots by host, paths and/or tags (comma-separated)")
+	snapshotsCmd.MarkFlagsMutuallyExclusive("latest", "tag")
+}
+
+func listSnapshots(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg.Restic.NoLock = snapshotsNoLock
+	resticSvc := restic.New(log.Logger)
+	if snapshotsLatest {
+		return listLatestSnapshots(cmd, resticSvc, cfg.Restic)
+	}
+
+	snapshots, err := resticSvc.Snapshots(cmd.Context(), cfg.Restic, models.SnapshotFilter{Tags: snapshotTags})
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list snapshots")
+		return err
+	}
+
+	if jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(snapshots)
+	}
+
+	return writeSnapshotsTable(os.Stdout, snapshots)
+}
+
+// listLatestSnapshots prints the latest snapshot of each --group-by group.
+func listLatestSnapshots(cmd *cobra.Command, resticSvc restic.Service, cfg models.ResticConfig) error {
+	groups, err := resticSvc.LatestSnapshots(cmd.Context(), cfg, snapshotsGroupBy)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to list latest snapshots")
+		return err
+	}
+
+	if jsonOutput {
+		return json.NewEncoder(os.Stdout).Encode(groups)
+	}
+
+	return writeSnapshotsTable(os.Stdout, latestSnapshots(groups))
+}
+
+// latestSnapshots flattens snapshot groups into one list, in group order.
+func latestSnapshots(groups []models.SnapshotGroup) []models.Snapshot {
+	var snapshots []models.Snapshot
+	for _, group := range groups {
+		snapshots = append(snapshots, group.Snapshots...)
+	}
+	return snapshots
+}
+
+// writeSnapshotsTable prints snapshots as an aligned table.
+func writeSnapshotsTable(out io.Writer, snapshots []models.Snapshot) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tTIME\tHOST\tTAGS\tPATHS")
+	for _, snap := range snapshots {
+		id := snap.ID
+		if len(id) > 8 {
+			id = id[:8]
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			id,
+			snap.Time.Format("2006-01-02 15:04:05"),
+			snap.Hostname,
+			strings.Join(snap.Tags, ","),
+			strings.Join(snap.Paths, ","),
+		)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSnapshotsTable(t *testing.T) {
+	snapshots := []models.Snapshot{
+		{
+			ID:       "abc123def4567890",
+			Time:     time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			Hostname: "server1",
+			Tags:     []string{"daily", "important"},
+			Paths:    []string{"/data", "/home"},
+		},
+		{
+			ID:       "short",
+			Time:     time.Date(2024, 1, 14, 10, 30, 0, 0, time.UTC),
+			Hostname: "server2",
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeSnapshotsTable(&buf, snapshots)
+
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "ID"))
+	assert.Contains(t, lines[1], "abc123de ")
+	assert.NotContains(t, lines[1], "abc123def4567890")
+	assert.Contains(t, lines[1], "2024-01-15 10:30:00")
+	assert.Contains(t, lines[1], "daily,important")
+	assert.Contains(t, lines[1], "/data,/home")
+	assert.Contains(t, lines[2], "short")
+	assert.Contains(t, lines[2], "server2")
+}
+
+func TestLatestSnapshots(t *testing.T) {
+	groups := []models.SnapshotGroup{
+		{Hostname: "nas", Paths: []string{"/data"}, Snapshots: []models.Snapshot{{ID: "abc123"}}},
+		{Hostname: "pi", Paths: []string{"/etc"}, Snapshots: []models.Snapshot{{ID: "def456"}}},
+	}
+
+	snapshots := latestSnapshots(groups)
+
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "abc123", snapshots[0].ID)
+	assert.Equal(t, "def456", snapshots[1].ID)
+}
+
+func TestWriteSnapshotsTable_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeSnapshotsTable(&buf, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "ID  TIME  HOST  TAGS  PATHS\n", buf.String())
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show repository size statistics",
+	Long: `Run restic stats against the configured repository.
+
+--mode selects what is counted:
+  restore-size       size of all files when every snapshot is restored (default)
+  raw-data           size of the blobs actually stored in the repository
+  files-by-contents  size of the files with unique contents`,
+	RunE:         runStats,
+	SilenceUsage: true, // a failed stats call is not a usage error
+}
+
+var (
+	statsMode   string
+	statsNoLock bool
+)
+
+func init() {
+	statsCmd.Flags().StringVar(&statsMode, "mode", string(models.StatsModeRestoreSize), "counting mode: restore-size, raw-data or files-by-contents")
+	statsCmd.Flags().BoolVar(&statsNoLock, "no-lock", false, "do not lock the repository, e.g. when it is shared")
+}
+
+// statsOutput is the --json representation of a stats result.
+type statsOutput struct {
+	Mode           string `json:"mode"`
+	TotalSize      int64  `json:"total_size"`
+	TotalFileCount int    `json:"total_file_count"`
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	cfg.Restic.NoLock = statsNoLock
+	resticSvc := restic.New(log.Logger)
+	result, err := resticSvc.Stats(cmd.Context(), cfg.Restic, models.StatsMode(statsMode))
+	if err != nil {
+		log.Error().Err(err).Msg("failed to get repository stats")
+		return err
+	}
+
+	return writeStatsResult(os.Stdout, result, jsonOutput)
+}
+
+// writeStatsResult prints the repository statistics as text or JSON.
+func writeStatsResult(out io.Writer, result *models.StatsResult, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(out).Encode(statsOutput{
+			Mode:           string(result.Mode),
+			TotalSize:      result.TotalSize,
+			TotalFileCount: result.TotalFileCount,
+		})
+	}
+
+	_, err := fmt.Fprintf(out, "Mode: %s\nTotal size: %s\nTotal files: %d\n",
+		result.Mode, format.Bytes(result.TotalSize), result.TotalFileCount)
+	return err
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteStatsResult(t *testing.T) {
+	var buf bytes.Buffer
+	result := &models.StatsResult{Mode: models.StatsModeRestoreSize, TotalSize: 1536 * 1024 * 1024, TotalFileCount: 4200}
+	err := writeStatsResult(&buf, result, false)
+
+	require.NoError(t, err)
+	assert.Equal(t, "Mode: restore-size\nTotal size: 1.5 GiB\nTotal files: 4200\n", buf.String())
+}
+
+func TestWriteStatsResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	result := &models.StatsResult{Mode: models.StatsModeRawData, TotalSize: 2048, TotalFileCount: 3}
+	err := writeStatsResult(&buf, result, true)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"mode":"raw-data","total_size":2048,"total_file_count":3}`, buf.String())
+}
+
+func TestWriteStatsResult_JSONKeepsStdoutClean(t *testing.T) {
+	result := &models.StatsResult{Mode: models.StatsModeRestoreSize, TotalSize: 4096, TotalFileCount: 7}
+	stdout, _ := captureJSONOutput(t, func(out io.Writer) error {
+		return writeStatsResult(out, result, jsonOutput)
+	})
+
+	assert.JSONEq(t, `{"mode":"restore-size","total_size":4096,"total_file_count":7}`, stdout)
+}
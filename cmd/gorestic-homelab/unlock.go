@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var unlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Remove stale repository locks",
+	Long: `Remove stale locks left behind by crashed runs.
+
+Locks are always removed, regardless of restic.fail_on_locked.`,
+	RunE:         runUnlock,
+	SilenceUsage: true,
+}
+
+// unlockOutput is the --json representation of an unlock result.
+type unlockOutput struct {
+	LocksRemoved int `json:"locks_removed"`
+}
+
+func runUnlock(cmd *cobra.Command, args []string) error {
+	if err := requireConfigSource(cmd); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	// fail_on_locked guards unattended runs; an explicit unlock always removes locks.
+	resticCfg := cfg.Restic
+	resticCfg.FailOnLocked = false
+
+	resticSvc := restic.New(log.Logger)
+	result, err := resticSvc.Unlock(cmd.Context(), resticCfg)
+	if err != nil {
+		log.Error().Err(err).Msg("failed to unlock repository")
+		return err
+	}
+
+	return writeUnlockResult(os.Stdout, result, jsonOutput)
+}
+
+// writeUnlockResult prints the unlock outcome as text or JSON.
+func writeUnlockResult(out io.Writer, result *models.UnlockResult, asJSON bool) error {
+	if asJSON {
+		return json.NewEncoder(out).Encode(unlockOutput{LocksRemoved: result.LocksRemoved})
+	}
+
+	if result.LocksRemoved == 0 {
+		_, err := fmt.Fprintln(out, "No stale locks found")
+		return err
+	}
+	_, err := fmt.Fprintf(out, "Removed %d stale lock(s)\n", result.LocksRemoved)
+	return err
+}
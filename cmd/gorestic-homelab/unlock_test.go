@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteUnlockResult(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *models.UnlockResult
+		expected string
+	}{
+		{
+			name:     "no locks",
+			result:   &models.UnlockResult{},
+			expected: "No stale locks found\n",
+		},
+		{
+			name:     "locks removed",
+			result:   &models.UnlockResult{LocksRemoved: 2},
+			expected: "Removed 2 stale lock(s)\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := writeUnlockResult(&buf, tt.result, false)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+		})
+	}
+}
+
+func TestWriteUnlockResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeUnlockResult(&buf, &models.UnlockResult{LocksRemoved: 3}, true)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"locks_removed":3}`, buf.String())
+}
+
+func TestWriteUnlockResult_JSONKeepsStdoutClean(t *testing.T) {
+	stdout, _ := captureJSONOutput(t, func(out io.Writer) error {
+		return writeUnlockResult(out, &models.UnlockResult{LocksRemoved: 1}, jsonOutput)
+	})
+
+	assert.JSONEq(t, `{"locks_removed":1}`, stdout)
+}
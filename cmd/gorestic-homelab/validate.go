@@ -2,9 +2,15 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/config"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/fgeck/gorestic-homelab/internal/services/ssh"
+	"github.com/fgeck/gorestic-homelab/internal/services/telegram"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 )
@@ -12,25 +18,45 @@ import (
 var validateCmd = &cobra.Command{
 	Use:   "validate",
 	Short: "Validate configuration file",
-	Long:  `Validate the configuration file without executing any backup operations.`,
-	RunE:  validateConfig,
+	Long: `Validate the configuration file without executing any backup operations.
+
+With --check-connectivity the configured services are contacted as well: the
+repository config is read to check the password, an SSH session is opened, the
+Telegram bot token is checked and a TCP connection to the PostgreSQL host is made.
+Nothing is modified.
+
+With --print-config the fully resolved configuration, after defaults and
+environment variable expansion, is printed as YAML (or JSON with --json)
+instead of the summary. Passwords, tokens, private keys and webhook URLs are
+replaced by ***.`,
+	RunE: validateConfig,
+}
+
+var (
+	validateConnectivity bool
+	validatePrintConfig  bool
+)
+
+func init() {
+	validateCmd.Flags().BoolVar(&validateConnectivity, "check-connectivity", false, "contact the repository, SSH host, Telegram and PostgreSQL without changing anything")
+	validateCmd.Flags().BoolVar(&validatePrintConfig, "print-config", false, "print the resolved config with secrets redacted instead of the summary")
 }
 
 func validateConfig(cmd *cobra.Command, args []string) error {
-	if configFile == "" {
-		log.Error().Msg("config file is required")
-		return cmd.Help()
+	if err := requireConfigSource(cmd); err != nil {
+		return err
 	}
 
 	// Check if file exists
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		log.Error().Str("file", configFile).Msg("config file not found")
-		return fmt.Errorf("config file not found: %s", configFile)
+	if configFile != "" {
+		if _, err := os.Stat(configFile); os.IsNotExist(err) {
+			log.Error().Str("file", configFile).Msg("config file not found")
+			return &config.Error{Err: fmt.Errorf("config file not found: %s", configFile)}
+		}
 	}
 
 	// Load configuration
-	parser := config.NewParser()
-	cfg, err := parser.LoadFile(configFile)
+	cfg, err := parseConfig()
 	if err != nil {
 		log.Error().Err(err).Str("file", configFile).Msg("failed to parse config")
 		return err
@@ -42,31 +68,74 @@ func validateConfig(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if validatePrintConfig {
+		return writeConfig(os.Stdout, *cfg, jsonOutput)
+	}
+
+	// Check the restic binary the config will be used with
+	version, err := restic.New(log.Logger).CheckBinary(cmd.Context())
+	if err != nil {
+		log.Error().Err(err).Msg("restic check failed")
+		return err
+	}
+
 	// Print configuration summary
 	fmt.Println("Configuration is valid!")
+	fmt.Printf("restic version: %s\n", version)
 	fmt.Println()
 	fmt.Println("Summary:")
 	fmt.Printf("  Repository: %s\n", cfg.Restic.Repository)
 	fmt.Printf("  Host: %s\n", cfg.Backup.Host)
 	fmt.Printf("  Paths: %v\n", cfg.Backup.Paths)
 	fmt.Printf("  Tags: %v\n", cfg.Backup.Tags)
+	for i, target := range cfg.Backup.Targets {
+		fmt.Printf("  Target %d: paths %v, tags %v\n", i+1, target.Paths, target.Tags)
+	}
+	if cfg.Backup.Retries > 0 {
+		fmt.Printf("  Retries: %d (first after %s)\n", cfg.Backup.Retries, cfg.Backup.RetryDelay)
+	}
 	fmt.Println()
 	fmt.Println("Retention Policy:")
+	if cfg.Retention.KeepLast > 0 {
+		fmt.Printf("  Keep last: %d\n", cfg.Retention.KeepLast)
+	}
+	if cfg.Retention.KeepHourly > 0 {
+		fmt.Printf("  Keep hourly: %d\n", cfg.Retention.KeepHourly)
+	}
 	fmt.Printf("  Keep daily: %d\n", cfg.Retention.KeepDaily)
 	fmt.Printf("  Keep weekly: %d\n", cfg.Retention.KeepWeekly)
 	fmt.Printf("  Keep monthly: %d\n", cfg.Retention.KeepMonthly)
+	if cfg.Retention.KeepYearly > 0 {
+		fmt.Printf("  Keep yearly: %d\n", cfg.Retention.KeepYearly)
+	}
+	if cfg.Retention.KeepWithin != "" {
+		fmt.Printf("  Keep within: %s\n", cfg.Retention.KeepWithin)
+	}
+	if len(cfg.Retention.KeepTags) > 0 {
+		fmt.Printf("  Keep tags: %v\n", cfg.Retention.KeepTags)
+	}
+	if len(cfg.Retention.Tags) > 0 {
+		fmt.Printf("  Tags: %v\n", cfg.Retention.Tags)
+	}
+	if cfg.Retention.GroupBy != "" {
+		fmt.Printf("  Group by: %s\n", cfg.Retention.GroupBy)
+	}
 	fmt.Println()
 	fmt.Println("Optional Features:")
+	fmt.Printf("  Wait for network: %v\n", cfg.Network != nil)
 	fmt.Printf("  Wake-on-LAN: %v\n", cfg.WOL != nil)
 	fmt.Printf("  PostgreSQL: %v\n", cfg.Postgres != nil)
+	fmt.Printf("  MySQL: %v\n", cfg.MySQL != nil)
+	fmt.Printf("  SQLite: %v\n", cfg.SQLite != nil)
 	fmt.Printf("  SSH Shutdown: %v\n", cfg.SSHShutdown != nil)
 	fmt.Printf("  Telegram: %v\n", cfg.Telegram != nil)
 	fmt.Printf("  Repository Check: %v\n", cfg.Check.Enabled)
+	fmt.Printf("  Copy To: %v\n", cfg.CopyTo != nil && cfg.CopyTo.Enabled)
 
 	if cfg.WOL != nil {
 		fmt.Println()
 		fmt.Println("WOL Configuration:")
-		fmt.Printf("  MAC Address: %s\n", cfg.WOL.MACAddress)
+		fmt.Printf("  MAC Addresses: %s\n", strings.Join(cfg.WOL.MACs(), ", "))
 		fmt.Printf("  Broadcast IP: %s\n", cfg.WOL.BroadcastIP)
 		if cfg.WOL.PollURL != "" {
 			fmt.Printf("  Poll URL: %s\n", cfg.WOL.PollURL)
@@ -78,8 +147,31 @@ func validateConfig(cmd *cobra.Command, args []string) error {
 		fmt.Println("PostgreSQL Configuration:")
 		fmt.Printf("  Host: %s\n", cfg.Postgres.Host)
 		fmt.Printf("  Port: %d\n", cfg.Postgres.Port)
-		fmt.Printf("  Database: %s\n", cfg.Postgres.Database)
+		fmt.Printf("  Databases: %s\n", strings.Join(cfg.Postgres.DatabaseNames(), ", "))
 		fmt.Printf("  Format: %s\n", cfg.Postgres.Format)
+		if cfg.Postgres.KeepLocal > 0 {
+			fmt.Printf("  Keep Local: %d in %s\n", cfg.Postgres.KeepLocal, cfg.Postgres.LocalDir)
+		}
+		if cfg.Postgres.MinVersion > 0 {
+			fmt.Printf("  Min pg_dump Version: %d\n", cfg.Postgres.MinVersion)
+		}
+	}
+
+	if cfg.MySQL != nil {
+		fmt.Println()
+		fmt.Println("MySQL Configuration:")
+		fmt.Printf("  Host: %s\n", cfg.MySQL.Host)
+		fmt.Printf("  Port: %d\n", cfg.MySQL.Port)
+		fmt.Printf("  Database: %s\n", cfg.MySQL.Database)
+	}
+
+	if cfg.SQLite != nil {
+		fmt.Println()
+		fmt.Println("SQLite Configuration:")
+		fmt.Printf("  Databases: %s\n", strings.Join(cfg.SQLite.Databases, ", "))
+		if cfg.SQLite.OutputDir != "" {
+			fmt.Printf("  Output Dir: %s\n", cfg.SQLite.OutputDir)
+		}
 	}
 
 	if cfg.SSHShutdown != nil {
@@ -90,12 +182,24 @@ func validateConfig(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Username: %s\n", cfg.SSHShutdown.Username)
 		fmt.Printf("  OS: %s\n", cfg.SSHShutdown.OS)
 		fmt.Printf("  Shutdown Delay: %d minute(s)\n", cfg.SSHShutdown.ShutdownDelay)
+		fmt.Printf("  Action: %s\n", cfg.SSHShutdown.Action)
+		if cfg.SSHShutdown.VerifyDown {
+			fmt.Printf("  Verify Down: within %s\n", cfg.SSHShutdown.VerifyTimeout)
+		}
+		if cfg.SSHShutdown.ShutdownCommand != "" {
+			fmt.Printf("  Command: %s\n", cfg.SSHShutdown.ShutdownCommand)
+		}
+		if cfg.SSHShutdown.StrictHostKeyChecking {
+			fmt.Printf("  Known Hosts: %s\n", cfg.SSHShutdown.KnownHostsPath)
+		} else {
+			fmt.Println("  Host Key Checking: disabled")
+		}
 	}
 
 	if cfg.Telegram != nil {
 		fmt.Println()
 		fmt.Println("Telegram Configuration:")
-		fmt.Printf("  Chat ID: %s\n", cfg.Telegram.ChatID)
+		fmt.Printf("  Chat IDs: %s\n", strings.Join(cfg.Telegram.ChatIDs, ", "))
 		fmt.Printf("  Bot Token: (configured)\n")
 	}
 
@@ -105,5 +209,26 @@ func validateConfig(cmd *cobra.Command, args []string) error {
 		fmt.Printf("  Subset: %s\n", cfg.Check.Subset)
 	}
 
+	if cfg.CopyTo != nil && cfg.CopyTo.Enabled {
+		fmt.Println()
+		fmt.Println("Copy Configuration:")
+		fmt.Printf("  Repository: %s\n", cfg.CopyTo.Repository)
+	}
+
+	if validateConnectivity {
+		fmt.Println()
+		var telegramTimeout time.Duration
+		if cfg.Telegram != nil {
+			telegramTimeout = cfg.Telegram.HTTPTimeout
+		}
+		results := checkConnectivity(cmd.Context(), cfg, connectivityServices{
+			restic:   restic.New(log.Logger),
+			ssh:      ssh.New(log.Logger),
+			telegram: telegram.New(log.Logger, telegramTimeout),
+			dial:     (&net.Dialer{}).DialContext,
+		})
+		return writeConnectivityResults(os.Stdout, results)
+	}
+
 	return nil
 }
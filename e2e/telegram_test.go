@@ -29,14 +29,14 @@ func getTelegramConfig(t *testing.T) models.TelegramConfig {
 
 	return models.TelegramConfig{
 		BotToken: botToken,
-		ChatID:   chatID,
+		ChatIDs:  []string{chatID},
 	}
 }
 
 func TestTelegramSendSuccessNotification_E2E(t *testing.T) {
 	cfg := getTelegramConfig(t)
 
-	svc := telegram.New(testLogger())
+	svc := telegram.New(testLogger(), 0)
 
 	msg := models.TelegramMessage{
 		Success:          true,
@@ -65,7 +65,7 @@ func TestTelegramSendSuccessNotification_E2E(t *testing.T) {
 func TestTelegramSendFailureNotification_E2E(t *testing.T) {
 	cfg := getTelegramConfig(t)
 
-	svc := telegram.New(testLogger())
+	svc := telegram.New(testLogger(), 0)
 
 	msg := models.TelegramMessage{
 		Success:      false,
@@ -87,10 +87,10 @@ func TestTelegramSendFailureNotification_E2E(t *testing.T) {
 func TestTelegramInvalidToken_E2E(t *testing.T) {
 	cfg := models.TelegramConfig{
 		BotToken: "invalid:token",
-		ChatID:   "-100123456789",
+		ChatIDs:  []string{"-100123456789"},
 	}
 
-	svc := telegram.New(testLogger())
+	svc := telegram.New(testLogger(), 0)
 
 	msg := models.TelegramMessage{
 		Success: true,
@@ -112,10 +112,10 @@ func TestTelegramInvalidChatID_E2E(t *testing.T) {
 
 	cfg := models.TelegramConfig{
 		BotToken: botToken,
-		ChatID:   "invalid-chat-id",
+		ChatIDs:  []string{"invalid-chat-id"},
 	}
 
-	svc := telegram.New(testLogger())
+	svc := telegram.New(testLogger(), 0)
 
 	msg := models.TelegramMessage{
 		Success: true,
@@ -123,7 +123,7 @@ func TestWOL_TargetNeverReady_E2E(t *testing.T) {
 // Mock implementations for E2E tests
 type mockWOLClient struct{}
 
-func (m *mockWOLClient) Wake(broadcastIP string, mac net.HardwareAddr) error {
+func (m *mockWOLClient) Wake(addr string, mac net.HardwareAddr) error {
 	return nil
 }
 
@@ -136,7 +136,7 @@ func TestRealWOL_E2E(t *testing.T) {
 
 	targetURL := os.Getenv("TEST_WOL_TARGET_URL")
 
-	svc := wol.New(testLogger())
+	svc := wol.New(testLogger(), 0)
 
 	cfg := models.WOLConfig{
 		MACAddress:    mac,
@@ -43,7 +43,7 @@ func TestResticInit_Integration(t *testing.T) {
 	cfg := getResticConfig(t)
 
 	svc := restic.New(testLogger())
-	err := svc.Init(context.Background(), cfg)
+	_, err := svc.Init(context.Background(), cfg)
 
 	require.NoError(t, err)
 }
@@ -54,11 +54,11 @@ func TestResticUnlock_NoLocks_Integration(t *testing.T) {
 	svc := restic.New(testLogger())
 
 	// Initialize repository first
-	err := svc.Init(context.Background(), cfg)
+	_, err := svc.Init(context.Background(), cfg)
 	require.NoError(t, err)
 
 	// Unlock should succeed even when no locks exist
-	err = svc.Unlock(context.Background(), cfg)
+	_, err = svc.Unlock(context.Background(), cfg)
 	require.NoError(t, err)
 }
 
@@ -74,11 +74,11 @@ func TestResticUnlock_BeforeBackup_Integration(t *testing.T) {
 	svc := restic.New(testLogger())
 
 	// Initialize repository first
-	err = svc.Init(context.Background(), cfg)
+	_, err = svc.Init(context.Background(), cfg)
 	require.NoError(t, err)
 
 	// Unlock before backup (simulating the workflow)
-	err = svc.Unlock(context.Background(), cfg)
+	_, err = svc.Unlock(context.Background(), cfg)
 	require.NoError(t, err)
 
 	// Backup should succeed after unlock
@@ -88,7 +88,7 @@ func TestResticUnlock_BeforeBackup_Integration(t *testing.T) {
 		Host:  "test-host",
 	}
 
-	result, err := svc.Backup(context.Background(), cfg, backupSettings)
+	result, err := svc.Backup(context.Background(), cfg, backupSettings, nil)
 	require.NoError(t, err)
 	assert.NotEmpty(t, result.SnapshotID)
 	assert.Nil(t, result.Error)
@@ -106,7 +106,7 @@ func TestResticBackupAndSnapshots_Integration(t *testing.T) {
 	svc := restic.New(testLogger())
 
 	// Initialize repository first
-	err = svc.Init(context.Background(), cfg)
+	_, err = svc.Init(context.Background(), cfg)
 	require.NoError(t, err)
 
 	// Perform backup
@@ -116,14 +116,14 @@ func TestResticBackupAndSnapshots_Integration(t *testing.T) {
 		Host:  "test-host",
 	}
 
-	result, err := svc.Backup(context.Background(), cfg, backupSettings)
+	result, err := svc.Backup(context.Background(), cfg, backupSettings, nil)
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, result.SnapshotID)
 	assert.Nil(t, result.Error)
 
 	// List snapshots
-	snapshots, err := svc.Snapshots(context.Background(), cfg)
+	snapshots, err := svc.Snapshots(context.Background(), cfg, models.SnapshotFilter{})
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, snapshots)
@@ -152,7 +152,7 @@ func TestResticForget_Integration(t *testing.T) {
 
 	svc := restic.New(testLogger())
 
-	err = svc.Init(context.Background(), cfg)
+	_, err = svc.Init(context.Background(), cfg)
 	require.NoError(t, err)
 
 	// Create multiple snapshots
@@ -162,7 +162,7 @@ func TestResticForget_Integration(t *testing.T) {
 			Tags:  []string{"forget-test"},
 			Host:  "test-host",
 		}
-		_, err := svc.Backup(context.Background(), cfg, settings)
+		_, err := svc.Backup(context.Background(), cfg, settings, nil)
 		require.NoError(t, err)
 	}
 
@@ -183,7 +183,7 @@ func TestResticCheck_Integration(t *testing.T) {
 
 	svc := restic.New(testLogger())
 
-	err := svc.Init(context.Background(), cfg)
+	_, err := svc.Init(context.Background(), cfg)
 	require.NoError(t, err)
 
 	settings := models.CheckSettings{
@@ -217,7 +217,7 @@ func TestResticBackupProgress_Integration(t *testing.T) {
 	svc := restic.New(debugLogger)
 
 	// Initialize repository first
-	err := svc.Init(context.Background(), cfg)
+	_, err := svc.Init(context.Background(), cfg)
 	require.NoError(t, err)
 
 	// Perform backup - should show progress messages
@@ -229,7 +229,7 @@ func TestResticBackupProgress_Integration(t *testing.T) {
 
 	t.Log("Starting backup with progress logging enabled (debug level)...")
 	t.Log("Progress logged on: new percentage OR every 30 seconds")
-	result, err := svc.Backup(context.Background(), cfg, backupSettings)
+	result, err := svc.Backup(context.Background(), cfg, backupSettings, nil)
 
 	require.NoError(t, err)
 	assert.NotEmpty(t, result.SnapshotID)
@@ -0,0 +1,51 @@
+package config
+
+import "time"
+
+// Default values applied by the parser when a setting is omitted.
+// The generated config template documents the same values.
+const (
+	DefaultKeepDaily   = 7
+	DefaultKeepWeekly  = 4
+	DefaultKeepMonthly = 6
+
+	DefaultWOLBroadcastIP   = "255.255.255.255"
+	DefaultWOLPort          = 9
+	DefaultWOLTimeout       = 5 * time.Minute
+	DefaultWOLPollInterval  = 10 * time.Second
+	DefaultWOLStabilizeWait = 10 * time.Second
+	DefaultWOLPacketCount   = 1
+	DefaultWOLHTTPTimeout   = 5 * time.Second
+
+	DefaultBackupRetryDelay = 30 * time.Second
+
+	DefaultNetworkTimeout      = time.Minute
+	DefaultNetworkPollInterval = 5 * time.Second
+
+	DefaultPostgresHost     = "localhost"
+	DefaultPostgresPort     = 5432
+	DefaultPostgresUsername = "postgres"
+	DefaultPostgresFormat   = "custom"
+
+	DefaultMySQLHost     = "localhost"
+	DefaultMySQLPort     = 3306
+	DefaultMySQLUsername = "root"
+
+	DefaultSSHPort           = 22
+	DefaultSSHUsername       = "root"
+	DefaultSSHShutdownDelay  = 1
+	DefaultSSHOS             = "linux"
+	DefaultSSHVerifyInterval = 10 * time.Second
+
+	DefaultTelegramParseMode   = "HTML"
+	DefaultTelegramMaxRetries  = 3
+	DefaultTelegramHTTPTimeout = 30 * time.Second
+
+	DefaultPushoverPriority = 1
+
+	DefaultWebhookMethod = "POST"
+
+	DefaultNtfyServer = "https://ntfy.sh"
+
+	DefaultEmailSMTPPort = 587
+)
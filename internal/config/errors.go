@@ -0,0 +1,15 @@
+package config
+
+// Error is returned for a configuration that cannot be read or is invalid,
+// so callers can tell configuration problems apart from runtime failures.
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
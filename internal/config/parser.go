@@ -2,8 +2,15 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -11,36 +18,117 @@ import (
 	"github.com/spf13/viper"
 )
 
+// backendEnvKeys maps typed backend credential keys, below restic or copy_to,
+// to restic environment variables.
+var backendEnvKeys = map[string]string{
+	"s3.access_key_id":     "AWS_ACCESS_KEY_ID",
+	"s3.secret_access_key": "AWS_SECRET_ACCESS_KEY",
+	"s3.region":            "AWS_DEFAULT_REGION",
+	"b2.account_id":        "B2_ACCOUNT_ID",
+	"b2.account_key":       "B2_ACCOUNT_KEY",
+	"azure.account_name":   "AZURE_ACCOUNT_NAME",
+	"azure.account_key":    "AZURE_ACCOUNT_KEY",
+}
+
+// EnvPrefix is the prefix of environment variables overriding config keys,
+// e.g. GORESTIC_RESTIC_REPOSITORY for restic.repository.
+const EnvPrefix = "GORESTIC"
+
+// HostnameEnv overrides the system hostname used when backup.host is not set.
+const HostnameEnv = "GORESTIC_HOSTNAME"
+
+// knownRepositorySchemes are the restic backend prefixes accepted without a warning.
+var knownRepositorySchemes = []string{"local:", "rest:", "s3:", "b2:", "sftp:", "rclone:", "azure:", "gs:", "swift:"}
+
 // Parser handles configuration file parsing.
 type Parser struct {
-	v *viper.Viper
+	v        *viper.Viper
+	warnings []string
 }
 
 // NewParser creates a new configuration parser.
 func NewParser() *Parser {
 	v := viper.New()
 	v.SetConfigType("yaml")
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
 	return &Parser{v: v}
 }
 
-// LoadFile loads configuration from a file path.
+// LoadFile loads configuration from a file path, together with the files
+// listed under its include key.
 func (p *Parser) LoadFile(path string) (*models.BackupConfig, error) {
-	p.v.SetConfigFile(path)
+	if err := p.mergeFile(path, nil); err != nil {
+		return nil, &Error{Err: err}
+	}
+
+	return p.load()
+}
 
-	if err := p.v.ReadInConfig(); err != nil {
-		return nil, fmt.Errorf("reading config file: %w", err)
+// mergeFile deep-merges the files included by path, then path itself, into the
+// configuration, so later files win. Relative includes are resolved against the
+// including file. stack holds the files currently being included.
+func (p *Parser) mergeFile(path string, stack []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	if slices.Contains(stack, abs) {
+		return fmt.Errorf("include cycle: %s", strings.Join(append(stack, abs), " -> "))
 	}
+	stack = append(stack, abs)
 
-	return p.parse()
+	fv := viper.New()
+	fv.SetConfigType("yaml")
+	fv.SetConfigFile(abs)
+	if err := fv.ReadInConfig(); err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	for _, include := range fv.GetStringSlice("include") {
+		include = p.expandEnv(include)
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(abs), include)
+		}
+		if err := p.mergeFile(include, stack); err != nil {
+			return err
+		}
+	}
+
+	if err := p.v.MergeConfigMap(fv.AllSettings()); err != nil {
+		return fmt.Errorf("merging config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// LoadEnv loads configuration from GORESTIC_* environment variables only.
+func (p *Parser) LoadEnv() (*models.BackupConfig, error) {
+	return p.load()
+}
+
+// Warnings returns non-fatal problems found while parsing, e.g. an unknown
+// repository scheme.
+func (p *Parser) Warnings() []string {
+	return p.warnings
 }
 
 // LoadReader loads configuration from a reader (useful for testing).
 func (p *Parser) LoadReader(content string) (*models.BackupConfig, error) {
 	if err := p.v.ReadConfig(strings.NewReader(content)); err != nil {
-		return nil, fmt.Errorf("reading config: %w", err)
+		return nil, &Error{Err: fmt.Errorf("reading config: %w", err)}
 	}
 
-	return p.parse()
+	return p.load()
+}
+
+// load parses the merged configuration, marking failures as *Error.
+func (p *Parser) load() (*models.BackupConfig, error) {
+	cfg, err := p.parse()
+	if err != nil {
+		return nil, &Error{Err: err}
+	}
+	return cfg, nil
 }
 
 //nolint:gocognit,gocyclo // parsing config requires checking many fields
@@ -50,7 +138,7 @@ func (p *Parser) parse() (*models.BackupConfig, error) {
 	// Parse restic config (required).
 	// Default fail_on_locked to true if not explicitly set
 	failOnLocked := true
-	if p.v.IsSet("restic.fail_on_locked") {
+	if p.isSet("restic.fail_on_locked") {
 		failOnLocked = p.v.GetBool("restic.fail_on_locked")
 	}
 
@@ -60,122 +148,493 @@ func (p *Parser) parse() (*models.BackupConfig, error) {
 		RestUser:     p.expandEnv(p.v.GetString("restic.rest_user")),
 		RestPassword: p.expandEnv(p.v.GetString("restic.rest_password")),
 		FailOnLocked: failOnLocked,
+		CacheDir:     p.expandEnv(p.v.GetString("restic.cache_dir")),
+		NoCache:      p.v.GetBool("restic.no_cache"),
+
+		InsecureTLS: p.v.GetBool("restic.insecure_tls"),
+		CACertPath:  p.expandEnv(p.v.GetString("restic.cacert")),
+
+		RcloneConfigPath: p.expandEnv(p.v.GetString("restic.rclone_config")),
+		RcloneArgs:       p.v.GetString("restic.rclone_args"),
+
+		PackSize:         p.v.GetInt("restic.pack_size"),
+		ReadConcurrency:  p.v.GetInt("restic.read_concurrency"),
+		CompressionLevel: p.v.GetString("restic.compression"),
 	}
 
 	if cfg.Restic.Repository == "" {
 		return nil, fmt.Errorf("restic.repository is required")
 	}
+	if err := p.checkRepository("restic.repository", cfg.Restic.Repository); err != nil {
+		return nil, err
+	}
 	if cfg.Restic.Password == "" {
 		return nil, fmt.Errorf("restic.password is required")
 	}
+	if cfg.Restic.CacheDir != "" && cfg.Restic.NoCache {
+		return nil, fmt.Errorf("restic.cache_dir and restic.no_cache are mutually exclusive")
+	}
+	if cfg.Restic.CACertPath != "" {
+		if _, err := os.Stat(cfg.Restic.CACertPath); err != nil {
+			return nil, fmt.Errorf("restic.cacert %q is not accessible: %w", cfg.Restic.CACertPath, err)
+		}
+	}
+	if (cfg.Restic.RcloneConfigPath != "" || cfg.Restic.RcloneArgs != "") && !strings.HasPrefix(cfg.Restic.Repository, "rclone:") {
+		return nil, fmt.Errorf("restic.rclone_config and restic.rclone_args require an rclone: repository")
+	}
+	if cfg.Restic.RcloneConfigPath != "" {
+		if _, err := os.Stat(cfg.Restic.RcloneConfigPath); err != nil {
+			return nil, fmt.Errorf("restic.rclone_config %q is not accessible: %w", cfg.Restic.RcloneConfigPath, err)
+		}
+	}
+	if cfg.Restic.PackSize != 0 && (cfg.Restic.PackSize < 4 || cfg.Restic.PackSize > 128) {
+		return nil, fmt.Errorf("restic.pack_size must be between 4 and 128 MiB")
+	}
+	if cfg.Restic.ReadConcurrency < 0 {
+		return nil, fmt.Errorf("restic.read_concurrency must not be negative")
+	}
+	validCompression := map[string]bool{"": true, "auto": true, "off": true, "max": true}
+	if !validCompression[cfg.Restic.CompressionLevel] {
+		return nil, fmt.Errorf("restic.compression must be one of: auto, off, max")
+	}
+
+	cfg.Restic.EnvVars = p.parseResticEnv("restic")
+
+	// Default the lock file to one per repository.
+	cfg.LockFile = p.expandEnv(p.v.GetString("lock_file"))
+	if cfg.LockFile == "" {
+		cfg.LockFile = DefaultLockFile(cfg.Restic.Repository)
+	}
 
 	// Parse backup settings (required).
+	// Default require_paths_exist to true if not explicitly set
+	requirePathsExist := true
+	if p.isSet("backup.require_paths_exist") {
+		requirePathsExist = p.v.GetBool("backup.require_paths_exist")
+	}
+
 	cfg.Backup = models.BackupSettings{
-		Paths: p.v.GetStringSlice("backup.paths"),
-		Tags:  p.v.GetStringSlice("backup.tags"),
-		Host:  p.v.GetString("backup.host"),
+		Paths:         p.expandEnvSlice(p.v.GetStringSlice("backup.paths")),
+		Tags:          p.expandEnvSlice(p.v.GetStringSlice("backup.tags")),
+		Host:          p.expandEnv(p.v.GetString("backup.host")),
+		Excludes:      p.expandEnvSlice(p.v.GetStringSlice("backup.excludes")),
+		ExcludeCaches: p.v.GetBool("backup.exclude_caches"),
+		ExcludeFile:   p.expandEnv(p.v.GetString("backup.exclude_file")),
+		FilesFrom:     p.expandEnv(p.v.GetString("backup.files_from")),
+
+		ExcludeIfPresent: p.expandEnvSlice(p.v.GetStringSlice("backup.exclude_if_present")),
+		OneFileSystem:    p.v.GetBool("backup.one_file_system"),
+
+		RequirePathsExist: requirePathsExist,
+		RequireNonEmpty:   p.v.GetBool("backup.require_non_empty"),
+
+		MinDataAdded:      p.v.GetInt64("backup.min_data_added"),
+		MinFilesProcessed: p.v.GetInt("backup.min_files_processed"),
+
+		MinInterval: p.v.GetDuration("backup.min_interval"),
+
+		Retries:    p.v.GetInt("backup.retries"),
+		RetryDelay: p.v.GetDuration("backup.retry_delay"),
+
+		ProgressFile: p.expandEnv(p.v.GetString("backup.progress_file")),
 	}
+	if cfg.Backup.RetryDelay == 0 {
+		cfg.Backup.RetryDelay = DefaultBackupRetryDelay
+	}
+
+	targets, err := p.parseBackupTargets()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Backup.Targets = targets
 
-	if len(cfg.Backup.Paths) == 0 {
-		return nil, fmt.Errorf("backup.paths is required")
+	if cfg.Backup.MinDataAdded < 0 {
+		return nil, fmt.Errorf("backup.min_data_added must not be negative")
+	}
+	if cfg.Backup.MinFilesProcessed < 0 {
+		return nil, fmt.Errorf("backup.min_files_processed must not be negative")
+	}
+	if cfg.Backup.MinInterval < 0 {
+		return nil, fmt.Errorf("backup.min_interval must not be negative")
+	}
+	if cfg.Backup.Retries < 0 {
+		return nil, fmt.Errorf("backup.retries must not be negative")
+	}
+	if len(cfg.Backup.Paths) == 0 && cfg.Backup.FilesFrom == "" && len(cfg.Backup.Targets) == 0 {
+		return nil, fmt.Errorf("backup.paths is required unless backup.targets is set")
+	}
+	if cfg.Backup.ExcludeFile != "" {
+		if _, err := os.Stat(cfg.Backup.ExcludeFile); err != nil {
+			return nil, fmt.Errorf("backup.exclude_file %q is not accessible: %w", cfg.Backup.ExcludeFile, err)
+		}
+	}
+	if cfg.Backup.FilesFrom != "" {
+		if _, err := os.Stat(cfg.Backup.FilesFrom); err != nil {
+			return nil, fmt.Errorf("backup.files_from %q is not accessible: %w", cfg.Backup.FilesFrom, err)
+		}
 	}
 
 	// Set default host if not specified.
 	if cfg.Backup.Host == "" {
-		hostname, err := os.Hostname()
-		if err != nil {
-			cfg.Backup.Host = "unknown"
-		} else {
-			cfg.Backup.Host = hostname
-		}
+		cfg.Backup.Host = defaultHost()
+	}
+	if suffix := p.expandEnv(p.v.GetString("backup.host_suffix")); suffix != "" {
+		cfg.Backup.Host += strings.ReplaceAll(suffix, "{hostname}", systemHostname())
 	}
 
 	// Parse retention policy.
 	cfg.Retention = models.RetentionPolicy{
+		KeepLast:    p.v.GetInt("retention.keep_last"),
+		KeepHourly:  p.v.GetInt("retention.keep_hourly"),
 		KeepDaily:   p.v.GetInt("retention.keep_daily"),
 		KeepWeekly:  p.v.GetInt("retention.keep_weekly"),
 		KeepMonthly: p.v.GetInt("retention.keep_monthly"),
+		KeepYearly:  p.v.GetInt("retention.keep_yearly"),
+		KeepWithin:  p.v.GetString("retention.keep_within"),
+		KeepTags:    p.v.GetStringSlice("retention.keep_tags"),
+		Prune: models.PruneSettings{
+			Enabled:   p.v.GetBool("retention.prune.enabled"),
+			MaxUnused: p.v.GetString("retention.prune.max_unused"),
+		},
+		Tags:    p.v.GetStringSlice("retention.tags"),
+		GroupBy: p.v.GetString("retention.group_by"),
+
+		SkipWhenUnchanged: p.v.GetBool("retention.skip_when_unchanged"),
+	}
+	if cfg.Retention.GroupBy != "" {
+		validGroupBy := map[string]bool{"host": true, "paths": true, "tags": true}
+		for _, field := range strings.Split(cfg.Retention.GroupBy, ",") {
+			if !validGroupBy[strings.TrimSpace(field)] {
+				return nil, fmt.Errorf("retention.group_by must be a comma-separated list of: host, paths, tags")
+			}
+		}
 	}
 
 	// Set defaults if no retention policy specified.
-	if cfg.Retention.KeepDaily == 0 && cfg.Retention.KeepWeekly == 0 && cfg.Retention.KeepMonthly == 0 {
-		cfg.Retention.KeepDaily = 7
-		cfg.Retention.KeepWeekly = 4
-		cfg.Retention.KeepMonthly = 6
+	if cfg.Retention.IsEmpty() {
+		cfg.Retention.KeepDaily = DefaultKeepDaily
+		cfg.Retention.KeepWeekly = DefaultKeepWeekly
+		cfg.Retention.KeepMonthly = DefaultKeepMonthly
+	}
+
+	cfg.MetricsFile = p.expandEnv(p.v.GetString("metrics_file"))
+	cfg.TempDir = p.expandEnv(p.v.GetString("temp_dir"))
+
+	// Parse notification filter, applied to all notifiers.
+	cfg.NotifyOn = p.v.GetString("notify.on")
+	if cfg.NotifyOn == "" {
+		cfg.NotifyOn = models.NotifyAlways
+	}
+	validNotifyOn := map[string]bool{models.NotifyAlways: true, models.NotifyFailure: true, models.NotifySuccess: true}
+	if !validNotifyOn[cfg.NotifyOn] {
+		return nil, fmt.Errorf("notify.on must be one of: always, failure, success")
 	}
+	cfg.NotifyBeforeShutdown = p.v.GetBool("notify.before_shutdown")
+
+	// Parse log output, applied once the config is loaded.
+	cfg.LogOutput = p.v.GetString("log.output")
+	if cfg.LogOutput == "" {
+		cfg.LogOutput = models.LogOutputStdout
+	}
+	if cfg.LogOutput != models.LogOutputStdout && cfg.LogOutput != models.LogOutputSyslog {
+		return nil, fmt.Errorf("log.output must be one of: stdout, syslog")
+	}
+
+	// Parse hooks.
+	cfg.PreHooks = p.v.GetStringSlice("hooks.pre")
+	cfg.PostHooks = p.v.GetStringSlice("hooks.post")
 
 	// Parse check settings.
 	cfg.Check = models.CheckSettings{
 		Enabled: p.v.GetBool("check.enabled"),
 		Subset:  p.v.GetString("check.subset"),
+
+		ReadData: p.v.GetBool("check.read_data"),
+	}
+
+	// Parse optional copy destination. Enabled defaults to true when configured.
+	if p.isSet("copy_to") {
+		enabled := true
+		if p.isSet("copy_to.enabled") {
+			enabled = p.v.GetBool("copy_to.enabled")
+		}
+
+		cfg.CopyTo = &models.CopyToConfig{
+			Enabled: enabled,
+			ResticConfig: models.ResticConfig{
+				Repository:   p.expandEnv(p.v.GetString("copy_to.repository")),
+				Password:     p.expandEnv(p.v.GetString("copy_to.password")),
+				RestUser:     p.expandEnv(p.v.GetString("copy_to.rest_user")),
+				RestPassword: p.expandEnv(p.v.GetString("copy_to.rest_password")),
+				EnvVars:      p.parseResticEnv("copy_to"),
+			},
+		}
+
+		if cfg.CopyTo.Enabled {
+			if cfg.CopyTo.Repository == "" {
+				return nil, fmt.Errorf("copy_to.repository is required when copy_to is enabled")
+			}
+			if err := p.checkRepository("copy_to.repository", cfg.CopyTo.Repository); err != nil {
+				return nil, err
+			}
+			if cfg.CopyTo.Password == "" {
+				return nil, fmt.Errorf("copy_to.password is required when copy_to is enabled")
+			}
+			if cfg.CopyTo.Repository == cfg.Restic.Repository {
+				return nil, fmt.Errorf("copy_to.repository must differ from restic.repository")
+			}
+		}
+	}
+
+	// Parse optional network readiness check.
+	if p.isSet("network") {
+		address, err := networkAddress(p.expandEnv(p.v.GetString("network.wait")))
+		if err != nil {
+			return nil, err
+		}
+		cfg.Network = &models.NetworkConfig{
+			Address:      address,
+			Timeout:      p.v.GetDuration("network.timeout"),
+			PollInterval: p.v.GetDuration("network.poll_interval"),
+		}
+		if cfg.Network.Timeout == 0 {
+			cfg.Network.Timeout = DefaultNetworkTimeout
+		}
+		if cfg.Network.PollInterval == 0 {
+			cfg.Network.PollInterval = DefaultNetworkPollInterval
+		}
 	}
 
 	// Parse optional WOL config.
-	if p.v.IsSet("wol") { //nolint:nestif // config parsing with defaults
+	if p.isSet("wol") { //nolint:nestif // config parsing with defaults
 		cfg.WOL = &models.WOLConfig{
 			MACAddress:    p.expandEnv(p.v.GetString("wol.mac_address")),
 			BroadcastIP:   p.expandEnv(p.v.GetString("wol.broadcast_ip")),
+			Port:          p.v.GetInt("wol.port"),
 			PollURL:       p.expandEnv(p.v.GetString("wol.poll_url")),
 			Timeout:       p.v.GetDuration("wol.timeout"),
 			PollInterval:  p.v.GetDuration("wol.poll_interval"),
+			HTTPTimeout:   p.v.GetDuration("wol.http_timeout"),
 			StabilizeWait: p.v.GetDuration("wol.stabilize_wait"),
+			ExpectStatus:  p.v.GetInt("wol.expect_status"),
+
+			PacketCount:    p.v.GetInt("wol.packet_count"),
+			PacketInterval: p.v.GetDuration("wol.packet_interval"),
 		}
 
-		if cfg.WOL.MACAddress == "" {
-			return nil, fmt.Errorf("wol.mac_address is required when wol is configured")
+		if cfg.WOL.MACAddress != "" {
+			if _, err := net.ParseMAC(cfg.WOL.MACAddress); err != nil {
+				return nil, fmt.Errorf("wol.mac_address is invalid: %q", cfg.WOL.MACAddress)
+			}
+		}
+		// A single MAC address is merged into the MAC address list.
+		for i, mac := range p.v.GetStringSlice("wol.mac_addresses") {
+			if mac = p.expandEnv(mac); mac != "" && mac != cfg.WOL.MACAddress {
+				if _, err := net.ParseMAC(mac); err != nil {
+					return nil, fmt.Errorf("wol.mac_addresses[%d] is invalid: %q", i, mac)
+				}
+				cfg.WOL.MACAddresses = append(cfg.WOL.MACAddresses, mac)
+			}
+		}
+		if cfg.WOL.MACAddress != "" && len(cfg.WOL.MACAddresses) > 0 {
+			cfg.WOL.MACAddresses = append([]string{cfg.WOL.MACAddress}, cfg.WOL.MACAddresses...)
+		}
+		if len(cfg.WOL.MACs()) == 0 {
+			return nil, fmt.Errorf("wol.mac_address or wol.mac_addresses is required when wol is configured")
 		}
 
 		// Set defaults.
 		if cfg.WOL.BroadcastIP == "" {
-			cfg.WOL.BroadcastIP = "255.255.255.255"
+			cfg.WOL.BroadcastIP = DefaultWOLBroadcastIP
+		}
+		if err := validateBroadcastIP(cfg.WOL.BroadcastIP); err != nil {
+			return nil, err
+		}
+		if cfg.WOL.PollURL != "" {
+			if err := validatePollURL(cfg.WOL.PollURL); err != nil {
+				return nil, err
+			}
+		}
+		if cfg.WOL.Port == 0 {
+			cfg.WOL.Port = DefaultWOLPort
+		}
+		if cfg.WOL.Port < 0 || cfg.WOL.Port > 65535 {
+			return nil, fmt.Errorf("wol.port must be between 1 and 65535")
+		}
+		if cfg.WOL.ExpectStatus != 0 && (cfg.WOL.ExpectStatus < 100 || cfg.WOL.ExpectStatus > 599) {
+			return nil, fmt.Errorf("wol.expect_status must be a valid HTTP status code")
 		}
 		if cfg.WOL.Timeout == 0 {
-			cfg.WOL.Timeout = 5 * time.Minute
+			cfg.WOL.Timeout = DefaultWOLTimeout
 		}
 		if cfg.WOL.PollInterval == 0 {
-			cfg.WOL.PollInterval = 10 * time.Second
+			cfg.WOL.PollInterval = DefaultWOLPollInterval
+		}
+		if cfg.WOL.HTTPTimeout == 0 {
+			cfg.WOL.HTTPTimeout = DefaultWOLHTTPTimeout
+		}
+		if cfg.WOL.HTTPTimeout < 0 {
+			return nil, fmt.Errorf("wol.http_timeout must not be negative")
 		}
 		if cfg.WOL.StabilizeWait == 0 {
-			cfg.WOL.StabilizeWait = 10 * time.Second
+			cfg.WOL.StabilizeWait = DefaultWOLStabilizeWait
+		}
+		if cfg.WOL.PacketCount == 0 {
+			cfg.WOL.PacketCount = DefaultWOLPacketCount
+		}
+		if cfg.WOL.PacketCount < 0 {
+			return nil, fmt.Errorf("wol.packet_count must not be negative")
+		}
+		if cfg.WOL.PacketCount > 1 && cfg.WOL.PacketInterval == 0 {
+			cfg.WOL.PacketInterval = time.Second
 		}
 	}
 
 	// Parse optional PostgreSQL config.
-	if p.v.IsSet("postgres") { //nolint:nestif // config parsing with defaults
+	if p.isSet("postgres") { //nolint:nestif // config parsing with defaults
 		cfg.Postgres = &models.PostgresConfig{
-			Host:     p.expandEnv(p.v.GetString("postgres.host")),
-			Port:     p.v.GetInt("postgres.port"),
-			Database: p.expandEnv(p.v.GetString("postgres.database")),
-			Username: p.expandEnv(p.v.GetString("postgres.username")),
-			Password: p.expandEnv(p.v.GetString("postgres.password")),
-			Format:   p.v.GetString("postgres.format"),
+			Host:        p.expandEnv(p.v.GetString("postgres.host")),
+			Port:        p.v.GetInt("postgres.port"),
+			Database:    p.expandEnv(p.v.GetString("postgres.database")),
+			Username:    p.expandEnv(p.v.GetString("postgres.username")),
+			Password:    p.expandEnv(p.v.GetString("postgres.password")),
+			Format:      p.v.GetString("postgres.format"),
+			DumpGlobals: p.v.GetBool("postgres.dump_globals"),
+
+			Jobs:        p.v.GetInt("postgres.jobs"),
+			Parallelism: p.v.GetInt("postgres.parallelism"),
+
+			ExcludeTables:  p.v.GetStringSlice("postgres.exclude_tables"),
+			IncludeTables:  p.v.GetStringSlice("postgres.include_tables"),
+			ExcludeSchemas: p.v.GetStringSlice("postgres.exclude_schemas"),
+
+			SSLMode:     p.v.GetString("postgres.sslmode"),
+			SSLRootCert: p.expandEnv(p.v.GetString("postgres.sslrootcert")),
+
+			KeepLocal: p.v.GetInt("postgres.keep_local"),
+			LocalDir:  p.expandEnv(p.v.GetString("postgres.local_dir")),
+
+			MinVersion: p.v.GetInt("postgres.min_version"),
+
+			StreamToRestic: p.v.GetBool("postgres.stream_to_restic"),
 		}
 
 		if cfg.Postgres.Host == "" {
-			cfg.Postgres.Host = "localhost"
+			cfg.Postgres.Host = DefaultPostgresHost
 		}
 		if cfg.Postgres.Port == 0 {
-			cfg.Postgres.Port = 5432
+			cfg.Postgres.Port = DefaultPostgresPort
+		}
+		// A single database is merged into the databases list. Each database
+		// must only appear once, dumps are named after it.
+		for _, db := range p.v.GetStringSlice("postgres.databases") {
+			if db = p.expandEnv(db); db != "" && db != cfg.Postgres.Database {
+				if slices.Contains(cfg.Postgres.Databases, db) {
+					return nil, fmt.Errorf("postgres.databases contains %q more than once", db)
+				}
+				cfg.Postgres.Databases = append(cfg.Postgres.Databases, db)
+			}
 		}
-		if cfg.Postgres.Database == "" {
-			return nil, fmt.Errorf("postgres.database is required when postgres is configured")
+		if cfg.Postgres.Database != "" && len(cfg.Postgres.Databases) > 0 {
+			cfg.Postgres.Databases = append([]string{cfg.Postgres.Database}, cfg.Postgres.Databases...)
+		}
+		if len(cfg.Postgres.DatabaseNames()) == 0 {
+			return nil, fmt.Errorf("postgres.database or postgres.databases is required when postgres is configured")
 		}
 		if cfg.Postgres.Username == "" {
-			cfg.Postgres.Username = "postgres"
+			cfg.Postgres.Username = DefaultPostgresUsername
 		}
 		if cfg.Postgres.Format == "" {
-			cfg.Postgres.Format = "custom"
+			cfg.Postgres.Format = DefaultPostgresFormat
 		}
 
 		// Validate format.
-		validFormats := map[string]bool{"custom": true, "plain": true, "tar": true}
+		validFormats := map[string]bool{"custom": true, "plain": true, "tar": true, "directory": true}
 		if !validFormats[cfg.Postgres.Format] {
-			return nil, fmt.Errorf("postgres.format must be one of: custom, plain, tar")
+			return nil, fmt.Errorf("postgres.format must be one of: custom, plain, tar, directory")
+		}
+		if cfg.Postgres.Jobs > 1 && cfg.Postgres.Format != "directory" {
+			return nil, fmt.Errorf("postgres.jobs > 1 requires postgres.format: directory")
+		}
+		if cfg.Postgres.Parallelism < 0 {
+			return nil, fmt.Errorf("postgres.parallelism must not be negative")
+		}
+		// An explicit 0 disables compression, so only an unset level keeps the default
+		if p.v.IsSet("postgres.compression_level") {
+			level := p.v.GetInt("postgres.compression_level")
+			if level < 0 || level > 9 {
+				return nil, fmt.Errorf("postgres.compression_level must be between 0 and 9")
+			}
+			cfg.Postgres.CompressionLevel = &level
+		}
+
+		// Validate SSL mode against the values libpq accepts.
+		validSSLModes := map[string]bool{
+			"disable": true, "allow": true, "prefer": true,
+			"require": true, "verify-ca": true, "verify-full": true,
+		}
+		if cfg.Postgres.SSLMode != "" && !validSSLModes[cfg.Postgres.SSLMode] {
+			return nil, fmt.Errorf("postgres.sslmode must be one of: disable, allow, prefer, require, verify-ca, verify-full")
+		}
+		if cfg.Postgres.KeepLocal < 0 {
+			return nil, fmt.Errorf("postgres.keep_local must not be negative")
+		}
+		if cfg.Postgres.KeepLocal > 0 && cfg.Postgres.LocalDir == "" {
+			return nil, fmt.Errorf("postgres.local_dir is required when postgres.keep_local is set")
+		}
+		if cfg.Postgres.MinVersion < 0 {
+			return nil, fmt.Errorf("postgres.min_version must not be negative")
+		}
+		// A directory dump is several files and cannot be piped through stdin
+		if cfg.Postgres.StreamToRestic && cfg.Postgres.Format == "directory" {
+			return nil, fmt.Errorf("postgres.stream_to_restic cannot be used with postgres.format: directory")
+		}
+		if cfg.Postgres.StreamToRestic && cfg.Postgres.KeepLocal > 0 {
+			return nil, fmt.Errorf("postgres.keep_local cannot be used with postgres.stream_to_restic")
+		}
+	}
+
+	// Parse optional MySQL/MariaDB config.
+	if p.isSet("mysql") {
+		cfg.MySQL = &models.MySQLConfig{
+			Host:     p.expandEnv(p.v.GetString("mysql.host")),
+			Port:     p.v.GetInt("mysql.port"),
+			Database: p.expandEnv(p.v.GetString("mysql.database")),
+			Username: p.expandEnv(p.v.GetString("mysql.username")),
+			Password: p.expandEnv(p.v.GetString("mysql.password")),
+		}
+
+		if cfg.MySQL.Host == "" {
+			cfg.MySQL.Host = DefaultMySQLHost
+		}
+		if cfg.MySQL.Port == 0 {
+			cfg.MySQL.Port = DefaultMySQLPort
+		}
+		if cfg.MySQL.Database == "" {
+			return nil, fmt.Errorf("mysql.database is required when mysql is configured")
+		}
+		if cfg.MySQL.Username == "" {
+			cfg.MySQL.Username = DefaultMySQLUsername
+		}
+	}
+
+	// Parse optional SQLite config.
+	if p.isSet("sqlite") {
+		cfg.SQLite = &models.SQLiteConfig{
+			OutputDir: p.expandEnv(p.v.GetString("sqlite.output_dir")),
+		}
+		for _, db := range p.v.GetStringSlice("sqlite.databases") {
+			if db = p.expandEnv(db); db != "" {
+				cfg.SQLite.Databases = append(cfg.SQLite.Databases, db)
+			}
+		}
+		if len(cfg.SQLite.Databases) == 0 {
+			return nil, fmt.Errorf("sqlite.databases is required when sqlite is configured")
 		}
 	}
 
 	// Parse optional SSH shutdown config.
-	if p.v.IsSet("ssh_shutdown") { //nolint:nestif // config parsing with defaults
+	if p.isSet("ssh_shutdown") { //nolint:nestif // config parsing with defaults
 		cfg.SSHShutdown = &models.SSHShutdownConfig{
 			Host:          p.expandEnv(p.v.GetString("ssh_shutdown.host")),
 			Port:          p.v.GetInt("ssh_shutdown.port"),
@@ -183,52 +642,118 @@ func (p *Parser) parse() (*models.BackupConfig, error) {
 			KeyPath:       p.expandEnv(p.v.GetString("ssh_shutdown.key_path")),
 			ShutdownDelay: p.v.GetInt("ssh_shutdown.shutdown_delay"),
 			OS:            p.v.GetString("ssh_shutdown.os"),
+
+			Action:          p.v.GetString("ssh_shutdown.action"),
+			ShutdownCommand: p.expandEnv(p.v.GetString("ssh_shutdown.command")),
+
+			PreShutdownCommands: p.v.GetStringSlice("ssh_shutdown.pre_commands"),
+			IgnorePreErrors:     p.v.GetBool("ssh_shutdown.ignore_pre_errors"),
+
+			VerifyDownURL:  p.expandEnv(p.v.GetString("ssh_shutdown.verify_down.url")),
+			VerifyTimeout:  p.v.GetDuration("ssh_shutdown.verify_down.timeout"),
+			VerifyInterval: p.v.GetDuration("ssh_shutdown.verify_down.interval"),
+
+			StrictHostKeyChecking: p.v.GetBool("ssh_shutdown.strict_host_key_checking"),
+			KnownHostsPath:        p.expandEnv(p.v.GetString("ssh_shutdown.known_hosts_path")),
 		}
 
 		if cfg.SSHShutdown.Host == "" {
 			return nil, fmt.Errorf("ssh_shutdown.host is required when ssh_shutdown is configured")
 		}
 		if cfg.SSHShutdown.Port == 0 {
-			cfg.SSHShutdown.Port = 22
+			cfg.SSHShutdown.Port = DefaultSSHPort
 		}
 		if cfg.SSHShutdown.Username == "" {
-			cfg.SSHShutdown.Username = "root"
+			cfg.SSHShutdown.Username = DefaultSSHUsername
 		}
 		if cfg.SSHShutdown.KeyPath == "" {
 			return nil, fmt.Errorf("ssh_shutdown.key_path is required when ssh_shutdown is configured")
 		}
 		if cfg.SSHShutdown.ShutdownDelay == 0 {
-			cfg.SSHShutdown.ShutdownDelay = 1
+			cfg.SSHShutdown.ShutdownDelay = DefaultSSHShutdownDelay
 		}
 		// Validate and default OS
 		if cfg.SSHShutdown.OS == "" {
-			cfg.SSHShutdown.OS = "linux"
+			cfg.SSHShutdown.OS = DefaultSSHOS
 		}
 		validOS := map[string]bool{"linux": true, "windows": true}
 		if !validOS[cfg.SSHShutdown.OS] {
 			return nil, fmt.Errorf("ssh_shutdown.os must be one of: linux, windows")
 		}
+		// verify_down is either a block or a plain boolean.
+		cfg.SSHShutdown.VerifyDown = p.isSet("ssh_shutdown.verify_down")
+		if enabled, ok := p.v.Get("ssh_shutdown.verify_down").(bool); ok {
+			cfg.SSHShutdown.VerifyDown = enabled
+		}
+		if cfg.SSHShutdown.VerifyDown {
+			// By default allow the shutdown delay plus five minutes for the host to go down
+			if cfg.SSHShutdown.VerifyTimeout == 0 {
+				cfg.SSHShutdown.VerifyTimeout = time.Duration(cfg.SSHShutdown.ShutdownDelay)*time.Minute + 5*time.Minute
+			}
+			if cfg.SSHShutdown.VerifyInterval == 0 {
+				cfg.SSHShutdown.VerifyInterval = DefaultSSHVerifyInterval
+			}
+		}
+		if cfg.SSHShutdown.Action == "" {
+			cfg.SSHShutdown.Action = models.SSHActionShutdown
+		}
+		if cfg.SSHShutdown.Action != models.SSHActionShutdown && cfg.SSHShutdown.Action != models.SSHActionReboot {
+			return nil, fmt.Errorf("ssh_shutdown.action must be one of: shutdown, reboot")
+		}
+		// Default to the user's known_hosts, like OpenSSH
+		if cfg.SSHShutdown.StrictHostKeyChecking && cfg.SSHShutdown.KnownHostsPath == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("ssh_shutdown.known_hosts_path is required when the home directory is unknown: %w", err)
+			}
+			cfg.SSHShutdown.KnownHostsPath = filepath.Join(home, ".ssh", "known_hosts")
+		}
 	}
 
 	// Parse optional Telegram config.
-	if p.v.IsSet("telegram") {
+	if p.isSet("telegram") {
 		cfg.Telegram = &models.TelegramConfig{
-			BotToken: p.expandEnv(p.v.GetString("telegram.bot_token")),
-			ChatID:   p.expandEnv(p.v.GetString("telegram.chat_id")),
+			BotToken:  p.expandEnv(p.v.GetString("telegram.bot_token")),
+			ParseMode: p.expandEnv(p.v.GetString("telegram.parse_mode")),
+
+			HTTPTimeout: p.v.GetDuration("telegram.http_timeout"),
+		}
+
+		cfg.Telegram.MaxRetries = DefaultTelegramMaxRetries
+		if p.isSet("telegram.max_retries") {
+			cfg.Telegram.MaxRetries = p.v.GetInt("telegram.max_retries")
+		}
+
+		if cfg.Telegram.HTTPTimeout == 0 {
+			cfg.Telegram.HTTPTimeout = DefaultTelegramHTTPTimeout
+		}
+		if cfg.Telegram.HTTPTimeout < 0 {
+			return nil, fmt.Errorf("telegram.http_timeout must not be negative")
 		}
 
 		if cfg.Telegram.BotToken == "" {
 			return nil, fmt.Errorf("telegram.bot_token is required when telegram is configured")
 		}
-		if cfg.Telegram.ChatID == "" {
-			return nil, fmt.Errorf("telegram.chat_id is required when telegram is configured")
+		// A single chat_id is merged into the chat_ids list.
+		if chatID := p.expandEnv(p.v.GetString("telegram.chat_id")); chatID != "" {
+			cfg.Telegram.ChatIDs = append(cfg.Telegram.ChatIDs, chatID)
+		}
+		cfg.Telegram.ChatIDs = append(cfg.Telegram.ChatIDs, p.expandEnvSlice(p.v.GetStringSlice("telegram.chat_ids"))...)
+		if len(cfg.Telegram.ChatIDs) == 0 {
+			return nil, fmt.Errorf("telegram.chat_id or telegram.chat_ids is required when telegram is configured")
+		}
+		if cfg.Telegram.ParseMode == "" {
+			cfg.Telegram.ParseMode = DefaultTelegramParseMode
+		}
+		if cfg.Telegram.ParseMode != "HTML" && cfg.Telegram.ParseMode != "MarkdownV2" {
+			return nil, fmt.Errorf("telegram.parse_mode must be one of: HTML, MarkdownV2")
 		}
 	}
 
 	// Parse optional Pushover config.
-	if p.v.IsSet("pushover") {
-		priority := 1
-		if p.v.IsSet("pushover.priority") {
+	if p.isSet("pushover") {
+		priority := DefaultPushoverPriority
+		if p.isSet("pushover.priority") {
 			priority = p.v.GetInt("pushover.priority")
 		}
 
@@ -246,16 +771,314 @@ func (p *Parser) parse() (*models.BackupConfig, error) {
 		}
 	}
 
+	// Parse optional healthcheck config.
+	if p.isSet("healthcheck") {
+		cfg.Healthcheck = &models.HealthcheckConfig{
+			PingURL: p.expandEnv(p.v.GetString("healthcheck.ping_url")),
+		}
+
+		if cfg.Healthcheck.PingURL == "" {
+			return nil, fmt.Errorf("healthcheck.ping_url is required when healthcheck is configured")
+		}
+	}
+
+	// Parse optional webhook config.
+	if p.isSet("webhook") {
+		cfg.Webhook = &models.WebhookConfig{
+			URL:    p.expandEnv(p.v.GetString("webhook.url")),
+			Method: strings.ToUpper(p.v.GetString("webhook.method")),
+		}
+
+		if cfg.Webhook.URL == "" {
+			return nil, fmt.Errorf("webhook.url is required when webhook is configured")
+		}
+		if cfg.Webhook.Method == "" {
+			cfg.Webhook.Method = DefaultWebhookMethod
+		}
+		if headers := p.v.GetStringMapString("webhook.headers"); len(headers) > 0 {
+			cfg.Webhook.Headers = make(map[string]string, len(headers))
+			for name, value := range headers {
+				cfg.Webhook.Headers[name] = p.expandEnv(value)
+			}
+		}
+	}
+
+	// Parse optional Discord config.
+	if p.isSet("discord") {
+		cfg.Discord = &models.DiscordConfig{
+			WebhookURL: p.expandEnv(p.v.GetString("discord.webhook_url")),
+		}
+
+		if cfg.Discord.WebhookURL == "" {
+			return nil, fmt.Errorf("discord.webhook_url is required when discord is configured")
+		}
+	}
+
+	// Parse optional Slack config.
+	if p.isSet("slack") {
+		cfg.Slack = &models.SlackConfig{
+			WebhookURL: p.expandEnv(p.v.GetString("slack.webhook_url")),
+			Channel:    p.expandEnv(p.v.GetString("slack.channel")),
+		}
+
+		if cfg.Slack.WebhookURL == "" {
+			return nil, fmt.Errorf("slack.webhook_url is required when slack is configured")
+		}
+	}
+
+	// Parse optional ntfy config.
+	if p.isSet("ntfy") {
+		cfg.Ntfy = &models.NtfyConfig{
+			Server: p.expandEnv(p.v.GetString("ntfy.server")),
+			Topic:  p.expandEnv(p.v.GetString("ntfy.topic")),
+			Token:  p.expandEnv(p.v.GetString("ntfy.token")),
+		}
+
+		if cfg.Ntfy.Server == "" {
+			cfg.Ntfy.Server = DefaultNtfyServer
+		}
+		if cfg.Ntfy.Topic == "" {
+			return nil, fmt.Errorf("ntfy.topic is required when ntfy is configured")
+		}
+	}
+
+	// Parse optional email config.
+	if p.isSet("email") {
+		cfg.Email = &models.EmailConfig{
+			SMTPHost: p.expandEnv(p.v.GetString("email.smtp_host")),
+			SMTPPort: p.v.GetInt("email.smtp_port"),
+			Username: p.expandEnv(p.v.GetString("email.username")),
+			Password: p.expandEnv(p.v.GetString("email.password")),
+			From:     p.expandEnv(p.v.GetString("email.from")),
+			To:       p.expandEnvSlice(p.v.GetStringSlice("email.to")),
+			UseTLS:   p.v.GetBool("email.use_tls"),
+		}
+
+		if cfg.Email.SMTPPort == 0 {
+			cfg.Email.SMTPPort = DefaultEmailSMTPPort
+		}
+		if cfg.Email.SMTPHost == "" {
+			return nil, fmt.Errorf("email.smtp_host is required when email is configured")
+		}
+		if cfg.Email.From == "" {
+			return nil, fmt.Errorf("email.from is required when email is configured")
+		}
+		if len(cfg.Email.To) == 0 {
+			return nil, fmt.Errorf("email.to is required when email is configured")
+		}
+	}
+
 	return cfg, nil
 }
 
+// parseBackupTargets reads the optional backup.targets list.
+func (p *Parser) parseBackupTargets() ([]models.BackupTarget, error) {
+	var raw []struct {
+		Paths []string `mapstructure:"paths"`
+		Tags  []string `mapstructure:"tags"`
+	}
+	if err := p.v.UnmarshalKey("backup.targets", &raw); err != nil {
+		return nil, fmt.Errorf("backup.targets: %w", err)
+	}
+
+	targets := make([]models.BackupTarget, 0, len(raw))
+	for i, target := range raw {
+		paths := p.expandEnvSlice(target.Paths)
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("backup.targets[%d].paths is required", i)
+		}
+		targets = append(targets, models.BackupTarget{Paths: paths, Tags: p.expandEnvSlice(target.Tags)})
+	}
+
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	return targets, nil
+}
+
+// parseResticEnv collects the env entries and typed backend credentials of
+// section, restic or copy_to. Viper lowercases keys, so variable names are
+// upper-cased again here.
+func (p *Parser) parseResticEnv(section string) map[string]string {
+	env := make(map[string]string)
+
+	for key, value := range p.v.GetStringMapString(section + ".env") {
+		env[strings.ToUpper(key)] = p.expandEnv(value)
+	}
+
+	for key, envName := range backendEnvKeys {
+		if value := p.v.GetString(section + "." + key); value != "" {
+			env[envName] = p.expandEnv(value)
+		}
+	}
+
+	if len(env) == 0 {
+		return nil
+	}
+	return env
+}
+
+// checkRepository validates the backend prefix of a repository string. Malformed
+// rest and sftp URLs are errors, unknown schemes are only recorded as warnings
+// since restic may support backends we do not know about.
+func (p *Parser) checkRepository(key, repo string) error {
+	switch {
+	case strings.HasPrefix(repo, "/"):
+		return nil
+	case strings.HasPrefix(repo, "rest:"):
+		u, err := url.Parse(strings.TrimPrefix(repo, "rest:"))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s is not a valid rest URL: %q", key, repo)
+		}
+	case strings.HasPrefix(repo, "sftp:"):
+		target := strings.TrimPrefix(repo, "sftp:")
+		if strings.HasPrefix(target, "//") {
+			if u, err := url.Parse(repo); err != nil || u.Host == "" {
+				return fmt.Errorf("%s is not a valid sftp URL: %q", key, repo)
+			}
+		} else if host, _, ok := strings.Cut(target, ":"); !ok || host == "" {
+			return fmt.Errorf("%s is not a valid sftp URL: %q", key, repo)
+		}
+	}
+
+	for _, scheme := range knownRepositorySchemes {
+		if strings.HasPrefix(repo, scheme) {
+			return nil
+		}
+	}
+	// Anything else without a scheme is a relative local path.
+	if scheme, _, ok := strings.Cut(repo, ":"); ok && !strings.Contains(scheme, "/") {
+		p.warnings = append(p.warnings, fmt.Sprintf("%s uses an unknown backend %q", key, scheme+":"))
+	}
+	return nil
+}
+
+// validateBroadcastIP accepts an IPv4 broadcast or an IPv6 address. Link-local
+// IPv6 addresses, including ff02::1, need a zone such as %eth0 to select the
+// interface the magic packet is sent on.
+func validateBroadcastIP(ip string) error {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return fmt.Errorf("wol.broadcast_ip is invalid: %q", ip)
+	}
+	if addr.Is6() && !addr.Is4In6() && addr.Zone() == "" &&
+		(addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast()) {
+		return fmt.Errorf("wol.broadcast_ip %q needs an interface zone, e.g. %s%%eth0", ip, ip)
+	}
+	return nil
+}
+
+// validatePollURL requires an http(s) URL with a host. IPv6 hosts must be
+// bracketed, otherwise the last group would be taken as the port.
+func validatePollURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("wol.poll_url must be an http(s) URL: %q", raw)
+	}
+	if strings.Contains(u.Hostname(), ":") && !strings.HasPrefix(u.Host, "[") {
+		return fmt.Errorf("wol.poll_url must put IPv6 addresses in brackets, e.g. http://[::1]:8000: %q", raw)
+	}
+	return nil
+}
+
+// networkAddress converts network.wait, a host:port or http(s) URL, into the
+// host:port to connect to.
+func networkAddress(wait string) (string, error) {
+	if wait == "" {
+		return "", fmt.Errorf("network.wait is required when network is configured")
+	}
+	if !strings.Contains(wait, "://") {
+		if host, port, err := net.SplitHostPort(wait); err != nil || host == "" || port == "" {
+			return "", fmt.Errorf("network.wait must be host:port or an http(s) URL: %q", wait)
+		}
+		return wait, nil
+	}
+
+	u, err := url.Parse(wait)
+	if err != nil || u.Hostname() == "" {
+		return "", fmt.Errorf("network.wait is not a valid URL: %q", wait)
+	}
+	port := u.Port()
+	switch {
+	case port != "":
+	case u.Scheme == "http":
+		port = "80"
+	case u.Scheme == "https":
+		port = "443"
+	default:
+		return "", fmt.Errorf("network.wait URL needs a port for scheme %q", u.Scheme)
+	}
+	return net.JoinHostPort(u.Hostname(), port), nil
+}
+
+// isSet reports whether key is set in the config file or environment. Sections
+// also count as set if any GORESTIC_<SECTION>_* variable is present.
+func (p *Parser) isSet(key string) bool {
+	if p.v.IsSet(key) {
+		return true
+	}
+
+	prefix := EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_")) + "_"
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultLockFile returns the default lock file path for a repository.
+func DefaultLockFile(repository string) string {
+	sum := sha256.Sum256([]byte(repository))
+	return filepath.Join(os.TempDir(), "gorestic-"+hex.EncodeToString(sum[:8])+".lock")
+}
+
 // expandEnv expands environment variables in the format ${VAR} or $VAR.
+// Unset variables expand to an empty string.
+// defaultHost is the snapshot host used when backup.host is not set:
+// GORESTIC_HOSTNAME if set, otherwise the system hostname.
+func defaultHost() string {
+	if host := os.Getenv(HostnameEnv); host != "" {
+		return host
+	}
+	return systemHostname()
+}
+
+// systemHostname returns the hostname reported by the kernel, or "unknown".
+func systemHostname() string {
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return "unknown"
+	}
+	return hostname
+}
+
 func (p *Parser) expandEnv(s string) string {
 	return os.ExpandEnv(s)
 }
 
+// expandEnvSlice expands environment variables in every element of a list.
+// Elements that expand to an empty string are dropped.
+func (p *Parser) expandEnvSlice(values []string) []string {
+	var expanded []string
+	for _, value := range values {
+		if value = p.expandEnv(value); value != "" {
+			expanded = append(expanded, value)
+		}
+	}
+	return expanded
+}
+
 // Validate performs validation on the loaded configuration.
+// Failures are returned as *Error.
 func Validate(cfg *models.BackupConfig) error {
+	if err := validate(cfg); err != nil {
+		return &Error{Err: err}
+	}
+	return nil
+}
+
+func validate(cfg *models.BackupConfig) error {
 	if cfg == nil {
 		return fmt.Errorf("configuration is nil")
 	}
@@ -268,8 +1091,12 @@ func Validate(cfg *models.BackupConfig) error {
 		return fmt.Errorf("restic.password is required")
 	}
 
-	if len(cfg.Backup.Paths) == 0 {
-		return fmt.Errorf("backup.paths is required")
+	if cfg.Restic.CacheDir != "" && cfg.Restic.NoCache {
+		return fmt.Errorf("restic.cache_dir and restic.no_cache are mutually exclusive")
+	}
+
+	if len(cfg.Backup.Paths) == 0 && cfg.Backup.FilesFrom == "" && len(cfg.Backup.Targets) == 0 {
+		return fmt.Errorf("backup.paths is required unless backup.targets is set")
 	}
 
 	return nil
@@ -0,0 +1,378 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+	"maps"
+)
+
+// schemaNode is a JSON Schema object.
+type schemaNode = map[string]any
+
+// durationPattern matches Go durations as accepted by time.ParseDuration, e.g. 1m30s.
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// WriteSchema writes a JSON Schema of the config file for editor validation.
+// Required keys and defaults follow the rules the parser applies; keys that
+// may come from an included file are still marked as required.
+func WriteSchema(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(Schema())
+}
+
+// Schema returns the JSON Schema of the config file.
+func Schema() schemaNode {
+	root := object("gorestic-homelab configuration", schemaNode{
+		"include":      stringList("Files merged before this one; relative paths are resolved against this file's directory"),
+		"restic":       resticSchema(),
+		"backup":       backupSchema(),
+		"retention":    retentionSchema(),
+		"check":        checkSchema(),
+		"copy_to":      copyToSchema(),
+		"lock_file":    str("Lock file preventing concurrent runs (default: gorestic-<repo-hash>.lock in the system temp dir)"),
+		"temp_dir":     str("Parent directory for transient database dumps (default: the system temp dir)"),
+		"metrics_file": str("Prometheus textfile written after each run"),
+		"log": object("Log output", schemaNode{
+			"output": withDefault(enum("stdout or syslog", "stdout", "syslog"), "stdout"),
+		}),
+		"hooks": object("Shell commands run through sh -c", schemaNode{
+			"pre":  stringList("Run before the backup; a failure aborts the run"),
+			"post": stringList("Always run after the backup; failures are only logged"),
+		}),
+		"network": object("Wait for the network before the run", schemaNode{
+			"wait":          str("host:port or http(s) URL that must be reachable"),
+			"timeout":       withDefault(duration("Max time to wait"), formatDuration(DefaultNetworkTimeout)),
+			"poll_interval": withDefault(duration("Pause between connection attempts"), formatDuration(DefaultNetworkPollInterval)),
+		}, "wait"),
+		"wol":          wolSchema(),
+		"postgres":     postgresSchema(),
+		"mysql":        mysqlSchema(),
+		"sqlite":       sqliteSchema(),
+		"ssh_shutdown": sshShutdownSchema(),
+		"notify": object("Notification filter applied to all notifiers", schemaNode{
+			"on":              withDefault(enum("When notifiers fire", "always", "failure", "success"), "always"),
+			"before_shutdown": withDefault(boolean("Notify before the SSH shutdown instead of after it"), false),
+		}),
+		"telegram":    telegramSchema(),
+		"pushover":    pushoverSchema(),
+		"healthcheck": object("Healthchecks.io pings", schemaNode{"ping_url": str("Ping URL")}, "ping_url"),
+		"webhook": object("Generic JSON webhook", schemaNode{
+			"url":     str("Webhook URL"),
+			"method":  withDefault(str("HTTP method"), DefaultWebhookMethod),
+			"headers": stringMap("HTTP headers sent with each request"),
+		}, "url"),
+		"discord": object("Discord webhook", schemaNode{"webhook_url": str("Webhook URL")}, "webhook_url"),
+		"slack": object("Slack incoming webhook", schemaNode{
+			"webhook_url": str("Webhook URL"),
+			"channel":     str("Channel override, e.g. #backups"),
+		}, "webhook_url"),
+		"ntfy": object("ntfy push notification", schemaNode{
+			"server": withDefault(str("ntfy server"), DefaultNtfyServer),
+			"topic":  str("Topic"),
+			"token":  str("Access token for protected topics"),
+		}, "topic"),
+		"email": object("Plain-text email report via SMTP", schemaNode{
+			"smtp_host": str("SMTP server"),
+			"smtp_port": withDefault(integer("SMTP port"), DefaultEmailSMTPPort),
+			"username":  str("SMTP username"),
+			"password":  str("SMTP password"),
+			"from":      str("Sender address"),
+			"to":        stringList("Recipients"),
+			"use_tls":   withDefault(boolean("Implicit TLS, usually on port 465"), false),
+		}, "smtp_host", "from", "to"),
+	}, "restic", "backup")
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["title"] = "gorestic-homelab"
+	return root
+}
+
+func resticSchema() schemaNode {
+	properties := schemaNode{
+		"repository":       str("Repository URL, e.g. /srv/restic, sftp:, rest:, s3:, b2:, azure: or rclone:"),
+		"password":         str("Repository password"),
+		"fail_on_locked":   withDefault(boolean("Fail if the repository is locked instead of removing stale locks"), true),
+		"cache_dir":        str("Restic cache location (RESTIC_CACHE_DIR)"),
+		"no_cache":         boolean("Disable the restic cache"),
+		"pack_size":        withRange(integer("Target pack size in MiB"), 4, 128),
+		"read_concurrency": withRange(integer("Files read in parallel"), 0, nil),
+		"compression":      enum("Compression mode", "auto", "off", "max"),
+		"cacert":           str("CA certificate for the repository server"),
+		"insecure_tls":     boolean("Skip TLS certificate verification"),
+		"rclone_config":    str("rclone config file (RCLONE_CONFIG), rclone: repositories only"),
+		"rclone_args":      str("Arguments rclone is started with, rclone: repositories only"),
+	}
+	maps.Copy(properties, backendCredentialsSchema())
+	return object("Restic repository", properties, "repository", "password")
+}
+
+// backendCredentialsSchema describes the credential keys shared by restic and copy_to.
+func backendCredentialsSchema() schemaNode {
+	return schemaNode{
+		"rest_user":     str("REST server username"),
+		"rest_password": str("REST server password"),
+		"s3": object("S3 credentials", schemaNode{
+			"access_key_id":     str("AWS_ACCESS_KEY_ID"),
+			"secret_access_key": str("AWS_SECRET_ACCESS_KEY"),
+			"region":            str("AWS_DEFAULT_REGION"),
+		}),
+		"b2": object("Backblaze B2 credentials", schemaNode{
+			"account_id":  str("B2_ACCOUNT_ID"),
+			"account_key": str("B2_ACCOUNT_KEY"),
+		}),
+		"azure": object("Azure Blob Storage credentials", schemaNode{
+			"account_name": str("AZURE_ACCOUNT_NAME"),
+			"account_key":  str("AZURE_ACCOUNT_KEY"),
+		}),
+		"env": stringMap("Additional environment variables for restic"),
+	}
+}
+
+func backupSchema() schemaNode {
+	backup := object("Backup settings", schemaNode{
+		"paths":               stringList("Paths to back up"),
+		"tags":                stringList("Snapshot tags; {date}, {time}, {weekday} and {host} are expanded"),
+		"host":                str("Snapshot host (default: $GORESTIC_HOSTNAME, then the system hostname)"),
+		"host_suffix":         str("Appended to the host; {hostname} is the system hostname"),
+		"excludes":            stringList("Exclude patterns (--exclude)"),
+		"exclude_caches":      boolean("Skip directories containing a CACHEDIR.TAG file"),
+		"exclude_if_present":  stringList("Skip directories containing any of these files"),
+		"exclude_file":        str("File with exclude patterns"),
+		"files_from":          str("File listing the paths to back up"),
+		"one_file_system":     boolean("Don't cross filesystem boundaries"),
+		"require_paths_exist": withDefault(boolean("Fail if a path is missing"), true),
+		"require_non_empty":   withDefault(boolean("Fail if a path is empty"), false),
+		"min_data_added":      withRange(integer("Fail if a backup adds fewer bytes"), 0, nil),
+		"min_files_processed": withRange(integer("Fail if a backup processes fewer files"), 0, nil),
+		"min_interval":        duration("Skip the run if this host has a younger snapshot"),
+		"retries":             withRange(integer("Retries after network errors"), 0, nil),
+		"retry_delay":         withDefault(duration("Delay before the first retry, doubled after each attempt"), formatDuration(DefaultBackupRetryDelay)),
+		"progress_file":       str("JSON file with the latest backup progress"),
+		"targets": schemaNode{
+			"type":        "array",
+			"description": "Additional snapshots, each with its own paths and tags",
+			"items": object("", schemaNode{
+				"paths": stringList("Paths of this target"),
+				"tags":  stringList("Tags of this target"),
+			}, "paths"),
+		},
+	})
+	backup["anyOf"] = requireOneOf("paths", "files_from", "targets")
+	return backup
+}
+
+func retentionSchema() schemaNode {
+	return object("Retention policy; defaults only apply when no keep rule is set", schemaNode{
+		"keep_last":           integer("Keep the last n snapshots"),
+		"keep_hourly":         integer("Hourly snapshots to keep"),
+		"keep_daily":          withDefault(integer("Daily snapshots to keep"), DefaultKeepDaily),
+		"keep_weekly":         withDefault(integer("Weekly snapshots to keep"), DefaultKeepWeekly),
+		"keep_monthly":        withDefault(integer("Monthly snapshots to keep"), DefaultKeepMonthly),
+		"keep_yearly":         integer("Yearly snapshots to keep"),
+		"keep_within":         str("Keep all snapshots within this duration, e.g. 30d"),
+		"keep_tags":           stringList("Never forget snapshots with any of these tags"),
+		"tags":                stringList("Only forget snapshots with any of these tags"),
+		"group_by":            str("How snapshots are grouped for the keep rules, e.g. host,tags"),
+		"skip_when_unchanged": boolean("Skip forget and prune when no snapshot was created"),
+		"prune": object("Prune unreferenced data after forget", schemaNode{
+			"enabled":    boolean("Run restic prune"),
+			"max_unused": str("Allowed unused space before repacking, e.g. 5%"),
+		}),
+	})
+}
+
+func checkSchema() schemaNode {
+	return object("Repository check", schemaNode{
+		"enabled":   boolean("Run restic check after the backup"),
+		"subset":    str("Part of the data read each run, e.g. 5% or 1/10"),
+		"read_data": boolean("Read all data instead of a subset"),
+	})
+}
+
+func copyToSchema() schemaNode {
+	properties := schemaNode{
+		"enabled":    withDefault(boolean("Copy after each backup"), true),
+		"repository": str("Destination repository URL"),
+		"password":   str("Destination repository password"),
+	}
+	maps.Copy(properties, backendCredentialsSchema())
+	return object("Copy new snapshots to a second repository", properties)
+}
+
+func wolSchema() schemaNode {
+	wol := object("Wake-on-LAN before the backup", schemaNode{
+		"mac_address":     str("MAC address to wake"),
+		"mac_addresses":   stringList("Additional MAC addresses"),
+		"packet_count":    withDefault(withRange(integer("Rounds of magic packets"), 0, nil), DefaultWOLPacketCount),
+		"packet_interval": duration("Pause between repeated packets"),
+		"broadcast_ip":    withDefault(str("IPv4 broadcast or IPv6 address, e.g. ff02::1%eth0"), DefaultWOLBroadcastIP),
+		"port":            withDefault(withRange(integer("UDP port for magic packets"), 1, 65535), DefaultWOLPort),
+		"poll_url":        str("http(s) URL polled until the target is ready"),
+		"expect_status":   withRange(integer("Status meaning ready (default: any 2xx/3xx)"), 100, 599),
+		"timeout":         withDefault(duration("Max time to wait for the target"), formatDuration(DefaultWOLTimeout)),
+		"poll_interval":   withDefault(duration("How often poll_url is checked"), formatDuration(DefaultWOLPollInterval)),
+		"http_timeout":    withDefault(duration("Timeout of a single poll request"), formatDuration(DefaultWOLHTTPTimeout)),
+		"stabilize_wait":  withDefault(duration("Wait after the target responds"), formatDuration(DefaultWOLStabilizeWait)),
+	})
+	wol["anyOf"] = requireOneOf("mac_address", "mac_addresses")
+	return wol
+}
+
+func postgresSchema() schemaNode {
+	postgres := object("PostgreSQL dump before the backup", schemaNode{
+		"host":              withDefault(str("Server host"), DefaultPostgresHost),
+		"port":              withDefault(integer("Server port"), DefaultPostgresPort),
+		"database":          str("Database to dump"),
+		"databases":         stringList("Databases to dump, one file each"),
+		"username":          withDefault(str("Username"), DefaultPostgresUsername),
+		"password":          str("Password"),
+		"format":            withDefault(enum("pg_dump format", "custom", "plain", "tar", "directory"), DefaultPostgresFormat),
+		"jobs":              integer("Parallel dump jobs (pg_dump -j), format: directory only"),
+		"parallelism":       withRange(integer("Databases dumped at the same time"), 0, nil),
+		"compression_level": withRange(integer("pg_dump -Z, 0 disables compression, unset keeps the pg_dump default"), 0, 9),
+		"exclude_tables":    stringList("pg_dump -T"),
+		"include_tables":    stringList("pg_dump -t"),
+		"exclude_schemas":   stringList("pg_dump -N"),
+		"sslmode":           enum("libpq SSL mode", "disable", "allow", "prefer", "require", "verify-ca", "verify-full"),
+		"sslrootcert":       str("CA certificate for verify-ca / verify-full"),
+		"dump_globals":      boolean("Also dump roles and tablespaces"),
+		"keep_local":        withRange(integer("Dumps kept per database in local_dir"), 0, nil),
+		"local_dir":         str("Where kept dumps are stored"),
+		"min_version":       withRange(integer("Minimum pg_dump major version"), 0, nil),
+		"stream_to_restic":  withDefault(boolean("Pipe pg_dump into restic backup --stdin"), false),
+	})
+	postgres["anyOf"] = requireOneOf("database", "databases")
+	return postgres
+}
+
+func mysqlSchema() schemaNode {
+	return object("MySQL/MariaDB dump before the backup", schemaNode{
+		"host":     withDefault(str("Server host"), DefaultMySQLHost),
+		"port":     withDefault(integer("Server port"), DefaultMySQLPort),
+		"database": str("Database to dump"),
+		"username": withDefault(str("Username"), DefaultMySQLUsername),
+		"password": str("Password"),
+	}, "database")
+}
+
+func sqliteSchema() schemaNode {
+	return object("SQLite snapshots before the backup", schemaNode{
+		"databases":  stringList("Database files"),
+		"output_dir": str("Snapshot directory (default: the system temp dir)"),
+	}, "databases")
+}
+
+func sshShutdownSchema() schemaNode {
+	verifyDown := object("Confirm the host went down", schemaNode{
+		"url":      str("HTTP check, TCP connect to host:port if omitted"),
+		"timeout":  duration("Max time to wait (default: shutdown_delay + 5m)"),
+		"interval": withDefault(duration("Pause between checks"), formatDuration(DefaultSSHVerifyInterval)),
+	})
+	verifyDown["type"] = []string{"object", "boolean"}
+
+	return object("Shut down a host over SSH after the backup", schemaNode{
+		"host":                     str("Host to shut down"),
+		"port":                     withDefault(integer("SSH port"), DefaultSSHPort),
+		"username":                 withDefault(str("SSH username"), DefaultSSHUsername),
+		"key_path":                 str("Private key file"),
+		"shutdown_delay":           withDefault(integer("Minutes before shutdown"), DefaultSSHShutdownDelay),
+		"os":                       withDefault(enum("Remote operating system", "linux", "windows"), DefaultSSHOS),
+		"action":                   withDefault(enum("Action", "shutdown", "reboot"), "shutdown"),
+		"command":                  str("Overrides the command built from action and os"),
+		"pre_commands":             stringList("Run before the shutdown, each must succeed"),
+		"ignore_pre_errors":        boolean("Shut down even if a pre-command fails"),
+		"verify_down":              verifyDown,
+		"strict_host_key_checking": boolean("Verify the host key against known_hosts"),
+		"known_hosts_path":         str("known_hosts file (default: ~/.ssh/known_hosts)"),
+	}, "host", "key_path")
+}
+
+func telegramSchema() schemaNode {
+	telegram := object("Telegram notification", schemaNode{
+		"bot_token":    str("Bot token"),
+		"chat_id":      str("Chat to notify"),
+		"chat_ids":     stringList("Additional chats"),
+		"parse_mode":   withDefault(enum("Message format", "HTML", "MarkdownV2"), DefaultTelegramParseMode),
+		"max_retries":  withDefault(withRange(integer("Attempts on rate limits and server errors"), 0, nil), DefaultTelegramMaxRetries),
+		"http_timeout": withDefault(duration("Timeout of a single Telegram API request"), formatDuration(DefaultTelegramHTTPTimeout)),
+	}, "bot_token")
+	telegram["anyOf"] = requireOneOf("chat_id", "chat_ids")
+	return telegram
+}
+
+func pushoverSchema() schemaNode {
+	return object("Pushover notification", schemaNode{
+		"app_token": str("Application token"),
+		"user_key":  str("User key"),
+		"priority":  withDefault(withRange(integer("-2 (lowest) to 2 (emergency)"), -2, 2), DefaultPushoverPriority),
+	}, "app_token", "user_key")
+}
+
+// object returns an object schema that rejects unknown keys, e.g. typos.
+func object(description string, properties schemaNode, required ...string) schemaNode {
+	node := schemaNode{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if description != "" {
+		node["description"] = description
+	}
+	if len(required) > 0 {
+		node["required"] = required
+	}
+	return node
+}
+
+// requireOneOf requires at least one of keys, e.g. database or databases.
+func requireOneOf(keys ...string) []schemaNode {
+	alternatives := make([]schemaNode, 0, len(keys))
+	for _, key := range keys {
+		alternatives = append(alternatives, schemaNode{"required": []string{key}})
+	}
+	return alternatives
+}
+
+func str(description string) schemaNode {
+	return schemaNode{"type": "string", "description": description}
+}
+
+func integer(description string) schemaNode {
+	return schemaNode{"type": "integer", "description": description}
+}
+
+func boolean(description string) schemaNode {
+	return schemaNode{"type": "boolean", "description": description}
+}
+
+func duration(description string) schemaNode {
+	return schemaNode{"type": "string", "pattern": durationPattern, "description": description}
+}
+
+func enum(description string, values ...string) schemaNode {
+	return schemaNode{"type": "string", "enum": values, "description": description}
+}
+
+func stringList(description string) schemaNode {
+	return schemaNode{"type": "array", "items": schemaNode{"type": "string"}, "description": description}
+}
+
+func stringMap(description string) schemaNode {
+	return schemaNode{"type": "object", "additionalProperties": schemaNode{"type": "string"}, "description": description}
+}
+
+func withDefault(node schemaNode, value any) schemaNode {
+	node["default"] = value
+	return node
+}
+
+// withRange sets inclusive bounds; a nil bound is left open.
+func withRange(node schemaNode, minimum, maximum any) schemaNode {
+	if minimum != nil {
+		node["minimum"] = minimum
+	}
+	if maximum != nil {
+		node["maximum"] = maximum
+	}
+	return node
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaJSON is the subset of JSON Schema the tests look at.
+type schemaJSON struct {
+	Type       any                   `json:"type"` // a string or a list of types
+	Required   []string              `json:"required"`
+	Properties map[string]schemaJSON `json:"properties"`
+	AnyOf      []schemaJSON          `json:"anyOf"`
+	Default    any                   `json:"default"`
+}
+
+func decodeSchema(t *testing.T) schemaJSON {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSchema(&buf))
+	require.True(t, json.Valid(buf.Bytes()))
+
+	var schema schemaJSON
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &schema))
+	return schema
+}
+
+func TestWriteSchema_ValidJSON(t *testing.T) {
+	schema := decodeSchema(t)
+
+	assert.Equal(t, "object", schema.Type)
+	assert.ElementsMatch(t, []string{"restic", "backup"}, schema.Required)
+
+	restic := schema.Properties["restic"]
+	assert.Contains(t, restic.Required, "repository")
+	assert.Contains(t, restic.Required, "password")
+	assert.Equal(t, "string", restic.Properties["repository"].Type)
+}
+
+func TestSchema_RequiredAlternatives(t *testing.T) {
+	schema := decodeSchema(t)
+
+	alternatives := func(section string) []string {
+		var keys []string
+		for _, alt := range schema.Properties[section].AnyOf {
+			keys = append(keys, alt.Required...)
+		}
+		return keys
+	}
+	assert.Equal(t, []string{"paths", "files_from", "targets"}, alternatives("backup"))
+	assert.Equal(t, []string{"database", "databases"}, alternatives("postgres"))
+	assert.Equal(t, []string{"chat_id", "chat_ids"}, alternatives("telegram"))
+	assert.Equal(t, []string{"bot_token"}, schema.Properties["telegram"].Required)
+}
+
+func TestSchema_Defaults(t *testing.T) {
+	schema := decodeSchema(t)
+
+	assert.InDelta(t, DefaultKeepDaily, schema.Properties["retention"].Properties["keep_daily"].Default, 0)
+	assert.Equal(t, "5m", schema.Properties["wol"].Properties["timeout"].Default)
+	assert.Equal(t, DefaultPostgresFormat, schema.Properties["postgres"].Properties["format"].Default)
+}
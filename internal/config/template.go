@@ -0,0 +1,483 @@
+package config
+
+import (
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// templateData holds the defaults documented in the generated config template.
+type templateData struct {
+	KeepDaily   int
+	KeepWeekly  int
+	KeepMonthly int
+
+	WOLBroadcastIP   string
+	WOLPort          int
+	WOLTimeout       string
+	WOLPollInterval  string
+	WOLStabilizeWait string
+	WOLPacketCount   int
+	WOLHTTPTimeout   string
+
+	BackupRetryDelay string
+
+	NetworkTimeout      string
+	NetworkPollInterval string
+
+	PostgresPort     int
+	PostgresUsername string
+	PostgresFormat   string
+
+	MySQLHost     string
+	MySQLPort     int
+	MySQLUsername string
+
+	SSHPort           int
+	SSHUsername       string
+	SSHShutdownDelay  int
+	SSHOS             string
+	SSHVerifyInterval string
+
+	TelegramParseMode   string
+	TelegramMaxRetries  int
+	TelegramHTTPTimeout string
+
+	PushoverPriority int
+
+	WebhookMethod string
+
+	NtfyServer string
+
+	EmailSMTPPort int
+}
+
+// WriteTemplate writes a fully commented example configuration.
+// Defaults in the template come from the same constants the parser applies.
+func WriteTemplate(w io.Writer) error {
+	tmpl, err := template.New("config").Parse(configTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, templateData{
+		KeepDaily:   DefaultKeepDaily,
+		KeepWeekly:  DefaultKeepWeekly,
+		KeepMonthly: DefaultKeepMonthly,
+
+		WOLBroadcastIP:   DefaultWOLBroadcastIP,
+		WOLPort:          DefaultWOLPort,
+		WOLTimeout:       formatDuration(DefaultWOLTimeout),
+		WOLPollInterval:  formatDuration(DefaultWOLPollInterval),
+		WOLStabilizeWait: formatDuration(DefaultWOLStabilizeWait),
+		WOLPacketCount:   DefaultWOLPacketCount,
+		WOLHTTPTimeout:   formatDuration(DefaultWOLHTTPTimeout),
+
+		BackupRetryDelay: formatDuration(DefaultBackupRetryDelay),
+
+		NetworkTimeout:      formatDuration(DefaultNetworkTimeout),
+		NetworkPollInterval: formatDuration(DefaultNetworkPollInterval),
+
+		PostgresPort:     DefaultPostgresPort,
+		PostgresUsername: DefaultPostgresUsername,
+		PostgresFormat:   DefaultPostgresFormat,
+
+		MySQLHost:     DefaultMySQLHost,
+		MySQLPort:     DefaultMySQLPort,
+		MySQLUsername: DefaultMySQLUsername,
+
+		SSHPort:           DefaultSSHPort,
+		SSHUsername:       DefaultSSHUsername,
+		SSHShutdownDelay:  DefaultSSHShutdownDelay,
+		SSHOS:             DefaultSSHOS,
+		SSHVerifyInterval: formatDuration(DefaultSSHVerifyInterval),
+
+		TelegramParseMode:   DefaultTelegramParseMode,
+		TelegramMaxRetries:  DefaultTelegramMaxRetries,
+		TelegramHTTPTimeout: formatDuration(DefaultTelegramHTTPTimeout),
+
+		PushoverPriority: DefaultPushoverPriority,
+
+		WebhookMethod: DefaultWebhookMethod,
+
+		NtfyServer: DefaultNtfyServer,
+
+		EmailSMTPPort: DefaultEmailSMTPPort,
+	})
+}
+
+// formatDuration renders a duration the way it is written in YAML, e.g. 5m instead of 5m0s.
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+const configTemplate = `# gorestic-homelab configuration
+# Generated by "gorestic-homelab generate-config". Adjust the values and
+# uncomment the optional sections you need.
+
+# Include shared settings from other files (optional)
+# Included files are merged first, then this file; later files win.
+# Relative paths are resolved against this file's directory.
+# include:
+#   - "common.yaml"
+
+# Restic repository configuration (required)
+restic:
+  # Repository URL - supports local, rest, s3, b2, sftp, etc.
+  repository: "rest:http://192.168.1.100:8000/backup/"
+
+  # Repository password - can use environment variable
+  password: "${RESTIC_PASSWORD}"
+
+  # Optional: Fail if repository is locked (default: true)
+  # Set to false to auto-remove stale locks from interrupted backups
+  # fail_on_locked: true
+
+  # Optional: Restic cache location (RESTIC_CACHE_DIR), or disable the cache
+  # entirely with no_cache, e.g. on diskless nodes. Not both.
+  # cache_dir: "/var/cache/restic"
+  # no_cache: true
+
+  # Optional: Backup performance tuning (restic defaults when omitted)
+  # pack_size: 64          # target pack size in MiB (4-128)
+  # read_concurrency: 8    # files read in parallel, useful for fast SSDs
+  # compression: "auto"    # auto, off or max
+
+  # Optional: REST server authentication
+  # rest_user: "backup"
+  # rest_password: "${REST_PASSWORD}"
+
+  # Optional: TLS for servers with a self-signed certificate. Prefer cacert
+  # (must exist) over insecure_tls, which skips certificate verification.
+  # cacert: "/etc/gorestic/rest-server-ca.pem"
+  # insecure_tls: true
+
+  # Optional: rclone repositories (rclone:remote:path). rclone_config sets
+  # RCLONE_CONFIG (must exist); rclone_args replaces the arguments rclone is
+  # started with and must include "serve restic --stdio".
+  # rclone_config: "/etc/gorestic/rclone.conf"
+  # rclone_args: "serve restic --stdio --drive-use-trash=false"
+
+  # Optional: Cloud backend credentials
+  # s3:
+  #   access_key_id: "${AWS_ACCESS_KEY_ID}"
+  #   secret_access_key: "${AWS_SECRET_ACCESS_KEY}"
+  #   region: "us-east-1"
+  # b2:
+  #   account_id: "${B2_ACCOUNT_ID}"
+  #   account_key: "${B2_ACCOUNT_KEY}"
+  # azure:
+  #   account_name: "${AZURE_ACCOUNT_NAME}"
+  #   account_key: "${AZURE_ACCOUNT_KEY}"
+
+  # Optional: Any additional environment variables for restic
+  # env:
+  #   GOOGLE_PROJECT_ID: "my-project"
+
+# Backup configuration (required)
+backup:
+  # Paths to back up
+  paths:
+    - /data
+    - /home
+
+  # Optional: Tags for this backup
+  # Placeholders are expanded per run: {date} (2024-01-15), {time} (03-00-00),
+  # {weekday} (monday) and {host}
+  tags:
+    - daily
+    - automated
+
+  # Optional: Override hostname (defaults to $GORESTIC_HOSTNAME, then the
+  # system hostname)
+  # host: "myserver"
+
+  # Optional: Appended to the host, e.g. when several machines share one config.
+  # {hostname} is replaced with the system hostname.
+  # host_suffix: "-{hostname}"
+
+  # Optional: Exclude patterns (passed to restic as --exclude)
+  # excludes:
+  #   - "*.tmp"
+  #   - "/home/*/.cache"
+
+  # Optional: Skip directories containing a CACHEDIR.TAG file
+  # exclude_caches: true
+
+  # Optional: Skip directories containing any of these files
+  # exclude_if_present:
+  #   - ".nobackup"
+
+  # Optional: Don't cross filesystem boundaries, e.g. into mounted network shares
+  # one_file_system: true
+
+  # Optional: Fail the backup if a path is missing, e.g. a mount that did not
+  # come up (default: true), or additionally if it is empty (default: false)
+  # require_paths_exist: true
+  # require_non_empty: true
+
+  # Optional: Fail the run if a backup adds fewer bytes or processes fewer
+  # files than this, e.g. after a misconfigured exclude (default: 0, disabled)
+  # min_data_added: 1048576
+  # min_files_processed: 1000
+
+  # Optional: Skip the run if this host has a snapshot younger than this, e.g.
+  # when a timer fires twice. Override with run --force (default: 0, disabled)
+  # min_interval: 12h
+
+  # Optional: Retry a backup that failed with a network error such as a timeout
+  # or a refused connection (default: 0, no retries). The delay before the first
+  # retry is doubled after each attempt.
+  # retries: 3
+  # retry_delay: {{.BackupRetryDelay}}
+
+  # Optional: Keep the latest backup progress (percent, files and bytes done)
+  # in this JSON file while restic runs, e.g. for a dashboard
+  # progress_file: "/var/lib/gorestic/progress.json"
+
+  # Optional: Read exclude patterns from a file (must exist)
+  # exclude_file: "/etc/gorestic/excludes.txt"
+
+  # Optional: Read the list of paths to back up from a file (must exist).
+  # When set, paths above are not passed to restic.
+  # files_from: "/etc/gorestic/files.txt"
+
+  # Optional: Additional snapshots, each with its own paths and tags.
+  # The settings above (host, excludes, ...) apply to every target, and
+  # paths may be omitted when targets are set.
+  # targets:
+  #   - paths: ["/etc"]
+  #     tags: ["config"]
+  #   - paths: ["/var/lib/docker/volumes"]
+  #     tags: ["docker"]
+
+# Retention policy (optional, defaults shown)
+# Defaults only apply when no retention key is set at all.
+retention:
+  keep_daily: {{.KeepDaily}}
+  keep_weekly: {{.KeepWeekly}}
+  keep_monthly: {{.KeepMonthly}}
+  # keep_last: 3
+  # keep_hourly: 24
+  # keep_yearly: 2
+  # keep_within: "30d"  # keep all snapshots within this duration
+  # keep_tags:  # never forget snapshots with any of these tags
+  #   - permanent
+
+  # Only forget snapshots with any of these tags, e.g. when several hosts
+  # share one repository (optional, default: all snapshots)
+  # tags:
+  #   - homelab
+  # group_by: "host,tags"  # how snapshots are grouped for the keep rules (default: restic's "host,paths")
+
+  # Skip forget and prune when the backup created no snapshot (default: false)
+  # skip_when_unchanged: true
+
+  # Prune unreferenced data after forget (optional, default: disabled)
+  # prune:
+  #   enabled: true
+  #   max_unused: "5%"  # allowed unused space before repacking
+
+# Repository check settings (optional)
+check:
+  enabled: true
+  subset: "5%"  # Check 5% of data each run
+  # read_data: true  # read all data instead of a subset (slow)
+
+# Copy new snapshots to a second repository after each backup (optional)
+# e.g. an offsite repository for 3-2-1 backups. Runs "restic copy" and
+# initializes the destination first if it does not exist yet. Backend
+# credentials are set like under restic; restic copy shares one environment,
+# so an env variable set differently for both repositories is an error
+# copy_to:
+#   enabled: true  # default when the block is present
+#   repository: "rest:https://offsite.example.com:8000/homelab"
+#   password: "${RESTIC_COPY_PASSWORD}"
+#   rest_user: "offsite"
+#   rest_password: "${RESTIC_COPY_REST_PASSWORD}"
+#   # s3, b2, azure and env as under restic
+
+# Lock file preventing concurrent runs (optional)
+# Default: gorestic-<repo-hash>.lock in the system temp dir
+# lock_file: "/var/run/gorestic-homelab.lock"
+
+# Parent directory for transient database dumps (optional)
+# Each run uses its own subdirectory, removed when the run ends
+# Default: the system temp dir
+# temp_dir: "/var/tmp/gorestic"
+
+# Discord notification (optional)
+# discord:
+#   webhook_url: "${DISCORD_WEBHOOK_URL}"
+
+# Slack notification via incoming webhook (optional)
+# slack:
+#   webhook_url: "${SLACK_WEBHOOK_URL}"
+#   channel: "#backups"  # optional
+
+# ntfy push notification (optional); failures are sent with high priority
+# ntfy:
+#   server: "{{.NtfyServer}}"  # default, or your self-hosted server
+#   topic: "homelab-backups"
+#   token: "${NTFY_TOKEN}"  # optional, for protected topics
+
+# Plain-text email report via SMTP (optional)
+# email:
+#   smtp_host: "smtp.example.com"
+#   smtp_port: {{.EmailSMTPPort}}  # STARTTLS is used when offered
+#   username: "${SMTP_USERNAME}"  # optional
+#   password: "${SMTP_PASSWORD}"
+#   from: "backup@example.com"
+#   to:
+#     - "admin@example.com"
+#   use_tls: false  # true for implicit TLS, usually on port 465
+
+# Generic JSON webhook notification (optional)
+# webhook:
+#   url: "https://example.com/hooks/backup"
+#   method: "{{.WebhookMethod}}"
+#   headers:
+#     Authorization: "Bearer ${WEBHOOK_TOKEN}"
+
+# Healthchecks.io dead-man-switch pings (optional)
+# healthcheck:
+#   ping_url: "https://hc-ping.com/${HEALTHCHECK_UUID}"
+
+# Log output (optional)
+# syslog sends logs to the local syslog daemon or journald with matching
+# priorities instead of stdout, like --log-syslog
+# log:
+#   output: "syslog"  # stdout (default) or syslog
+
+# Prometheus textfile metrics for node_exporter (optional)
+# metrics_file: "/var/lib/node_exporter/textfile_collector/gorestic.prom"
+
+# Hook commands (optional)
+# Run through "sh -c"; a failing pre-hook aborts the backup,
+# post-hooks always run and failures are only logged
+# hooks:
+#   pre:
+#     - "docker stop nextcloud"
+#   post:
+#     - "docker start nextcloud"
+
+# Wait for the network before the run (optional)
+# Useful when the backup starts at boot before DNS or routes are up.
+# The run fails with step "network" if the address is not reachable in time.
+# network:
+#   wait: "offsite.example.com:443"  # host:port or http(s):// URL
+#   timeout: {{.NetworkTimeout}}         # max time to wait
+#   poll_interval: {{.NetworkPollInterval}}   # pause between connection attempts
+
+# Wake-on-LAN configuration (optional)
+# Uncomment to enable WOL before backup
+# wol:
+#   mac_address: "AA:BB:CC:DD:EE:FF"
+#   mac_addresses:     # optional, a packet is sent to each address
+#     - "AA:BB:CC:DD:EE:01"
+#   packet_count: 3    # repeat the magic packet for lossy links (default: {{.WOLPacketCount}})
+#   packet_interval: 1s # pause between repeated packets
+#   broadcast_ip: "192.168.1.255"  # defaults to {{.WOLBroadcastIP}}, IPv6 e.g. "ff02::1%eth0"
+#   port: {{.WOLPort}}            # UDP port for magic packets, some devices listen on 7
+#   poll_url: "http://192.168.1.100:8000"  # URL to poll until target is ready
+#   expect_status: 200 # status meaning ready, default accepts any 2xx/3xx
+#   timeout: {{.WOLTimeout}}        # max time to wait
+#   poll_interval: {{.WOLPollInterval}} # how often to check poll_url
+#   http_timeout: {{.WOLHTTPTimeout}}   # timeout of a single poll request
+#   stabilize_wait: {{.WOLStabilizeWait}} # wait after target responds
+
+# PostgreSQL dump configuration (optional)
+# Uncomment to backup PostgreSQL database before restic backup
+# postgres:
+#   host: "192.168.1.100"
+#   port: {{.PostgresPort}}
+#   database: "myapp"
+#   databases:         # optional, dump several databases (one file each)
+#     - "nextcloud"
+#     - "immich"
+#   username: "{{.PostgresUsername}}"
+#   password: "${POSTGRES_PASSWORD}"
+#   format: "{{.PostgresFormat}}"  # custom (default), plain, tar, directory
+#   jobs: 4  # parallel dump jobs (pg_dump -j), only with format: directory
+#   parallelism: 2  # databases dumped at the same time (default: 1)
+#   compression_level: 6  # pg_dump -Z, 0-9 (0 disables compression, unset keeps the pg_dump default)
+#   exclude_tables:  # pg_dump -T, patterns such as "audit_*" are allowed
+#     - "public.logs"
+#   include_tables: []  # pg_dump -t, dump only these tables
+#   exclude_schemas: []  # pg_dump -N
+#   sslmode: "require"  # disable, allow, prefer, require, verify-ca, verify-full
+#   sslrootcert: "/etc/ssl/pg-ca.crt"  # CA certificate for verify-ca / verify-full
+#   dump_globals: true  # also dump roles and tablespaces into globals.sql
+#   keep_local: 3  # keep the newest 3 dumps per database after the backup (default: 0, delete)
+#   local_dir: "/var/backups/postgres"  # where kept dumps are stored, required with keep_local
+#   min_version: 16  # fail if the pg_dump major version is older (default: 0, no check)
+#   stream_to_restic: true  # pipe pg_dump into restic backup --stdin, no local dump file (default: false)
+
+# MySQL/MariaDB dump configuration (optional)
+# Uncomment to backup a MySQL or MariaDB database before restic backup
+# mysql:
+#   host: "192.168.1.100"  # default: {{.MySQLHost}}
+#   port: {{.MySQLPort}}             # default: {{.MySQLPort}}
+#   database: "nextcloud"
+#   username: "backup"     # default: {{.MySQLUsername}}
+#   password: "${MYSQL_PASSWORD}"
+
+# SQLite snapshot configuration (optional)
+# Uncomment to snapshot SQLite databases (via sqlite3 .backup) before restic backup
+# sqlite:
+#   databases:
+#     - "/srv/vaultwarden/db.sqlite3"
+#   output_dir: "/var/tmp/sqlite"  # defaults to the system temp dir
+
+# SSH shutdown configuration (optional)
+# Uncomment to shutdown remote server after backup
+# ssh_shutdown:
+#   host: "192.168.1.100"
+#   port: {{.SSHPort}}
+#   username: "{{.SSHUsername}}"
+#   key_path: "${HOME}/.ssh/id_rsa"
+#   shutdown_delay: {{.SSHShutdownDelay}}  # minutes before shutdown
+#   os: "{{.SSHOS}}"        # linux (default) or windows
+#   action: "shutdown" # shutdown (default) or reboot
+#   command: "systemctl poweroff"  # overrides the command built from action and os
+#   pre_commands:      # run before the shutdown, each must succeed
+#     - "docker stop immich"
+#   ignore_pre_errors: false  # shut down even if a pre-command fails
+#   verify_down:       # confirm the host went down (or just "verify_down: true")
+#     url: "http://192.168.1.100:5000"  # HTTP check, TCP connect to host:port if omitted
+#     timeout: 6m      # default: shutdown_delay + 5m
+#     interval: {{.SSHVerifyInterval}}
+#   strict_host_key_checking: true  # verify the host key against known_hosts
+#   known_hosts_path: "${HOME}/.ssh/known_hosts"  # default when strict checking is on
+
+# Notification filter applied to all notifiers (optional)
+# notify:
+#   on: "failure"  # always (default), failure, or success
+#   before_shutdown: true  # notify before the SSH shutdown instead of after it (default: false)
+
+# Telegram notification configuration (optional)
+# Uncomment to receive backup notifications via Telegram
+# telegram:
+#   bot_token: "${TELEGRAM_BOT_TOKEN}"
+#   chat_id: "${TELEGRAM_CHAT_ID}"
+#   chat_ids:           # optional, additional chats
+#     - "-100123456789"
+#   parse_mode: "{{.TelegramParseMode}}"  # HTML (default) or MarkdownV2
+#   max_retries: {{.TelegramMaxRetries}}      # attempts on rate limits and server errors (default: {{.TelegramMaxRetries}})
+#   http_timeout: {{.TelegramHTTPTimeout}}    # timeout of a single Telegram API request (default: {{.TelegramHTTPTimeout}})
+
+# Pushover notification configuration (optional)
+# Uncomment to receive backup notifications via Pushover
+# pushover:
+#   app_token: "${PUSHOVER_APP_TOKEN}"
+#   user_key: "${PUSHOVER_USER_KEY}"
+#   priority: {{.PushoverPriority}}  # -2 (lowest) to 2 (emergency), default: {{.PushoverPriority}} (high)
+`
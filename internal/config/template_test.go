@@ -0,0 +1,50 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTemplate_Parses(t *testing.T) {
+	t.Setenv("RESTIC_PASSWORD", "secret")
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTemplate(&buf))
+
+	cfg, err := NewParser().LoadReader(buf.String())
+	require.NoError(t, err)
+	require.NoError(t, Validate(cfg))
+
+	assert.Equal(t, "secret", cfg.Restic.Password)
+	assert.True(t, cfg.Restic.FailOnLocked)
+	assert.Equal(t, []string{"/data", "/home"}, cfg.Backup.Paths)
+	assert.Equal(t, DefaultKeepDaily, cfg.Retention.KeepDaily)
+	assert.Equal(t, DefaultKeepWeekly, cfg.Retention.KeepWeekly)
+	assert.Equal(t, DefaultKeepMonthly, cfg.Retention.KeepMonthly)
+	assert.True(t, cfg.Check.Enabled)
+}
+
+func TestWriteTemplate_DocumentsDefaults(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTemplate(&buf))
+
+	out := buf.String()
+	assert.NotContains(t, out, "{{")
+	assert.Contains(t, out, "#   port: 5432\n")
+	assert.Contains(t, out, "#   timeout: 5m ")
+	assert.Contains(t, out, `#   parse_mode: "HTML"`)
+	for _, section := range []string{"restic:", "backup:", "retention:", "check:", "# wol:", "# postgres:", "# ssh_shutdown:", "# telegram:"} {
+		assert.Contains(t, out, section)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "10s", formatDuration(10*time.Second))
+	assert.Equal(t, "5m", formatDuration(5*time.Minute))
+	assert.Equal(t, "1m30s", formatDuration(90*time.Second))
+	assert.Equal(t, "2h", formatDuration(2*time.Hour))
+}
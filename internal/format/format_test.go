@@ -0,0 +1,61 @@
+package format
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBytes(t *testing.T) {
+	tests := []struct {
+		bytes    int64
+		expected string
+	}{
+		{0, "0 B"},
+		{500, "500 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KiB"},
+		{1024 * 1024, "1.0 MiB"},
+		{1024 * 1024 * 1024, "1.0 GiB"},
+		{1024 * 1024 * 1024 * 2, "2.0 GiB"},
+		{1536 * 1024, "1.5 MiB"},
+		{1 << 40, "1.0 TiB"},
+		{1<<50 - 1, "1024.0 TiB"},
+		{1 << 50, "1.0 PiB"},
+		{1<<60 - 1, "1024.0 PiB"},
+		{1 << 60, "1.0 EiB"},
+		{1<<63 - 1, "8.0 EiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Bytes(tt.bytes))
+		})
+	}
+}
+
+func TestEscapeHTML(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"hello", "hello"},
+		{"<script>", "&lt;script&gt;"},
+		{"a & b", "a &amp; b"},
+		{"<>&", "&lt;&gt;&amp;"},
+		{"normal text", "normal text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EscapeHTML(tt.input))
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 5))
+	assert.Equal(t, "shor…", Truncate("shorter", 5))
+	assert.Equal(t, "äöü…", Truncate("äöüäöü", 4))
+	assert.Equal(t, "", Truncate("text", 0))
+}
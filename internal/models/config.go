@@ -1,19 +1,56 @@
 // Package models contains the data structures used throughout gorestic-homelab.
 package models
 
+import "time"
+
 // BackupConfig holds the complete configuration for a backup run.
 type BackupConfig struct {
 	Restic      ResticConfig
 	Backup      BackupSettings
 	Retention   RetentionPolicy
 	Check       CheckSettings
+	CopyTo      *CopyToConfig      // nil if not configured
+	Network     *NetworkConfig     // nil if not configured
 	WOL         *WOLConfig         // nil if not configured
 	Postgres    *PostgresConfig    // nil if not configured
+	MySQL       *MySQLConfig       // nil if not configured
+	SQLite      *SQLiteConfig      // nil if not configured
 	SSHShutdown *SSHShutdownConfig // nil if not configured
 	Telegram    *TelegramConfig    // nil if not configured
 	Pushover    *PushoverConfig    // nil if not configured
+	Healthcheck *HealthcheckConfig // nil if not configured
+	Webhook     *WebhookConfig     // nil if not configured
+	Discord     *DiscordConfig     // nil if not configured
+	Slack       *SlackConfig       // nil if not configured
+	Ntfy        *NtfyConfig        // nil if not configured
+	Email       *EmailConfig       // nil if not configured
+	PreHooks    []string           // shell commands run before the backup
+	PostHooks   []string           // shell commands always run after the backup
+	LockFile    string             // path of the lock preventing concurrent runs
+	NotifyOn    string             // "always" (default), "failure" or "success"
+	MetricsFile string             // Prometheus textfile output, empty to disable
+	TempDir     string             // parent of the per-run directory for dumps, empty for the system temp dir
+	DryRun      bool               // set via --dry-run, not read from the config file
+	AttachLog   bool               // set via --attach-log, not read from the config file
+	Force       bool               // set via --force, ignores Backup.MinInterval
+
+	NotifyBeforeShutdown bool   // send notifications before the SSH shutdown instead of after it
+	LogOutput            string // "stdout" (default) or "syslog"
 }
 
+// Values for BackupConfig.NotifyOn.
+const (
+	NotifyAlways  = "always"
+	NotifyFailure = "failure"
+	NotifySuccess = "success"
+)
+
+// Values for BackupConfig.LogOutput.
+const (
+	LogOutputStdout = "stdout"
+	LogOutputSyslog = "syslog"
+)
+
 // ResticConfig holds restic repository configuration.
 type ResticConfig struct {
 	Repository   string
@@ -21,24 +58,145 @@ type ResticConfig struct {
 	RestUser     string // optional, for REST server auth
 	RestPassword string // optional, for REST server auth
 	FailOnLocked bool   // if true (default), fail when locks exist; if false, remove locks and continue
+	DryRun       bool   // pass --dry-run to backup and forget
+
+	// CacheDir sets RESTIC_CACHE_DIR; NoCache passes --no-cache to every command.
+	CacheDir string
+	NoCache  bool
+
+	// TLS settings for REST and S3 backends with self-signed certificates,
+	// passed as --insecure-tls and --cacert to every command.
+	InsecureTLS bool
+	CACertPath  string
+
+	// Rclone settings for rclone: repositories. RcloneConfigPath sets RCLONE_CONFIG;
+	// RcloneArgs replaces the arguments restic starts rclone with, passed as
+	// -o rclone.args=... to every command.
+	RcloneConfigPath string
+	RcloneArgs       string
+
+	// NoLock passes --no-lock to the read-only snapshots, stats and check
+	// commands. Set via --no-lock on those commands; backup and forget always lock.
+	NoLock bool
+
+	// Backup tuning, passed as --pack-size (MiB), --read-concurrency and --compression.
+	// Zero values keep the restic defaults.
+	PackSize         int
+	ReadConcurrency  int
+	CompressionLevel string // "auto", "off" or "max"
+
+	// EnvVars holds extra environment variables for restic, e.g. cloud backend
+	// credentials like AWS_ACCESS_KEY_ID or B2_ACCOUNT_ID.
+	EnvVars map[string]string
+}
+
+// CopyToConfig holds the second repository that snapshots are copied to, e.g. an
+// offsite copy for 3-2-1 backups.
+type CopyToConfig struct {
+	Enabled bool
+	ResticConfig
 }
 
 // BackupSettings holds backup-specific settings.
 type BackupSettings struct {
+	Paths         []string
+	Tags          []string
+	Host          string
+	Excludes      []string // patterns passed as --exclude
+	ExcludeCaches bool     // skip directories containing a CACHEDIR.TAG
+	ExcludeFile   string   // optional file with exclude patterns
+	FilesFrom     string   // optional file listing paths to back up (replaces Paths)
+
+	// DumpPaths are the database dumps of this run. They are backed up next
+	// to Paths or FilesFrom.
+	DumpPaths []string
+
+	// ExcludeIfPresent skips directories containing one of these files, e.g. ".nobackup".
+	ExcludeIfPresent []string
+	// OneFileSystem keeps restic from crossing filesystem boundaries such as mounts.
+	OneFileSystem bool
+
+	// RequirePathsExist fails the backup if a configured path is missing,
+	// e.g. because a mount did not come up.
+	RequirePathsExist bool
+	// RequireNonEmpty additionally fails the backup if a path is empty.
+	RequireNonEmpty bool
+
+	// MinDataAdded and MinFilesProcessed fail the run if a backup adds fewer
+	// bytes or processes fewer files, e.g. due to a misconfigured exclude.
+	// Zero disables the check.
+	MinDataAdded      int64
+	MinFilesProcessed int
+
+	// MinInterval skips the run if the newest snapshot of Host is younger,
+	// e.g. to avoid double backups when a timer fires twice. Zero disables the check.
+	MinInterval time.Duration
+
+	// Retries is the number of times a backup failing with a network error is
+	// retried. RetryDelay is the wait before the first retry, doubled after each one.
+	Retries    int
+	RetryDelay time.Duration
+
+	// StdinFilename names the file of a backup read from stdin, e.g. "app.dump".
+	StdinFilename string
+
+	// ProgressFile receives the latest backup progress as JSON while restic
+	// runs, e.g. for a dashboard. Empty disables it.
+	ProgressFile string
+
+	// Targets are backed up as separate snapshots, each with its own paths and tags.
+	// They share the remaining settings and can be combined with Paths.
+	Targets []BackupTarget
+}
+
+// BackupTarget is a group of paths backed up into its own snapshot.
+type BackupTarget struct {
 	Paths []string
 	Tags  []string
-	Host  string
 }
 
 // RetentionPolicy defines how many snapshots to keep.
 type RetentionPolicy struct {
+	KeepLast    int
+	KeepHourly  int
 	KeepDaily   int
 	KeepWeekly  int
 	KeepMonthly int
+	KeepYearly  int
+	KeepWithin  string   // e.g., "30d" or "1y6m"
+	KeepTags    []string // snapshots with any of these tags are never forgotten
+	Prune       PruneSettings
+
+	// SkipWhenUnchanged skips forget and prune when the backup created no snapshot.
+	SkipWhenUnchanged bool
+
+	// Tags limits forget to snapshots with any of these tags.
+	Tags []string
+	// GroupBy overrides restic's snapshot grouping, e.g. "host,tags";
+	// empty keeps restic's default.
+	GroupBy string
+}
+
+// PruneSettings defines whether and how unreferenced data is pruned.
+type PruneSettings struct {
+	Enabled   bool
+	MaxUnused string // e.g., "5%"; empty uses restic's default
+
+	// RepackCacheableOnly only repacks tree and metadata packs, set via
+	// --repack-cacheable-only on the prune command.
+	RepackCacheableOnly bool
+}
+
+// IsEmpty reports whether no retention rule is set. KeepTags does not count,
+// since forgetting with only keep tags would remove every other snapshot.
+func (r RetentionPolicy) IsEmpty() bool {
+	return r.KeepLast == 0 && r.KeepHourly == 0 && r.KeepDaily == 0 &&
+		r.KeepWeekly == 0 && r.KeepMonthly == 0 && r.KeepYearly == 0 && r.KeepWithin == ""
 }
 
 // CheckSettings defines repository check behavior.
 type CheckSettings struct {
-	Enabled bool
-	Subset  string // e.g., "1%"
+	Enabled  bool
+	Subset   string // e.g., "1%"
+	ReadData bool   // read all pack files (--read-data), overrides Subset
 }
@@ -0,0 +1,12 @@
+package models
+
+// DiscordConfig holds Discord webhook notification configuration.
+type DiscordConfig struct {
+	WebhookURL string
+}
+
+// DiscordResult holds the result of a Discord notification.
+type DiscordResult struct {
+	MessageSent bool
+	Error       error
+}
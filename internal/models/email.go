@@ -0,0 +1,18 @@
+package models
+
+// EmailConfig holds SMTP email notification configuration.
+type EmailConfig struct {
+	SMTPHost string
+	SMTPPort int
+	Username string // optional, enables SMTP AUTH PLAIN
+	Password string
+	From     string
+	To       []string
+	UseTLS   bool // connect with implicit TLS (usually port 465) instead of STARTTLS
+}
+
+// EmailResult holds the result of an email notification.
+type EmailResult struct {
+	MessageSent bool
+	Error       error
+}
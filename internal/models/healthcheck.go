@@ -0,0 +1,25 @@
+package models
+
+// HealthcheckConfig holds healthchecks.io (or compatible) ping configuration.
+type HealthcheckConfig struct {
+	PingURL string
+}
+
+// HealthcheckStatus is the state reported by a ping.
+type HealthcheckStatus string
+
+// Healthcheck ping states.
+const (
+	HealthcheckStart   HealthcheckStatus = "start"
+	HealthcheckSuccess HealthcheckStatus = "success"
+	HealthcheckFail    HealthcheckStatus = "fail"
+	// HealthcheckSkipped is a success ping whose body notes that the run was
+	// skipped, ending the started run without recording a backup.
+	HealthcheckSkipped HealthcheckStatus = "skipped"
+)
+
+// HealthcheckResult holds the result of a healthcheck ping.
+type HealthcheckResult struct {
+	Pinged bool
+	Error  error
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// HookResult holds the result of a hook command.
+type HookResult struct {
+	Command  string
+	Output   string
+	Duration time.Duration
+	Error    error
+}
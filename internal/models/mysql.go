@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// MySQLConfig holds MySQL/MariaDB dump configuration.
+type MySQLConfig struct {
+	Host     string
+	Port     int
+	Database string
+	Username string
+	Password string
+}
+
+// MySQLDumpResult holds the result of a mysqldump operation.
+type MySQLDumpResult struct {
+	OutputPath string
+	SizeBytes  int64
+	Duration   time.Duration
+	Error      error
+}
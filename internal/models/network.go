@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// NetworkConfig holds the network readiness check run before anything else.
+type NetworkConfig struct {
+	Address      string        // host:port that must accept TCP connections
+	Timeout      time.Duration // max time to wait for the address
+	PollInterval time.Duration // pause between connection attempts
+}
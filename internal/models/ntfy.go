@@ -0,0 +1,14 @@
+package models
+
+// NtfyConfig holds ntfy push notification configuration.
+type NtfyConfig struct {
+	Server string // base URL of the ntfy server, e.g. https://ntfy.sh
+	Topic  string
+	Token  string // optional access token for protected topics
+}
+
+// NtfyResult holds the result of an ntfy notification.
+type NtfyResult struct {
+	MessageSent bool
+	Error       error
+}
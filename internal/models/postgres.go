@@ -4,12 +4,56 @@ import "time"
 
 // PostgresConfig holds PostgreSQL dump configuration.
 type PostgresConfig struct {
-	Host     string
-	Port     int
-	Database string
-	Username string
-	Password string
-	Format   string // "custom" (default), "plain", "tar"
+	Host      string
+	Port      int
+	Database  string
+	Databases []string // dumped one file each; includes Database when both are set
+	Username  string
+	Password  string
+	Format    string // "custom" (default), "plain", "tar", "directory"
+
+	// Jobs sets pg_dump -j for parallel dumps (directory format only).
+	Jobs int
+	// Parallelism is the number of databases dumped at the same time; 0 or 1
+	// dumps them one after another.
+	Parallelism int
+	// CompressionLevel sets pg_dump -Z (0-9); nil keeps the pg_dump default.
+	CompressionLevel *int
+
+	// SSLMode and SSLRootCert are passed to libpq as PGSSLMODE and PGSSLROOTCERT.
+	SSLMode     string // e.g. "require", "verify-full"; empty keeps the libpq default
+	SSLRootCert string
+
+	// Table and schema filters, passed to pg_dump as repeated -T, -t and -N flags.
+	ExcludeTables  []string
+	IncludeTables  []string
+	ExcludeSchemas []string
+
+	// DumpGlobals additionally dumps roles and tablespaces via pg_dumpall --globals-only.
+	DumpGlobals bool
+
+	// KeepLocal keeps the newest N dumps per database in LocalDir after the backup
+	// instead of deleting them. 0 deletes the dumps.
+	KeepLocal int
+	LocalDir  string
+
+	// MinVersion fails the run if the pg_dump major version is older. 0 disables the check.
+	MinVersion int
+
+	// StreamToRestic pipes each pg_dump straight into restic backup --stdin
+	// instead of writing a dump file first. Not with the directory format.
+	StreamToRestic bool
+}
+
+// DatabaseNames returns the databases to dump.
+func (c PostgresConfig) DatabaseNames() []string {
+	if len(c.Databases) > 0 {
+		return c.Databases
+	}
+	if c.Database != "" {
+		return []string{c.Database}
+	}
+	return nil
 }
 
 // PostgresDumpResult holds the result of a pg_dump operation.
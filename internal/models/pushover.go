@@ -12,6 +12,7 @@ type PushoverConfig struct {
 // PushoverMessage holds the data for a backup notification.
 type PushoverMessage struct {
 	Success    bool
+	DryRun     bool
 	Host       string
 	Repository string
 	StartTime  time.Time
@@ -30,6 +31,10 @@ type PushoverMessage struct {
 	SnapshotsRemoved int
 	SnapshotsKept    int
 
+	// Repository stats, zero if they were not collected.
+	RepoTotalSize int64
+	SnapshotCount int
+
 	// Error info (if failed).
 	ErrorMessage string
 	FailedStep   string
@@ -2,6 +2,19 @@ package models
 
 import "time"
 
+// ErrorKind is the category of a failed restic command.
+type ErrorKind string
+
+const (
+	ErrorKindUnknown        ErrorKind = "unknown"
+	ErrorKindLocked         ErrorKind = "locked"          // another process holds an exclusive lock
+	ErrorKindNotInitialized ErrorKind = "not_initialized" // no repository at the configured location
+	ErrorKindWrongPassword  ErrorKind = "wrong_password"  // no key matches the repository password
+	ErrorKindNetwork        ErrorKind = "network"         // the repository backend could not be reached
+	ErrorKindCorruption     ErrorKind = "corruption"      // damaged or missing repository data
+	ErrorKindDiskFull       ErrorKind = "disk_full"       // no space left on the target or cache
+)
+
 // BackupResult holds the result of a backup operation.
 type BackupResult struct {
 	SnapshotID          string
@@ -13,6 +26,38 @@ type BackupResult struct {
 	TotalBytesProcessed int64
 	Duration            time.Duration
 	Error               error
+	ErrorKind           ErrorKind // set if Error is set
+
+	// Warnings holds restic's warning messages, plus the files it could
+	// not read when the backup still created a snapshot (exit code 3).
+	Warnings []string
+	// Errors holds restic's JSON error messages, one per file or
+	// directory that could not be backed up.
+	Errors []string
+}
+
+// InitResult holds the result of a repository initialization.
+type InitResult struct {
+	// Created is true if the repository was newly initialized and false if it already existed.
+	Created bool
+}
+
+// UnlockResult holds the result of an unlock operation.
+type UnlockResult struct {
+	LocksRemoved int
+}
+
+// CopyOptions selects the snapshots copied to the destination repository.
+// Empty filters copy all snapshots; snapshots already present are skipped by restic.
+type CopyOptions struct {
+	Host string
+	Tags []string
+}
+
+// CopyResult holds the result of a copy operation.
+type CopyResult struct {
+	Duration time.Duration
+	Error    error
 }
 
 // ForgetResult holds the result of a forget operation.
@@ -22,22 +67,82 @@ type ForgetResult struct {
 	SpaceFreed       int64
 	Duration         time.Duration
 	Error            error
+	ErrorKind        ErrorKind // set if Error is set
 }
 
-// CheckResult holds the result of a repository check.
-type CheckResult struct {
-	Passed   bool
+// PruneResult holds the result of a prune operation.
+type PruneResult struct {
+	SpaceFreed int64 // bytes removed from the repository
+	Duration   time.Duration
+	Error      error
+}
+
+// RepairResult holds the result of a repository repair operation.
+type RepairResult struct {
 	Duration time.Duration
 	Error    error
 }
 
+// CheckResult holds the result of a repository check.
+type CheckResult struct {
+	Passed    bool
+	Duration  time.Duration
+	Error     error
+	ErrorKind ErrorKind // set if Error is set
+}
+
+// StatsMode selects how restic stats counts the repository contents.
+type StatsMode string
+
+const (
+	StatsModeRestoreSize     StatsMode = "restore-size"      // size of the files when restored
+	StatsModeRawData         StatsMode = "raw-data"          // size of the stored blobs
+	StatsModeFilesByContents StatsMode = "files-by-contents" // size of the unique files
+)
+
+// StatsResult holds repository statistics from restic stats.
+type StatsResult struct {
+	Mode           StatsMode
+	TotalSize      int64
+	TotalFileCount int
+	SnapshotCount  int
+}
+
+// DiffResult holds the differences between two snapshots from restic diff.
+type DiffResult struct {
+	SnapshotA    string
+	SnapshotB    string
+	FilesAdded   int
+	FilesRemoved int
+	FilesChanged int
+	DirsAdded    int
+	DirsRemoved  int
+	BytesAdded   uint64
+	BytesRemoved uint64
+}
+
 // Snapshot represents a restic snapshot.
 type Snapshot struct {
-	ID       string
-	Time     time.Time
-	Hostname string
-	Tags     []string
-	Paths    []string
+	ID       string    `json:"id"`
+	Time     time.Time `json:"time"`
+	Hostname string    `json:"hostname"`
+	Tags     []string  `json:"tags"`
+	Paths    []string  `json:"paths"`
+}
+
+// SnapshotGroup holds the snapshots sharing a group key, as listed by
+// restic snapshots --group-by. Fields that are not grouped by are empty.
+type SnapshotGroup struct {
+	Hostname  string     `json:"hostname,omitempty"`
+	Paths     []string   `json:"paths,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	Snapshots []Snapshot `json:"snapshots"`
+}
+
+// SnapshotFilter narrows down which snapshots are listed.
+type SnapshotFilter struct {
+	Tags []string // only snapshots with these tags
+	Host string   // only snapshots from this host
 }
 
 // BackupProgress for restic status messages during backup.
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// RunSummary holds the outcome of a backup run.
+type RunSummary struct {
+	Success    bool
+	DryRun     bool
+	FailedStep string
+	Duration   time.Duration
+
+	// Backup stats (zero if the backup step did not complete).
+	SnapshotID string
+	FilesNew   int
+	DataAdded  int64
+
+	// Retention stats (zero if the forget step did not complete).
+	SnapshotsKept    int
+	SnapshotsRemoved int
+
+	ErrorMessage string
+
+	// StepTimings holds the elapsed time per executed step, e.g. "backup",
+	// including the step that failed.
+	StepTimings map[string]time.Duration
+}
@@ -0,0 +1,13 @@
+package models
+
+// SlackConfig holds Slack incoming webhook configuration.
+type SlackConfig struct {
+	WebhookURL string
+	Channel    string // optional, overrides the webhook's default channel
+}
+
+// SlackResult holds the result of a Slack notification.
+type SlackResult struct {
+	MessageSent bool
+	Error       error
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SQLiteConfig holds SQLite snapshot configuration.
+type SQLiteConfig struct {
+	Databases []string // paths of the database files to snapshot
+	OutputDir string   // where snapshots are written; the runner's temp dir if empty
+}
+
+// SQLiteBackupResult holds the result of snapshotting the configured databases.
+type SQLiteBackupResult struct {
+	OutputPaths []string // snapshots created, also set when a later database failed
+	SizeBytes   int64
+	Duration    time.Duration
+	Error       error
+}
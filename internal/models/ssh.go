@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // SSHShutdownConfig holds SSH shutdown configuration.
 type SSHShutdownConfig struct {
 	Host          string
@@ -9,11 +11,46 @@ type SSHShutdownConfig struct {
 	KeyPath       string // path to key file
 	ShutdownDelay int    // seconds before shutdown (Linux: minutes, Windows: seconds)
 	OS            string // "linux" (default) or "windows"
+
+	Action          string // "shutdown" (default) or "reboot"
+	ShutdownCommand string // run verbatim instead of the command built from Action and OS
+
+	// PreShutdownCommands run in order before the shutdown, each in its own session.
+	// A failing command aborts the shutdown unless IgnorePreErrors is set.
+	PreShutdownCommands []string
+	IgnorePreErrors     bool
+
+	// VerifyDown polls the host after the shutdown command until it stops
+	// responding, via HTTP GET of VerifyDownURL or a TCP connect to Host:Port.
+	VerifyDown     bool
+	VerifyDownURL  string
+	VerifyTimeout  time.Duration // must cover ShutdownDelay
+	VerifyInterval time.Duration
+
+	// StrictHostKeyChecking verifies the server key against KnownHostsPath.
+	// When disabled any host key is accepted.
+	StrictHostKeyChecking bool
+	KnownHostsPath        string
 }
 
+// Values for SSHShutdownConfig.Action.
+const (
+	SSHActionShutdown = "shutdown"
+	SSHActionReboot   = "reboot"
+)
+
 // SSHResult holds the result of an SSH operation.
 type SSHResult struct {
-	CommandRun bool
-	Output     string
-	Error      error
+	CommandRun  bool
+	Confirmed   bool // host stopped responding, only checked with VerifyDown
+	Output      string
+	PreCommands []SSHCommandResult
+	Error       error
+}
+
+// SSHCommandResult holds the result of a single remote command.
+type SSHCommandResult struct {
+	Command string
+	Output  string
+	Error   error
 }
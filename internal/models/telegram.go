@@ -4,13 +4,19 @@ import "time"
 
 // TelegramConfig holds Telegram notification configuration.
 type TelegramConfig struct {
-	BotToken string
-	ChatID   string
+	BotToken   string
+	ChatIDs    []string
+	ParseMode  string // "HTML" (default) or "MarkdownV2"
+	MaxRetries int    // maximum send attempts on 429/5xx/network errors
+
+	HTTPTimeout time.Duration // timeout of a single API request
 }
 
 // TelegramMessage holds the data for a backup notification.
 type TelegramMessage struct {
 	Success    bool
+	DryRun     bool
+	Skipped    bool // no backup was made because a recent snapshot exists
 	Host       string
 	Repository string
 	StartTime  time.Time
@@ -24,18 +30,30 @@ type TelegramMessage struct {
 	DataAdded       int64
 	TotalFiles      int
 	TotalBytes      int64
+	UnreadableFiles int // files restic reported errors for
+
+	ThroughputBytesPerSec int64 // bytes processed per second of backup, zero if unknown
 
 	// Retention stats.
 	SnapshotsRemoved int
 	SnapshotsKept    int
+	SpaceFreed       int64 // bytes freed by prune
+
+	// Repository stats (zero if not collected).
+	RepoTotalSize int64
+	RepoFileCount int
+	SnapshotCount int // snapshots in the repository after retention
 
 	// Error info (if failed).
 	ErrorMessage string
 	FailedStep   string
+	LogExcerpt   string // last lines of the failed command's output, set with --attach-log
 }
 
 // TelegramResult holds the result of a Telegram notification.
 type TelegramResult struct {
-	MessageSent bool
-	Error       error
+	MessageSent  bool
+	MessagesSent int // messages delivered over all chats; long texts are sent in several parts
+	Attempts     int
+	Error        error
 }
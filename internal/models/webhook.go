@@ -0,0 +1,14 @@
+package models
+
+// WebhookConfig holds generic webhook notification configuration.
+type WebhookConfig struct {
+	URL     string
+	Method  string
+	Headers map[string]string
+}
+
+// WebhookResult holds the result of a webhook notification.
+type WebhookResult struct {
+	Sent  bool
+	Error error
+}
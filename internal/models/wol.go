@@ -5,16 +5,35 @@ import "time"
 // WOLConfig holds Wake-on-LAN configuration.
 type WOLConfig struct {
 	MACAddress    string
+	MACAddresses  []string // woken one after another; includes MACAddress when both are set
 	BroadcastIP   string
+	Port          int           // UDP port for magic packets, default 9
 	PollURL       string        // URL to poll until target machine is ready
 	Timeout       time.Duration // max time to wait for target
 	PollInterval  time.Duration // how often to poll the URL
+	HTTPTimeout   time.Duration // timeout of a single poll request
+	ExpectStatus  int           // HTTP status meaning ready, 0 accepts any 2xx/3xx
 	StabilizeWait time.Duration // wait after target responds
+
+	PacketCount    int           // magic packets sent per MAC, default 1
+	PacketInterval time.Duration // pause between repeated packets
+}
+
+// MACs returns the MAC addresses to wake.
+func (c WOLConfig) MACs() []string {
+	if len(c.MACAddresses) > 0 {
+		return c.MACAddresses
+	}
+	if c.MACAddress != "" {
+		return []string{c.MACAddress}
+	}
+	return nil
 }
 
 // WOLResult holds the result of a Wake-on-LAN operation.
 type WOLResult struct {
 	PacketSent   bool
+	PacketsSent  int
 	TargetReady  bool
 	WaitDuration time.Duration
 	Error        error
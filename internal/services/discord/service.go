@@ -0,0 +1,168 @@
+// Package discord provides Discord webhook notification services.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// Embed colors.
+const (
+	colorSuccess = 0x2ECC71
+	colorFailure = 0xE74C3C
+)
+
+// maxFieldValue is the length limit Discord enforces on embed field values.
+const maxFieldValue = 1024
+
+// Service defines the interface for Discord notification operations.
+type Service interface {
+	SendNotification(ctx context.Context, cfg models.DiscordConfig, msg models.TelegramMessage) (*models.DiscordResult, error)
+}
+
+// HTTPClient allows mocking HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Impl implements the Discord Service interface.
+type Impl struct {
+	httpClient HTTPClient
+	logger     zerolog.Logger
+}
+
+// New creates a new Discord service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// NewWithClient creates a new Discord service with a custom HTTP client (for testing).
+func NewWithClient(logger zerolog.Logger, httpClient HTTPClient) *Impl {
+	return &Impl{
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// webhookRequest is the request body for a Discord webhook.
+type webhookRequest struct {
+	Embeds []embed `json:"embeds"`
+}
+
+type embed struct {
+	Title     string       `json:"title"`
+	Color     int          `json:"color"`
+	Fields    []embedField `json:"fields"`
+	Timestamp string       `json:"timestamp,omitempty"`
+}
+
+type embedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// SendNotification sends a backup notification to a Discord webhook.
+func (s *Impl) SendNotification(ctx context.Context, cfg models.DiscordConfig, msg models.TelegramMessage) (*models.DiscordResult, error) {
+	result := &models.DiscordResult{}
+
+	s.logger.Info().
+		Bool("success", msg.Success).
+		Msg("sending Discord notification")
+
+	jsonBody, err := json.Marshal(webhookRequest{Embeds: []embed{buildEmbed(msg)}})
+	if err != nil {
+		result.Error = fmt.Errorf("failed to marshal request: %w", err)
+		return result, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.WebhookURL, bytes.NewReader(jsonBody))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create request: %w", err)
+		return result, nil
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to send request: %w", err)
+		return result, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	// Discord answers 204 No Content unless ?wait=true is set.
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Errorf("discord API returned status %d", resp.StatusCode)
+		return result, nil
+	}
+
+	result.MessageSent = true
+	s.logger.Info().Msg("Discord notification sent successfully")
+
+	return result, nil
+}
+
+func buildEmbed(msg models.TelegramMessage) embed {
+	e := embed{
+		Title: "Backup Successful",
+		Color: colorSuccess,
+	}
+	if !msg.Success {
+		e.Title = "Backup Failed"
+		e.Color = colorFailure
+	}
+	if msg.DryRun {
+		e.Title += " (dry-run)"
+	}
+	if !msg.StartTime.IsZero() {
+		e.Timestamp = msg.StartTime.UTC().Format(time.RFC3339)
+	}
+
+	e.Fields = append(e.Fields,
+		embedField{Name: "Host", Value: msg.Host, Inline: true},
+		embedField{Name: "Duration", Value: msg.Duration.Round(time.Second).String(), Inline: true},
+	)
+
+	if msg.Success {
+		e.Fields = append(e.Fields,
+			embedField{Name: "Snapshot", Value: "`" + msg.SnapshotID + "`", Inline: true},
+			embedField{Name: "Files", Value: fmt.Sprintf("%d new, %d changed, %d unmodified", msg.FilesNew, msg.FilesChanged, msg.FilesUnmodified)},
+			embedField{Name: "Data added", Value: format.Bytes(msg.DataAdded), Inline: true},
+		)
+		if msg.SnapshotsRemoved > 0 || msg.SnapshotsKept > 0 {
+			e.Fields = append(e.Fields, embedField{
+				Name:   "Retention",
+				Value:  strconv.Itoa(msg.SnapshotsKept) + " kept, " + strconv.Itoa(msg.SnapshotsRemoved) + " removed",
+				Inline: true,
+			})
+		}
+		if msg.RepoTotalSize > 0 {
+			e.Fields = append(e.Fields, embedField{Name: "Repository size", Value: format.Bytes(msg.RepoTotalSize), Inline: true})
+		}
+		if msg.SnapshotCount > 0 {
+			e.Fields = append(e.Fields, embedField{Name: "Snapshots in repo", Value: strconv.Itoa(msg.SnapshotCount), Inline: true})
+		}
+	} else {
+		e.Fields = append(e.Fields,
+			embedField{Name: "Failed step", Value: msg.FailedStep, Inline: true},
+			embedField{Name: "Error", Value: "```" + format.Truncate(msg.ErrorMessage, maxFieldValue-len("``````")) + "```"},
+		)
+	}
+
+	return e
+}
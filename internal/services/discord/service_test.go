@@ -0,0 +1,183 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHTTPClient struct {
+	doFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.doFunc != nil {
+		return m.doFunc(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func testConfig() models.DiscordConfig {
+	return models.DiscordConfig{
+		WebhookURL: "https://discord.com/api/webhooks/123/abc",
+	}
+}
+
+func fieldValue(t *testing.T, e embed, name string) string {
+	t.Helper()
+	for _, f := range e.Fields {
+		if f.Name == name {
+			return f.Value
+		}
+	}
+	t.Fatalf("field %q not found", name)
+	return ""
+}
+
+func TestSendNotification_Success(t *testing.T) {
+	var capturedRequest *http.Request
+	var capturedBody webhookRequest
+
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			capturedRequest = req
+			body, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(body, &capturedBody)
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+
+	msg := models.TelegramMessage{
+		Success:         true,
+		Host:            "server1",
+		StartTime:       time.Now().Add(-5 * time.Minute),
+		Duration:        5 * time.Minute,
+		SnapshotID:      "abc123",
+		FilesNew:        10,
+		FilesChanged:    5,
+		FilesUnmodified: 100,
+		DataAdded:       1024 * 1024,
+	}
+
+	result, err := svc.SendNotification(context.Background(), testConfig(), msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Nil(t, result.Error)
+
+	assert.Equal(t, http.MethodPost, capturedRequest.Method)
+	assert.Equal(t, "https://discord.com/api/webhooks/123/abc", capturedRequest.URL.String())
+	assert.Equal(t, "application/json", capturedRequest.Header.Get("Content-Type"))
+
+	require.Len(t, capturedBody.Embeds, 1)
+	e := capturedBody.Embeds[0]
+	assert.Equal(t, "Backup Successful", e.Title)
+	assert.Equal(t, colorSuccess, e.Color)
+	assert.Equal(t, "server1", fieldValue(t, e, "Host"))
+	assert.Equal(t, "`abc123`", fieldValue(t, e, "Snapshot"))
+	assert.Equal(t, "10 new, 5 changed, 100 unmodified", fieldValue(t, e, "Files"))
+	assert.Equal(t, "1.0 MiB", fieldValue(t, e, "Data added"))
+}
+
+func TestBuildEmbed_RepositorySize(t *testing.T) {
+	e := buildEmbed(models.TelegramMessage{Success: true, RepoTotalSize: 5 << 30, SnapshotCount: 42})
+
+	assert.Equal(t, "5.0 GiB", fieldValue(t, e, "Repository size"))
+	assert.Equal(t, "42", fieldValue(t, e, "Snapshots in repo"))
+}
+
+func TestBuildEmbed_Failure(t *testing.T) {
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		Duration:     30 * time.Second,
+		FailedStep:   "backup",
+		ErrorMessage: "repository not found",
+	}
+
+	e := buildEmbed(msg)
+
+	assert.Equal(t, "Backup Failed", e.Title)
+	assert.Equal(t, colorFailure, e.Color)
+	assert.Equal(t, "backup", fieldValue(t, e, "Failed step"))
+	assert.Equal(t, "```repository not found```", fieldValue(t, e, "Error"))
+	for _, f := range e.Fields {
+		assert.NotEqual(t, "Snapshot", f.Name)
+	}
+}
+
+func TestBuildEmbed_LongError(t *testing.T) {
+	msg := models.TelegramMessage{
+		FailedStep:   "backup",
+		ErrorMessage: "restic backup failed: " + strings.Repeat("x", 5000),
+	}
+
+	e := buildEmbed(msg)
+
+	value := fieldValue(t, e, "Error")
+	assert.Equal(t, maxFieldValue, utf8.RuneCountInString(value))
+	assert.True(t, strings.HasPrefix(value, "```restic backup failed: "))
+	assert.True(t, strings.HasSuffix(value, "…```"))
+}
+
+func TestBuildEmbed_DryRun(t *testing.T) {
+	e := buildEmbed(models.TelegramMessage{Success: true, DryRun: true})
+
+	assert.Equal(t, "Backup Successful (dry-run)", e.Title)
+}
+
+func TestSendNotification_HTTPError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.SendNotification(context.Background(), testConfig(), models.TelegramMessage{})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Contains(t, result.Error.Error(), "connection refused")
+}
+
+func TestSendNotification_APIError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(`{"message":"Invalid Form Body"}`)),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.SendNotification(context.Background(), testConfig(), models.TelegramMessage{})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Contains(t, result.Error.Error(), "400")
+}
@@ -0,0 +1,237 @@
+// Package email provides SMTP email notification services.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// sendTimeout bounds the whole SMTP conversation.
+const sendTimeout = 30 * time.Second
+
+// Service defines the interface for email notification operations.
+type Service interface {
+	Send(ctx context.Context, cfg models.EmailConfig, msg models.TelegramMessage) (*models.EmailResult, error)
+}
+
+// Sender allows mocking the SMTP conversation in tests.
+type Sender interface {
+	SendMail(ctx context.Context, addr string, useTLS bool, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// DefaultSender is the default sender using net/smtp.
+type DefaultSender struct{}
+
+// SendMail delivers msg to the SMTP server at addr. With useTLS the connection
+// uses implicit TLS, otherwise STARTTLS is used when the server offers it.
+func (d *DefaultSender) SendMail(ctx context.Context, addr string, useTLS bool, auth smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	tlsConfig := &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}
+
+	var conn net.Conn
+	if useTLS {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	if !useTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// Impl implements the email Service interface.
+type Impl struct {
+	sender Sender
+	logger zerolog.Logger
+}
+
+// New creates a new email service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		sender: &DefaultSender{},
+		logger: logger,
+	}
+}
+
+// NewWithSender creates a new email service with a custom sender (for testing).
+func NewWithSender(logger zerolog.Logger, sender Sender) *Impl {
+	return &Impl{
+		sender: sender,
+		logger: logger,
+	}
+}
+
+// Send emails a plain-text backup report to all configured recipients.
+func (s *Impl) Send(ctx context.Context, cfg models.EmailConfig, msg models.TelegramMessage) (*models.EmailResult, error) {
+	result := &models.EmailResult{}
+
+	s.logger.Info().
+		Bool("success", msg.Success).
+		Strs("to", cfg.To).
+		Msg("sending email notification")
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.SMTPHost)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort))
+	if err := s.sender.SendMail(ctx, addr, cfg.UseTLS, auth, cfg.From, cfg.To, buildMessage(cfg, msg)); err != nil {
+		result.Error = fmt.Errorf("failed to send email: %w", err)
+		return result, nil
+	}
+
+	result.MessageSent = true
+	s.logger.Info().Msg("email notification sent successfully")
+
+	return result, nil
+}
+
+// buildMessage renders the RFC 5322 message with CRLF line endings.
+func buildMessage(cfg models.EmailConfig, msg models.TelegramMessage) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", formatSubject(msg)))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: %s\r\n", messageID(cfg.From))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(formatBody(msg), "\n", "\r\n"))
+
+	return b.Bytes()
+}
+
+// messageID returns a random Message-ID at the domain of from, as spam
+// filters penalize mails without one.
+func messageID(from string) string {
+	domain := "localhost"
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndex(addr.Address, "@"); i >= 0 && i < len(addr.Address)-1 {
+			domain = addr.Address[i+1:]
+		}
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		// Still unique enough for a single host sending a few mails a day
+		return fmt.Sprintf("<%d@%s>", time.Now().UnixNano(), domain)
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(id), domain)
+}
+
+func formatSubject(msg models.TelegramMessage) string {
+	subject := "Backup Successful on " + msg.Host
+	if !msg.Success {
+		subject = "Backup Failed on " + msg.Host
+	}
+	if msg.DryRun {
+		subject += " (dry-run)"
+	}
+	return subject
+}
+
+func formatBody(msg models.TelegramMessage) string {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "Host: %s\n", msg.Host)
+	fmt.Fprintf(&b, "Repository: %s\n", msg.Repository)
+	fmt.Fprintf(&b, "Started: %s\n", msg.StartTime.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "Duration: %s\n", msg.Duration.Round(time.Second))
+
+	if msg.Success {
+		b.WriteString("\nBackup Statistics:\n")
+		fmt.Fprintf(&b, "  Snapshot: %s\n", msg.SnapshotID)
+		fmt.Fprintf(&b, "  Files new: %d\n", msg.FilesNew)
+		fmt.Fprintf(&b, "  Files changed: %d\n", msg.FilesChanged)
+		fmt.Fprintf(&b, "  Files unmodified: %d\n", msg.FilesUnmodified)
+		fmt.Fprintf(&b, "  Data added: %s\n", format.Bytes(msg.DataAdded))
+		fmt.Fprintf(&b, "  Total files: %d\n", msg.TotalFiles)
+		fmt.Fprintf(&b, "  Total size: %s\n", format.Bytes(msg.TotalBytes))
+
+		if msg.SnapshotsRemoved > 0 || msg.SnapshotsKept > 0 {
+			b.WriteString("\nRetention:\n")
+			fmt.Fprintf(&b, "  Snapshots kept: %d\n", msg.SnapshotsKept)
+			fmt.Fprintf(&b, "  Snapshots removed: %d\n", msg.SnapshotsRemoved)
+		}
+
+		if msg.RepoTotalSize > 0 {
+			b.WriteString("\nRepository:\n")
+			fmt.Fprintf(&b, "  Size: %s\n", format.Bytes(msg.RepoTotalSize))
+			if msg.SnapshotCount > 0 {
+				fmt.Fprintf(&b, "  Snapshots: %d\n", msg.SnapshotCount)
+			}
+		}
+	} else {
+		b.WriteString("\nError Details:\n")
+		fmt.Fprintf(&b, "  Failed step: %s\n", msg.FailedStep)
+		fmt.Fprintf(&b, "  Error: %s\n", msg.ErrorMessage)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,197 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/mail"
+	"net/smtp"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedHeaders matches the headers that differ on every message.
+var generatedHeaders = regexp.MustCompile(`Date: [^\r]+\r\nMessage-ID: [^\r]+\r\n`)
+
+type fakeSender struct {
+	addr   string
+	useTLS bool
+	auth   smtp.Auth
+	from   string
+	to     []string
+	msg    string
+	err    error
+}
+
+func (f *fakeSender) SendMail(_ context.Context, addr string, useTLS bool, auth smtp.Auth, from string, to []string, msg []byte) error {
+	f.addr = addr
+	f.useTLS = useTLS
+	f.auth = auth
+	f.from = from
+	f.to = to
+	f.msg = string(msg)
+	return f.err
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func testConfig() models.EmailConfig {
+	return models.EmailConfig{
+		SMTPHost: "smtp.example.com",
+		SMTPPort: 587,
+		Username: "backup@example.com",
+		Password: "secret",
+		From:     "backup@example.com",
+		To:       []string{"alice@example.com", "bob@example.com"},
+	}
+}
+
+func TestSend_Success(t *testing.T) {
+	sender := &fakeSender{}
+	svc := NewWithSender(testLogger(), sender)
+
+	msg := models.TelegramMessage{
+		Success:          true,
+		Host:             "server1",
+		Repository:       "/backup",
+		StartTime:        time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC),
+		Duration:         5 * time.Minute,
+		SnapshotID:       "abc123",
+		FilesNew:         10,
+		FilesChanged:     5,
+		FilesUnmodified:  100,
+		DataAdded:        1024 * 1024,
+		TotalFiles:       115,
+		TotalBytes:       1024 * 1024 * 1024,
+		SnapshotsKept:    7,
+		SnapshotsRemoved: 2,
+	}
+
+	result, err := svc.Send(context.Background(), testConfig(), msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Nil(t, result.Error)
+
+	assert.Equal(t, "smtp.example.com:587", sender.addr)
+	assert.False(t, sender.useTLS)
+	assert.NotNil(t, sender.auth)
+	assert.Equal(t, "backup@example.com", sender.from)
+	assert.Equal(t, []string{"alice@example.com", "bob@example.com"}, sender.to)
+
+	expected := "From: backup@example.com\r\n" +
+		"To: alice@example.com, bob@example.com\r\n" +
+		"Subject: Backup Successful on server1\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=utf-8\r\n" +
+		"\r\n" +
+		"Host: server1\r\n" +
+		"Repository: /backup\r\n" +
+		"Started: 2024-01-15 03:00:00\r\n" +
+		"Duration: 5m0s\r\n" +
+		"\r\n" +
+		"Backup Statistics:\r\n" +
+		"  Snapshot: abc123\r\n" +
+		"  Files new: 10\r\n" +
+		"  Files changed: 5\r\n" +
+		"  Files unmodified: 100\r\n" +
+		"  Data added: 1.0 MiB\r\n" +
+		"  Total files: 115\r\n" +
+		"  Total size: 1.0 GiB\r\n" +
+		"\r\n" +
+		"Retention:\r\n" +
+		"  Snapshots kept: 7\r\n" +
+		"  Snapshots removed: 2\r\n"
+	assert.Equal(t, expected, generatedHeaders.ReplaceAllString(sender.msg, ""))
+}
+
+func TestBuildMessage_DateAndMessageID(t *testing.T) {
+	cfg := testConfig()
+	cfg.From = "Backups <backup@example.com>"
+
+	raw := string(buildMessage(cfg, models.TelegramMessage{Success: true, Host: "server1"}))
+
+	header, _, found := strings.Cut(raw, "\r\n\r\n")
+	require.True(t, found)
+	msg, err := mail.ReadMessage(strings.NewReader(header + "\r\n\r\n"))
+	require.NoError(t, err)
+
+	date, err := msg.Header.Date()
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), date, time.Minute)
+	assert.Regexp(t, `^<[0-9a-f]{32}@example\.com>$`, msg.Header.Get("Message-ID"))
+	assert.NotEqual(t, msg.Header.Get("Message-ID"), messageID(cfg.From))
+}
+
+func TestMessageID_UnparsableFrom(t *testing.T) {
+	assert.Regexp(t, `^<[0-9a-f]{32}@localhost>$`, messageID("not an address"))
+}
+
+func TestFormatBody_RepositorySize(t *testing.T) {
+	body := formatBody(models.TelegramMessage{Success: true, RepoTotalSize: 5 << 30, SnapshotCount: 42})
+
+	assert.Contains(t, body, "\nRepository:\n  Size: 5.0 GiB\n  Snapshots: 42\n")
+}
+
+func TestSend_Failure(t *testing.T) {
+	sender := &fakeSender{}
+	svc := NewWithSender(testLogger(), sender)
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		DryRun:       true,
+		Host:         "server1",
+		Repository:   "/backup",
+		StartTime:    time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC),
+		Duration:     30 * time.Second,
+		FailedStep:   "backup",
+		ErrorMessage: "repository not found",
+	}
+
+	_, err := svc.Send(context.Background(), testConfig(), msg)
+	require.NoError(t, err)
+
+	assert.Contains(t, sender.msg, "Subject: Backup Failed on server1 (dry-run)\r\n")
+	assert.Contains(t, sender.msg, "\r\n\r\nError Details:\r\n"+
+		"  Failed step: backup\r\n"+
+		"  Error: repository not found\r\n")
+	assert.NotContains(t, sender.msg, "Backup Statistics")
+}
+
+func TestSend_NoAuthWithoutUsername(t *testing.T) {
+	sender := &fakeSender{}
+	svc := NewWithSender(testLogger(), sender)
+
+	cfg := testConfig()
+	cfg.Username = ""
+	cfg.SMTPPort = 465
+	cfg.UseTLS = true
+
+	_, err := svc.Send(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.Nil(t, sender.auth)
+	assert.True(t, sender.useTLS)
+	assert.Equal(t, "smtp.example.com:465", sender.addr)
+}
+
+func TestSend_SenderError(t *testing.T) {
+	sender := &fakeSender{err: errors.New("535 authentication failed")}
+	svc := NewWithSender(testLogger(), sender)
+
+	result, err := svc.Send(context.Background(), testConfig(), models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "535 authentication failed")
+}
@@ -0,0 +1,96 @@
+// Package healthcheck provides healthchecks.io dead-man-switch pings.
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// skippedBody is sent with the success ping of a skipped run.
+const skippedBody = "skipped: a snapshot younger than backup.min_interval exists"
+
+// Service defines the interface for healthcheck operations.
+type Service interface {
+	Ping(ctx context.Context, pingURL string, status models.HealthcheckStatus) (*models.HealthcheckResult, error)
+}
+
+// HTTPClient allows mocking HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Impl implements the healthcheck Service interface.
+type Impl struct {
+	httpClient HTTPClient
+	logger     zerolog.Logger
+}
+
+// New creates a new healthcheck service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// NewWithClient creates a new healthcheck service with a custom HTTP client (for testing).
+func NewWithClient(logger zerolog.Logger, httpClient HTTPClient) *Impl {
+	return &Impl{
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// Ping reports the given status to the ping URL.
+func (s *Impl) Ping(ctx context.Context, pingURL string, status models.HealthcheckStatus) (*models.HealthcheckResult, error) {
+	result := &models.HealthcheckResult{}
+
+	url := strings.TrimSuffix(pingURL, "/")
+	var body io.Reader = http.NoBody
+	switch status {
+	case models.HealthcheckStart:
+		url += "/start"
+	case models.HealthcheckFail:
+		url += "/fail"
+	case models.HealthcheckSuccess:
+	case models.HealthcheckSkipped:
+		// The body is shown with the ping, so the skip is visible in the event log
+		body = strings.NewReader(skippedBody)
+	default:
+		return nil, fmt.Errorf("unknown healthcheck status %q", status)
+	}
+
+	s.logger.Info().Str("status", string(status)).Msg("sending healthcheck ping")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create request: %w", err)
+		return result, nil
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to send request: %w", err)
+		return result, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Errorf("healthcheck ping returned status %d", resp.StatusCode)
+		return result, nil
+	}
+
+	result.Pinged = true
+	s.logger.Debug().Str("status", string(status)).Msg("healthcheck ping sent")
+
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHTTPClient struct {
+	doFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.doFunc != nil {
+		return m.doFunc(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("OK")),
+	}, nil
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func TestPing_URLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		pingURL  string
+		status   models.HealthcheckStatus
+		expected string
+	}{
+		{"start", "https://hc-ping.com/uuid", models.HealthcheckStart, "https://hc-ping.com/uuid/start"},
+		{"success", "https://hc-ping.com/uuid", models.HealthcheckSuccess, "https://hc-ping.com/uuid"},
+		{"fail", "https://hc-ping.com/uuid", models.HealthcheckFail, "https://hc-ping.com/uuid/fail"},
+		{"trailing slash", "https://hc-ping.com/uuid/", models.HealthcheckFail, "https://hc-ping.com/uuid/fail"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedRequest *http.Request
+			httpClient := &mockHTTPClient{
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					capturedRequest = req
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader("OK")),
+					}, nil
+				},
+			}
+
+			svc := NewWithClient(testLogger(), httpClient)
+			result, err := svc.Ping(context.Background(), tt.pingURL, tt.status)
+
+			require.NoError(t, err)
+			assert.True(t, result.Pinged)
+			assert.Nil(t, result.Error)
+			assert.Equal(t, http.MethodPost, capturedRequest.Method)
+			assert.Equal(t, tt.expected, capturedRequest.URL.String())
+		})
+	}
+}
+
+func TestPing_Skipped(t *testing.T) {
+	var url, body string
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			url = req.URL.String()
+			data, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			body = string(data)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("OK"))}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.Ping(context.Background(), "https://hc-ping.com/uuid", models.HealthcheckSkipped)
+
+	require.NoError(t, err)
+	assert.True(t, result.Pinged)
+	// A success ping ends the started run, the body marks it as skipped
+	assert.Equal(t, "https://hc-ping.com/uuid", url)
+	assert.Contains(t, body, "skipped")
+}
+
+func TestPing_UnknownStatus(t *testing.T) {
+	svc := NewWithClient(testLogger(), &mockHTTPClient{})
+
+	_, err := svc.Ping(context.Background(), "https://hc-ping.com/uuid", "bogus")
+
+	assert.Error(t, err)
+}
+
+func TestPing_HTTPError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.Ping(context.Background(), "https://hc-ping.com/uuid", models.HealthcheckStart)
+
+	require.NoError(t, err)
+	assert.False(t, result.Pinged)
+	assert.Contains(t, result.Error.Error(), "connection refused")
+}
+
+func TestPing_BadStatus(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("not found")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.Ping(context.Background(), "https://hc-ping.com/uuid", models.HealthcheckSuccess)
+
+	require.NoError(t, err)
+	assert.False(t, result.Pinged)
+	assert.Contains(t, result.Error.Error(), "404")
+}
@@ -0,0 +1,81 @@
+// Package hooks provides execution of user-defined shell commands around the backup.
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// Service defines the interface for hook operations.
+type Service interface {
+	Run(ctx context.Context, command string) (*models.HookResult, error)
+}
+
+// CommandExecutor allows mocking exec.Command in tests.
+type CommandExecutor interface {
+	Execute(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// DefaultExecutor is the default command executor using os/exec.
+type DefaultExecutor struct{}
+
+// Execute runs a command and returns its combined output.
+func (e *DefaultExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	return cmd.CombinedOutput()
+}
+
+// Impl implements the hooks Service interface.
+type Impl struct {
+	executor CommandExecutor
+	logger   zerolog.Logger
+}
+
+// New creates a new hooks service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		executor: &DefaultExecutor{},
+		logger:   logger,
+	}
+}
+
+// NewWithExecutor creates a new hooks service with a custom executor (for testing).
+func NewWithExecutor(logger zerolog.Logger, executor CommandExecutor) *Impl {
+	return &Impl{
+		executor: executor,
+		logger:   logger,
+	}
+}
+
+// Run executes a hook command through sh -c.
+func (s *Impl) Run(ctx context.Context, command string) (*models.HookResult, error) {
+	s.logger.Info().Str("command", command).Msg("running hook")
+
+	start := time.Now()
+	output, err := s.executor.Execute(ctx, "sh", "-c", command)
+
+	result := &models.HookResult{
+		Command:  command,
+		Output:   strings.TrimSpace(string(output)),
+		Duration: time.Since(start),
+	}
+
+	if err != nil {
+		result.Error = fmt.Errorf("hook %q failed: %w, output: %s", command, err, result.Output)
+		return result, nil
+	}
+
+	s.logger.Debug().
+		Str("command", command).
+		Str("output", result.Output).
+		Str("duration", result.Duration.Round(time.Millisecond).String()).
+		Msg("hook completed")
+
+	return result, nil
+}
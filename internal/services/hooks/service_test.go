@@ -0,0 +1,77 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecutor struct {
+	executeFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+func (m *mockExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, name, args...)
+	}
+	return nil, nil
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func TestRun_Success(t *testing.T) {
+	var capturedName string
+	var capturedArgs []string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			capturedName = name
+			capturedArgs = args
+			return []byte("container stopped\n"), nil
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Run(context.Background(), "docker stop app")
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, "sh", capturedName)
+	assert.Equal(t, []string{"-c", "docker stop app"}, capturedArgs)
+	assert.Equal(t, "container stopped", result.Output)
+	assert.Equal(t, "docker stop app", result.Command)
+}
+
+func TestRun_Failure(t *testing.T) {
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return []byte("no such container"), errors.New("exit status 1")
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Run(context.Background(), "docker stop app")
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.NotNil(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "docker stop app")
+	assert.Contains(t, result.Error.Error(), "no such container")
+}
+
+func TestDefaultExecutor_RunsShell(t *testing.T) {
+	executor := &DefaultExecutor{}
+
+	output, err := executor.Execute(context.Background(), "sh", "-c", "echo hello")
+
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(output))
+}
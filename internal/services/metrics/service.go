@@ -0,0 +1,99 @@
+// Package metrics provides Prometheus textfile output for node_exporter.
+package metrics
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// Service defines the interface for metrics operations.
+type Service interface {
+	Write(path string, msg models.TelegramMessage) error
+}
+
+// Impl implements the metrics Service interface.
+type Impl struct {
+	logger zerolog.Logger
+}
+
+// New creates a new metrics service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{logger: logger}
+}
+
+// Write renders the run result as Prometheus text format and atomically
+// replaces the file at path.
+func (s *Impl) Write(path string, msg models.TelegramMessage) error {
+	s.logger.Info().Str("path", path).Msg("writing metrics file")
+
+	// The temp file must not end in .prom, or node_exporter may read it half-written.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp metrics file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
+
+	if _, err := tmp.WriteString(render(msg)); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing metrics: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing metrics file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0o644); err != nil { //nolint:gosec // node_exporter must be able to read it
+		return fmt.Errorf("setting metrics file permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming metrics file: %w", err)
+	}
+
+	return nil
+}
+
+// render formats the run result in the Prometheus text exposition format.
+func render(msg models.TelegramMessage) string {
+	labels := fmt.Sprintf(`{host="%s"}`, escapeLabel(msg.Host))
+
+	var sb strings.Builder
+	gauge := func(name, help string, value any) {
+		fmt.Fprintf(&sb, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(&sb, "# TYPE %s gauge\n", name)
+		fmt.Fprintf(&sb, "%s%s %v\n", name, labels, value)
+	}
+
+	success := 0
+	if msg.Success {
+		success = 1
+	}
+
+	skipped := 0
+	if msg.Skipped {
+		skipped = 1
+	}
+
+	gauge("gorestic_backup_success", "Whether the last backup run succeeded (1) or failed (0).", success)
+	gauge("gorestic_backup_skipped", "Whether the last run was skipped (1) because a recent snapshot exists.", skipped)
+	gauge("gorestic_backup_duration_seconds", "Duration of the last backup run in seconds.", msg.Duration.Seconds())
+	gauge("gorestic_files_new", "Number of new files in the last snapshot.", msg.FilesNew)
+	gauge("gorestic_files_changed", "Number of changed files in the last snapshot.", msg.FilesChanged)
+	gauge("gorestic_data_added_bytes", "Bytes added to the repository by the last backup.", msg.DataAdded)
+	gauge("gorestic_snapshots_kept", "Number of snapshots kept by the retention policy.", msg.SnapshotsKept)
+	gauge("gorestic_last_run_timestamp", "Unix timestamp of the end of the last backup run.", msg.StartTime.Add(msg.Duration).Unix())
+	if msg.RepoTotalSize > 0 {
+		gauge("gorestic_repository_size_bytes", "Size of the repository after the last run in bytes.", msg.RepoTotalSize)
+		gauge("gorestic_repository_snapshots", "Number of snapshots in the repository after the last run.", msg.SnapshotCount)
+	}
+
+	return sb.String()
+}
+
+// escapeLabel escapes a Prometheus label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func testMessage() models.TelegramMessage {
+	return models.TelegramMessage{
+		Success:       true,
+		Host:          "homelab",
+		Repository:    "/backup",
+		StartTime:     time.Unix(1700000000, 0),
+		Duration:      90500 * time.Millisecond,
+		FilesNew:      10,
+		FilesChanged:  5,
+		DataAdded:     1048576,
+		SnapshotsKept: 7,
+	}
+}
+
+func TestRender(t *testing.T) {
+	expected := `# HELP gorestic_backup_success Whether the last backup run succeeded (1) or failed (0).
+# TYPE gorestic_backup_success gauge
+gorestic_backup_success{host="homelab"} 1
+# HELP gorestic_backup_skipped Whether the last run was skipped (1) because a recent snapshot exists.
+# TYPE gorestic_backup_skipped gauge
+gorestic_backup_skipped{host="homelab"} 0
+# HELP gorestic_backup_duration_seconds Duration of the last backup run in seconds.
+# TYPE gorestic_backup_duration_seconds gauge
+gorestic_backup_duration_seconds{host="homelab"} 90.5
+# HELP gorestic_files_new Number of new files in the last snapshot.
+# TYPE gorestic_files_new gauge
+gorestic_files_new{host="homelab"} 10
+# HELP gorestic_files_changed Number of changed files in the last snapshot.
+# TYPE gorestic_files_changed gauge
+gorestic_files_changed{host="homelab"} 5
+# HELP gorestic_data_added_bytes Bytes added to the repository by the last backup.
+# TYPE gorestic_data_added_bytes gauge
+gorestic_data_added_bytes{host="homelab"} 1048576
+# HELP gorestic_snapshots_kept Number of snapshots kept by the retention policy.
+# TYPE gorestic_snapshots_kept gauge
+gorestic_snapshots_kept{host="homelab"} 7
+# HELP gorestic_last_run_timestamp Unix timestamp of the end of the last backup run.
+# TYPE gorestic_last_run_timestamp gauge
+gorestic_last_run_timestamp{host="homelab"} 1700000090
+`
+
+	assert.Equal(t, expected, render(testMessage()))
+}
+
+func TestRender_Failure(t *testing.T) {
+	msg := testMessage()
+	msg.Success = false
+
+	assert.Contains(t, render(msg), `gorestic_backup_success{host="homelab"} 0`)
+}
+
+func TestRender_Skipped(t *testing.T) {
+	msg := models.TelegramMessage{Success: true, Skipped: true, Host: "homelab", StartTime: time.Unix(1700000000, 0)}
+
+	out := render(msg)
+
+	assert.Contains(t, out, `gorestic_backup_skipped{host="homelab"} 1`)
+	assert.Contains(t, out, `gorestic_files_new{host="homelab"} 0`)
+	assert.NotContains(t, out, "gorestic_repository_size_bytes", "repository stats were not collected")
+}
+
+func TestRender_RepositorySize(t *testing.T) {
+	msg := testMessage()
+	msg.RepoTotalSize = 5 << 30
+	msg.SnapshotCount = 42
+
+	out := render(msg)
+
+	assert.Contains(t, out, `gorestic_repository_size_bytes{host="homelab"} 5368709120`)
+	assert.Contains(t, out, `gorestic_repository_snapshots{host="homelab"} 42`)
+}
+
+func TestRender_EscapesLabels(t *testing.T) {
+	msg := testMessage()
+	msg.Host = `my"host\`
+
+	assert.Contains(t, render(msg), `gorestic_files_new{host="my\"host\\"} 10`)
+}
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "gorestic.prom")
+
+	svc := New(testLogger())
+	require.NoError(t, svc.Write(path, testMessage()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, render(testMessage()), string(content))
+
+	// No temp files should be left behind
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestWrite_ReplacesExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gorestic.prom")
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0o600))
+
+	svc := New(testLogger())
+	require.NoError(t, svc.Write(path, testMessage()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "stale")
+}
+
+func TestWrite_MissingDirectory(t *testing.T) {
+	svc := New(testLogger())
+
+	err := svc.Write(filepath.Join(t.TempDir(), "missing", "gorestic.prom"), testMessage())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,147 @@
+// Package mysql provides MySQL/MariaDB dump operations.
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// Service defines the interface for MySQL dump operations.
+type Service interface {
+	Dump(ctx context.Context, cfg models.MySQLConfig, outputPath string) (*models.MySQLDumpResult, error)
+}
+
+// CommandExecutor allows mocking exec.Command in tests.
+type CommandExecutor interface {
+	ExecuteWithEnv(ctx context.Context, env []string, outputPath string, name string, args ...string) error
+}
+
+// DefaultExecutor is the default command executor using os/exec.
+type DefaultExecutor struct{}
+
+// ExecuteWithEnv runs the named binary and writes its stdout to outputPath.
+func (e *DefaultExecutor) ExecuteWithEnv(ctx context.Context, env []string, outputPath string, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	output, err := os.Create(outputPath) //nolint:gosec // outputPath is controlled by caller
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer func() { _ = output.Close() }()
+
+	var stderr bytes.Buffer
+	cmd.Stdout = output
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg != "" {
+			return fmt.Errorf("%s failed: %w: %s", name, err, errMsg)
+		}
+		return fmt.Errorf("%s failed: %w", name, err)
+	}
+
+	return nil
+}
+
+// Impl implements the MySQL Service interface.
+type Impl struct {
+	executor CommandExecutor
+	logger   zerolog.Logger
+}
+
+// New creates a new MySQL service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		executor: &DefaultExecutor{},
+		logger:   logger,
+	}
+}
+
+// NewWithExecutor creates a new MySQL service with a custom executor (for testing).
+func NewWithExecutor(logger zerolog.Logger, executor CommandExecutor) *Impl {
+	return &Impl{
+		executor: executor,
+		logger:   logger,
+	}
+}
+
+// Dump performs a mysqldump operation.
+func (s *Impl) Dump(ctx context.Context, cfg models.MySQLConfig, outputPath string) (*models.MySQLDumpResult, error) {
+	s.logger.Info().
+		Str("host", cfg.Host).
+		Int("port", cfg.Port).
+		Str("database", cfg.Database).
+		Str("output", outputPath).
+		Msg("starting MySQL dump")
+
+	start := time.Now()
+	result := &models.MySQLDumpResult{
+		OutputPath: outputPath,
+	}
+
+	// Ensure output directory exists
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		result.Error = fmt.Errorf("failed to create output directory: %w", err)
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	// Build mysqldump arguments. --single-transaction gives a consistent
+	// InnoDB snapshot without locking tables.
+	args := []string{
+		"-h", cfg.Host,
+		"-P", fmt.Sprintf("%d", cfg.Port),
+		"-u", cfg.Username,
+		"--single-transaction",
+		"--routines",
+		"--triggers",
+		cfg.Database,
+	}
+
+	// Pass the password via environment so it does not show up in the process list
+	env := []string{}
+	if cfg.Password != "" {
+		env = append(env, fmt.Sprintf("MYSQL_PWD=%s", cfg.Password))
+	}
+
+	if execErr := s.executor.ExecuteWithEnv(ctx, env, outputPath, "mysqldump", args...); execErr != nil {
+		// Clean up partial file
+		_ = os.Remove(outputPath)
+		result.Error = execErr
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	// Get file size
+	if info, err := os.Stat(outputPath); err == nil {
+		result.SizeBytes = info.Size()
+	}
+
+	result.Duration = time.Since(start)
+
+	s.logger.Info().
+		Str("output", outputPath).
+		Int64("size_bytes", result.SizeBytes).
+		Str("duration", result.Duration.Round(time.Millisecond).String()).
+		Msg("MySQL dump completed")
+
+	return result, nil
+}
+
+// GetOutputFilename returns a suggested output filename based on config.
+func GetOutputFilename(cfg models.MySQLConfig) string {
+	timestamp := time.Now().Format("20060102-150405")
+	return fmt.Sprintf("mysql-%s-%s.sql", cfg.Database, timestamp)
+}
@@ -0,0 +1,157 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecutor struct {
+	executeFunc func(ctx context.Context, env []string, outputPath string, name string, args ...string) error
+}
+
+func (m *mockExecutor) ExecuteWithEnv(ctx context.Context, env []string, outputPath string, name string, args ...string) error {
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, env, outputPath, name, args...)
+	}
+	return os.WriteFile(outputPath, []byte(""), 0o600)
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func testConfig() models.MySQLConfig {
+	return models.MySQLConfig{
+		Host:     "localhost",
+		Port:     3306,
+		Database: "appdb",
+		Username: "root",
+		Password: "secret",
+	}
+}
+
+func TestDump_Success(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "appdb.sql")
+
+	var capturedName string
+	var capturedArgs []string
+	var capturedEnv []string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedName = name
+			capturedArgs = args
+			capturedEnv = env
+			return os.WriteFile(op, []byte("-- MySQL dump"), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Dump(context.Background(), testConfig(), outputPath)
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, outputPath, result.OutputPath)
+	assert.Equal(t, int64(13), result.SizeBytes)
+
+	assert.Equal(t, "mysqldump", capturedName)
+	assert.Equal(t, []string{
+		"-h", "localhost",
+		"-P", "3306",
+		"-u", "root",
+		"--single-transaction",
+		"--routines",
+		"--triggers",
+		"appdb",
+	}, capturedArgs)
+
+	// Password must only be passed via environment
+	assert.Contains(t, capturedEnv, "MYSQL_PWD=secret")
+	for _, arg := range capturedArgs {
+		assert.NotContains(t, arg, "secret")
+	}
+}
+
+func TestDump_NoPassword(t *testing.T) {
+	var capturedEnv []string
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedEnv = env
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	cfg.Password = ""
+
+	result, err := svc.Dump(context.Background(), cfg, filepath.Join(t.TempDir(), "appdb.sql"))
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	for _, e := range capturedEnv {
+		assert.NotContains(t, e, "MYSQL_PWD")
+	}
+}
+
+func TestDump_ExecutorError(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "appdb.sql")
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			_ = os.WriteFile(op, []byte("partial"), 0o600)
+			return errors.New("mysqldump failed: access denied")
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Dump(context.Background(), testConfig(), outputPath)
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "access denied")
+
+	// Partial output is removed
+	_, statErr := os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestDump_CreatesDirectory(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "subdir", "nested", "appdb.sql")
+
+	svc := NewWithExecutor(testLogger(), &mockExecutor{})
+	result, err := svc.Dump(context.Background(), testConfig(), outputPath)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.FileExists(t, outputPath)
+}
+
+func TestGetOutputFilename(t *testing.T) {
+	filename := GetOutputFilename(testConfig())
+
+	assert.True(t, strings.HasPrefix(filename, "mysql-appdb-"))
+	assert.True(t, strings.HasSuffix(filename, ".sql"))
+}
+
+func TestDefaultExecutor_CapturesStderr(t *testing.T) {
+	executor := &DefaultExecutor{}
+	outputPath := filepath.Join(t.TempDir(), "out.sql")
+
+	err := executor.ExecuteWithEnv(context.Background(), nil, outputPath, "sh", "-c", "echo 'access denied' >&2; exit 2")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sh failed")
+	assert.Contains(t, err.Error(), "access denied")
+}
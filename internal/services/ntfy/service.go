@@ -0,0 +1,133 @@
+// Package ntfy provides ntfy push notification services.
+package ntfy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// Service defines the interface for ntfy notification operations.
+type Service interface {
+	Notify(ctx context.Context, cfg models.NtfyConfig, msg models.TelegramMessage) (*models.NtfyResult, error)
+}
+
+// HTTPClient allows mocking HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Impl implements the ntfy Service interface.
+type Impl struct {
+	httpClient HTTPClient
+	logger     zerolog.Logger
+}
+
+// New creates a new ntfy service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// NewWithClient creates a new ntfy service with a custom HTTP client (for testing).
+func NewWithClient(logger zerolog.Logger, httpClient HTTPClient) *Impl {
+	return &Impl{
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// Notify publishes a backup notification to the configured ntfy topic.
+func (s *Impl) Notify(ctx context.Context, cfg models.NtfyConfig, msg models.TelegramMessage) (*models.NtfyResult, error) {
+	result := &models.NtfyResult{}
+
+	s.logger.Info().
+		Bool("success", msg.Success).
+		Str("topic", cfg.Topic).
+		Msg("sending ntfy notification")
+
+	topicURL := strings.TrimRight(cfg.Server, "/") + "/" + cfg.Topic
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, topicURL, bytes.NewBufferString(formatBody(msg)))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create request: %w", err)
+		return result, nil
+	}
+
+	title, priority, tags := formatHeaders(msg)
+	req.Header.Set("Title", title)
+	req.Header.Set("Priority", priority)
+	req.Header.Set("Tags", tags)
+	if cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to send request: %w", err)
+		return result, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Errorf("ntfy server returned status %d", resp.StatusCode)
+		return result, nil
+	}
+
+	result.MessageSent = true
+	s.logger.Info().Msg("ntfy notification sent successfully")
+
+	return result, nil
+}
+
+// formatHeaders returns the Title, Priority and Tags headers. Failures are sent
+// with high priority so they break through the default notification settings.
+func formatHeaders(msg models.TelegramMessage) (title, priority, tags string) {
+	title = "Backup Successful on " + msg.Host
+	priority = "default"
+	tags = "white_check_mark"
+	if !msg.Success {
+		title = "Backup Failed on " + msg.Host
+		priority = "high"
+		tags = "rotating_light"
+	}
+	if msg.DryRun {
+		title += " (dry-run)"
+		tags += ",test_tube"
+	}
+	return title, priority, tags
+}
+
+func formatBody(msg models.TelegramMessage) string {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "Duration: %s\n", msg.Duration.Round(time.Second))
+	if msg.Success {
+		fmt.Fprintf(&b, "Snapshot: %s\n", msg.SnapshotID)
+		fmt.Fprintf(&b, "Data added: %s\n", format.Bytes(msg.DataAdded))
+		fmt.Fprintf(&b, "Files: %d new, %d changed, %d unmodified\n", msg.FilesNew, msg.FilesChanged, msg.FilesUnmodified)
+		fmt.Fprintf(&b, "Retention: %d kept, %d removed", msg.SnapshotsKept, msg.SnapshotsRemoved)
+		if msg.RepoTotalSize > 0 {
+			fmt.Fprintf(&b, "\nRepository size: %s", format.Bytes(msg.RepoTotalSize))
+		}
+		if msg.SnapshotCount > 0 {
+			fmt.Fprintf(&b, "\nSnapshots in repo: %d", msg.SnapshotCount)
+		}
+	} else {
+		fmt.Fprintf(&b, "Failed step: %s\n", msg.FailedStep)
+		fmt.Fprintf(&b, "Error: %s", msg.ErrorMessage)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package ntfy
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHTTPClient struct {
+	doFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.doFunc != nil {
+		return m.doFunc(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+	}, nil
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func testConfig() models.NtfyConfig {
+	return models.NtfyConfig{
+		Server: "https://ntfy.sh",
+		Topic:  "homelab-backups",
+	}
+}
+
+func captureClient(captured **http.Request, rawBody *string) *mockHTTPClient {
+	return &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			*captured = req
+			*rawBody = string(body)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}, nil
+		},
+	}
+}
+
+func TestNotify_SuccessHeaders(t *testing.T) {
+	var req *http.Request
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&req, &rawBody))
+
+	msg := models.TelegramMessage{
+		Success:          true,
+		Host:             "server1",
+		Duration:         5 * time.Minute,
+		SnapshotID:       "abc123",
+		FilesNew:         10,
+		FilesChanged:     5,
+		FilesUnmodified:  100,
+		DataAdded:        1024 * 1024,
+		SnapshotsKept:    7,
+		SnapshotsRemoved: 2,
+	}
+
+	result, err := svc.Notify(context.Background(), testConfig(), msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Nil(t, result.Error)
+
+	require.NotNil(t, req)
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "https://ntfy.sh/homelab-backups", req.URL.String())
+	assert.Equal(t, "Backup Successful on server1", req.Header.Get("Title"))
+	assert.Equal(t, "default", req.Header.Get("Priority"))
+	assert.Equal(t, "white_check_mark", req.Header.Get("Tags"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+
+	assert.Equal(t, "Duration: 5m0s\n"+
+		"Snapshot: abc123\n"+
+		"Data added: 1.0 MiB\n"+
+		"Files: 10 new, 5 changed, 100 unmodified\n"+
+		"Retention: 7 kept, 2 removed", rawBody)
+}
+
+func TestFormatBody_RepositorySize(t *testing.T) {
+	body := formatBody(models.TelegramMessage{Success: true, RepoTotalSize: 5 << 30, SnapshotCount: 42})
+
+	assert.True(t, strings.HasSuffix(body, "Retention: 0 kept, 0 removed\nRepository size: 5.0 GiB\nSnapshots in repo: 42"), body)
+}
+
+func TestNotify_FailureHeaders(t *testing.T) {
+	var req *http.Request
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&req, &rawBody))
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		Duration:     30 * time.Second,
+		FailedStep:   "backup",
+		ErrorMessage: "repository not found",
+	}
+
+	result, err := svc.Notify(context.Background(), testConfig(), msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+
+	require.NotNil(t, req)
+	assert.Equal(t, "Backup Failed on server1", req.Header.Get("Title"))
+	assert.Equal(t, "high", req.Header.Get("Priority"))
+	assert.Equal(t, "rotating_light", req.Header.Get("Tags"))
+	assert.Equal(t, "Duration: 30s\nFailed step: backup\nError: repository not found", rawBody)
+}
+
+func TestNotify_DryRunTag(t *testing.T) {
+	var req *http.Request
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&req, &rawBody))
+
+	_, err := svc.Notify(context.Background(), testConfig(), models.TelegramMessage{Success: true, Host: "server1", DryRun: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Backup Successful on server1 (dry-run)", req.Header.Get("Title"))
+	assert.Equal(t, "white_check_mark,test_tube", req.Header.Get("Tags"))
+}
+
+func TestNotify_TokenAndTrailingSlash(t *testing.T) {
+	var req *http.Request
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&req, &rawBody))
+
+	cfg := models.NtfyConfig{Server: "https://ntfy.example.com/", Topic: "backups", Token: "tk_secret"}
+	_, err := svc.Notify(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://ntfy.example.com/backups", req.URL.String())
+	assert.Equal(t, "Bearer tk_secret", req.Header.Get("Authorization"))
+}
+
+func TestNotify_HTTPError(t *testing.T) {
+	client := &mockHTTPClient{
+		doFunc: func(_ *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+	svc := NewWithClient(testLogger(), client)
+
+	result, err := svc.Notify(context.Background(), testConfig(), models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "connection refused")
+}
+
+func TestNotify_Non200Status(t *testing.T) {
+	client := &mockHTTPClient{
+		doFunc: func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       io.NopCloser(strings.NewReader(`{"error":"forbidden"}`)),
+			}, nil
+		},
+	}
+	svc := NewWithClient(testLogger(), client)
+
+	result, err := svc.Notify(context.Background(), testConfig(), models.TelegramMessage{Success: false})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "status 403")
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -120,3 +121,176 @@ func (_c *MockCommandExecutor_ExecuteWithEnv_Call) RunAndReturn(run func(ctx con
 	_c.Call.Return(run)
 	return _c
 }
+
+// Output provides a mock function for the type MockCommandExecutor
+func (_mock *MockCommandExecutor) Output(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, env, name, args)
+	} else {
+		tmpRet = _mock.Called(ctx, env, name)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Output")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, string, ...string) ([]byte, error)); ok {
+		return returnFunc(ctx, env, name, args...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, string, ...string) []byte); ok {
+		r0 = returnFunc(ctx, env, name, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, string, ...string) error); ok {
+		r1 = returnFunc(ctx, env, name, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommandExecutor_Output_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Output'
+type MockCommandExecutor_Output_Call struct {
+	*mock.Call
+}
+
+// Output is a helper method to define mock.On call
+//   - ctx context.Context
+//   - env []string
+//   - name string
+//   - args ...string
+func (_e *MockCommandExecutor_Expecter) Output(ctx interface{}, env interface{}, name interface{}, args ...interface{}) *MockCommandExecutor_Output_Call {
+	return &MockCommandExecutor_Output_Call{Call: _e.mock.On("Output",
+		append([]interface{}{ctx, env, name}, args...)...)}
+}
+
+func (_c *MockCommandExecutor_Output_Call) Run(run func(ctx context.Context, env []string, name string, args ...string)) *MockCommandExecutor_Output_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 []string
+		var variadicArgs []string
+		if len(args) > 3 {
+			variadicArgs = args[3].([]string)
+		}
+		arg3 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommandExecutor_Output_Call) Return(bytes []byte, err error) *MockCommandExecutor_Output_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *MockCommandExecutor_Output_Call) RunAndReturn(run func(ctx context.Context, env []string, name string, args ...string) ([]byte, error)) *MockCommandExecutor_Output_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stream provides a mock function for the type MockCommandExecutor
+func (_mock *MockCommandExecutor) Stream(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, env, stdout, name, args)
+	} else {
+		tmpRet = _mock.Called(ctx, env, stdout, name)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stream")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, io.Writer, string, ...string) error); ok {
+		r0 = returnFunc(ctx, env, stdout, name, args...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCommandExecutor_Stream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stream'
+type MockCommandExecutor_Stream_Call struct {
+	*mock.Call
+}
+
+// Stream is a helper method to define mock.On call
+//   - ctx context.Context
+//   - env []string
+//   - stdout io.Writer
+//   - name string
+//   - args ...string
+func (_e *MockCommandExecutor_Expecter) Stream(ctx interface{}, env interface{}, stdout interface{}, name interface{}, args ...interface{}) *MockCommandExecutor_Stream_Call {
+	return &MockCommandExecutor_Stream_Call{Call: _e.mock.On("Stream",
+		append([]interface{}{ctx, env, stdout, name}, args...)...)}
+}
+
+func (_c *MockCommandExecutor_Stream_Call) Run(run func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string)) *MockCommandExecutor_Stream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 io.Writer
+		if args[2] != nil {
+			arg2 = args[2].(io.Writer)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 []string
+		var variadicArgs []string
+		if len(args) > 4 {
+			variadicArgs = args[4].([]string)
+		}
+		arg4 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommandExecutor_Stream_Call) Return(err error) *MockCommandExecutor_Stream_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCommandExecutor_Stream_Call) RunAndReturn(run func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error) *MockCommandExecutor_Stream_Call {
+	_c.Call.Return(run)
+	return _c
+}
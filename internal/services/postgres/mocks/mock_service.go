@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,66 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 	return &MockService_Expecter{mock: &_m.Mock}
 }
 
+// CheckVersion provides a mock function for the type MockService
+func (_mock *MockService) CheckVersion(ctx context.Context) (int, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckVersion")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_CheckVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckVersion'
+type MockService_CheckVersion_Call struct {
+	*mock.Call
+}
+
+// CheckVersion is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) CheckVersion(ctx interface{}) *MockService_CheckVersion_Call {
+	return &MockService_CheckVersion_Call{Call: _e.mock.On("CheckVersion", ctx)}
+}
+
+func (_c *MockService_CheckVersion_Call) Run(run func(ctx context.Context)) *MockService_CheckVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CheckVersion_Call) Return(n int, err error) *MockService_CheckVersion_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockService_CheckVersion_Call) RunAndReturn(run func(ctx context.Context) (int, error)) *MockService_CheckVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Dump provides a mock function for the type MockService
 func (_mock *MockService) Dump(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
 	ret := _mock.Called(ctx, cfg, outputPath)
@@ -111,3 +172,217 @@ func (_c *MockService_Dump_Call) RunAndReturn(run func(ctx context.Context, cfg
 	_c.Call.Return(run)
 	return _c
 }
+
+// DumpGlobals provides a mock function for the type MockService
+func (_mock *MockService) DumpGlobals(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+	ret := _mock.Called(ctx, cfg, outputPath)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DumpGlobals")
+	}
+
+	var r0 *models.PostgresDumpResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostgresConfig, string) (*models.PostgresDumpResult, error)); ok {
+		return returnFunc(ctx, cfg, outputPath)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostgresConfig, string) *models.PostgresDumpResult); ok {
+		r0 = returnFunc(ctx, cfg, outputPath)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PostgresDumpResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PostgresConfig, string) error); ok {
+		r1 = returnFunc(ctx, cfg, outputPath)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_DumpGlobals_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DumpGlobals'
+type MockService_DumpGlobals_Call struct {
+	*mock.Call
+}
+
+// DumpGlobals is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.PostgresConfig
+//   - outputPath string
+func (_e *MockService_Expecter) DumpGlobals(ctx interface{}, cfg interface{}, outputPath interface{}) *MockService_DumpGlobals_Call {
+	return &MockService_DumpGlobals_Call{Call: _e.mock.On("DumpGlobals", ctx, cfg, outputPath)}
+}
+
+func (_c *MockService_DumpGlobals_Call) Run(run func(ctx context.Context, cfg models.PostgresConfig, outputPath string)) *MockService_DumpGlobals_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.PostgresConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.PostgresConfig)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DumpGlobals_Call) Return(postgresDumpResult *models.PostgresDumpResult, err error) *MockService_DumpGlobals_Call {
+	_c.Call.Return(postgresDumpResult, err)
+	return _c
+}
+
+func (_c *MockService_DumpGlobals_Call) RunAndReturn(run func(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error)) *MockService_DumpGlobals_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DumpTo provides a mock function for the type MockService
+func (_mock *MockService) DumpTo(ctx context.Context, cfg models.PostgresConfig, w io.Writer) (*models.PostgresDumpResult, error) {
+	ret := _mock.Called(ctx, cfg, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DumpTo")
+	}
+
+	var r0 *models.PostgresDumpResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostgresConfig, io.Writer) (*models.PostgresDumpResult, error)); ok {
+		return returnFunc(ctx, cfg, w)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostgresConfig, io.Writer) *models.PostgresDumpResult); ok {
+		r0 = returnFunc(ctx, cfg, w)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PostgresDumpResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PostgresConfig, io.Writer) error); ok {
+		r1 = returnFunc(ctx, cfg, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_DumpTo_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DumpTo'
+type MockService_DumpTo_Call struct {
+	*mock.Call
+}
+
+// DumpTo is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.PostgresConfig
+//   - w io.Writer
+func (_e *MockService_Expecter) DumpTo(ctx interface{}, cfg interface{}, w interface{}) *MockService_DumpTo_Call {
+	return &MockService_DumpTo_Call{Call: _e.mock.On("DumpTo", ctx, cfg, w)}
+}
+
+func (_c *MockService_DumpTo_Call) Run(run func(ctx context.Context, cfg models.PostgresConfig, w io.Writer)) *MockService_DumpTo_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.PostgresConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.PostgresConfig)
+		}
+		var arg2 io.Writer
+		if args[2] != nil {
+			arg2 = args[2].(io.Writer)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_DumpTo_Call) Return(postgresDumpResult *models.PostgresDumpResult, err error) *MockService_DumpTo_Call {
+	_c.Call.Return(postgresDumpResult, err)
+	return _c
+}
+
+func (_c *MockService_DumpTo_Call) RunAndReturn(run func(ctx context.Context, cfg models.PostgresConfig, w io.Writer) (*models.PostgresDumpResult, error)) *MockService_DumpTo_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ServerVersion provides a mock function for the type MockService
+func (_mock *MockService) ServerVersion(ctx context.Context, cfg models.PostgresConfig) (int, error) {
+	ret := _mock.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServerVersion")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostgresConfig) (int, error)); ok {
+		return returnFunc(ctx, cfg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.PostgresConfig) int); ok {
+		r0 = returnFunc(ctx, cfg)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.PostgresConfig) error); ok {
+		r1 = returnFunc(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_ServerVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServerVersion'
+type MockService_ServerVersion_Call struct {
+	*mock.Call
+}
+
+// ServerVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.PostgresConfig
+func (_e *MockService_Expecter) ServerVersion(ctx interface{}, cfg interface{}) *MockService_ServerVersion_Call {
+	return &MockService_ServerVersion_Call{Call: _e.mock.On("ServerVersion", ctx, cfg)}
+}
+
+func (_c *MockService_ServerVersion_Call) Run(run func(ctx context.Context, cfg models.PostgresConfig)) *MockService_ServerVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.PostgresConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.PostgresConfig)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_ServerVersion_Call) Return(n int, err error) *MockService_ServerVersion_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockService_ServerVersion_Call) RunAndReturn(run func(ctx context.Context, cfg models.PostgresConfig) (int, error)) *MockService_ServerVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// MoveDump moves a dump file or directory into dir and returns its new path.
+// Dumps on a different filesystem are copied and the original is removed.
+func MoveDump(src, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("creating %s: %w", dir, err)
+	}
+
+	dst := filepath.Join(dir, filepath.Base(src))
+	err := os.Rename(src, dst)
+	if err == nil {
+		return dst, nil
+	}
+	if !errors.Is(err, syscall.EXDEV) {
+		return "", fmt.Errorf("moving %s to %s: %w", src, dir, err)
+	}
+
+	if err := copyPath(src, dst); err != nil {
+		_ = os.RemoveAll(dst)
+		return "", fmt.Errorf("copying %s to %s: %w", src, dir, err)
+	}
+	if err := os.RemoveAll(src); err != nil {
+		return "", fmt.Errorf("removing %s: %w", src, err)
+	}
+	return dst, nil
+}
+
+// RotateDumps removes all but the newest keep dumps of database in dir.
+// Dumps are ordered by the timestamp in their file name, files that do not
+// match the GetOutputFilename pattern are left alone. It returns the removed paths.
+func RotateDumps(dir, database string, keep int) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	type dump struct {
+		path      string
+		timestamp time.Time
+	}
+	var dumps []dump
+	for _, entry := range entries {
+		if ts, ok := dumpTimestamp(entry.Name(), database); ok {
+			dumps = append(dumps, dump{path: filepath.Join(dir, entry.Name()), timestamp: ts})
+		}
+	}
+	if len(dumps) <= keep {
+		return nil, nil
+	}
+
+	// Newest first
+	sort.Slice(dumps, func(i, j int) bool {
+		return dumps[i].timestamp.After(dumps[j].timestamp)
+	})
+
+	var removed []string
+	for _, d := range dumps[keep:] {
+		if err := os.RemoveAll(d.path); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", d.path, err)
+		}
+		removed = append(removed, d.path)
+	}
+	return removed, nil
+}
+
+// dumpTimestamp parses the timestamp from a dump file name created by
+// GetOutputFilename, e.g. myapp-20240115-030000.dump.
+func dumpTimestamp(name, database string) (time.Time, bool) {
+	rest, ok := strings.CutPrefix(name, database+"-")
+	if !ok || len(rest) < len(timestampLayout)+1 || rest[len(timestampLayout)] != '.' {
+		return time.Time{}, false
+	}
+
+	ts, err := time.ParseInLocation(timestampLayout, rest[:len(timestampLayout)], time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return ts, true
+}
+
+// copyPath copies a file, or a directory with its files, to dst.
+func copyPath(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o700)
+		}
+		return copyFile(path, target)
+	})
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
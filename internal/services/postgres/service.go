@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,49 +20,93 @@ import (
 
 // PostgreSQL dump format constants.
 const (
-	FormatPlain = "plain"
-	FormatTar   = "tar"
+	FormatPlain     = "plain"
+	FormatTar       = "tar"
+	FormatDirectory = "directory"
 )
 
+// timestampLayout is the timestamp format used in dump file names.
+const timestampLayout = "20060102-150405"
+
+// GlobalsFilename is the file name used for the pg_dumpall --globals-only output.
+const GlobalsFilename = "globals.sql"
+
 // Service defines the interface for PostgreSQL dump operations.
 type Service interface {
 	Dump(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error)
+	DumpTo(ctx context.Context, cfg models.PostgresConfig, w io.Writer) (*models.PostgresDumpResult, error)
+	DumpGlobals(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error)
+	CheckVersion(ctx context.Context) (int, error)
+	ServerVersion(ctx context.Context, cfg models.PostgresConfig) (int, error)
 }
 
 // CommandExecutor allows mocking exec.Command in tests.
 type CommandExecutor interface {
 	ExecuteWithEnv(ctx context.Context, env []string, outputPath string, name string, args ...string) error
+	Stream(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error
+	Output(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
 }
 
 // DefaultExecutor is the default command executor using os/exec.
 type DefaultExecutor struct{}
 
-// ExecuteWithEnv runs pg_dump and writes output to the specified file.
+// ExecuteWithEnv runs the named binary (pg_dump, pg_dumpall) and writes its stdout to outputPath.
+// An empty outputPath discards stdout, for commands that write their own output (-f).
 func (e *DefaultExecutor) ExecuteWithEnv(ctx context.Context, env []string, outputPath string, name string, args ...string) error {
+	var stdout io.Writer
+	if outputPath != "" {
+		output, err := os.Create(outputPath) //nolint:gosec // outputPath is controlled by caller
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() { _ = output.Close() }()
+		stdout = output
+	}
+
+	return e.Stream(ctx, env, stdout, name, args...)
+}
+
+// Stream runs the named binary and writes its stdout to stdout, e.g. a pipe to restic.
+// A nil stdout discards the output.
+func (e *DefaultExecutor) Stream(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Env = append(os.Environ(), env...)
-
-	output, err := os.Create(outputPath) //nolint:gosec // outputPath is controlled by caller
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer func() { _ = output.Close() }()
+	cmd.Stdout = stdout
 
 	var stderr bytes.Buffer
-	cmd.Stdout = output
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
 		errMsg := strings.TrimSpace(stderr.String())
 		if errMsg != "" {
-			return fmt.Errorf("pg_dump failed: %w: %s", err, errMsg)
+			return fmt.Errorf("%s failed: %w: %s", name, err, errMsg)
 		}
-		return fmt.Errorf("pg_dump failed: %w", err)
+		return fmt.Errorf("%s failed: %w", name, err)
 	}
 
 	return nil
 }
 
+// Output runs the named binary (pg_dump, psql) and returns its stdout.
+func (e *DefaultExecutor) Output(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg != "" {
+			return output, fmt.Errorf("%s failed: %w: %s", name, err, errMsg)
+		}
+		return output, fmt.Errorf("%s failed: %w", name, err)
+	}
+
+	return output, nil
+}
+
 // Impl implements the PostgreSQL Service interface.
 type Impl struct {
 	executor CommandExecutor
@@ -92,26 +139,71 @@ func (s *Impl) Dump(ctx context.Context, cfg models.PostgresConfig, outputPath s
 		Str("output", outputPath).
 		Msg("starting PostgreSQL dump")
 
+	if cfg.Jobs > 1 && cfg.Format != FormatDirectory {
+		return &models.PostgresDumpResult{
+			OutputPath: outputPath,
+			Error:      fmt.Errorf("parallel jobs (%d) require the directory format, got %q", cfg.Jobs, cfg.Format),
+		}, nil
+	}
+
+	args := dumpArgs(cfg, outputPath)
+	return s.execute(ctx, cfg, outputPath, cfg.Format != FormatDirectory, "pg_dump", args), nil
+}
+
+// DumpTo runs pg_dump and writes the dump to w instead of a file, e.g. a pipe
+// to restic backup --stdin. The directory format cannot be written to a stream.
+func (s *Impl) DumpTo(ctx context.Context, cfg models.PostgresConfig, w io.Writer) (*models.PostgresDumpResult, error) {
+	s.logger.Info().
+		Str("host", cfg.Host).
+		Int("port", cfg.Port).
+		Str("database", cfg.Database).
+		Str("format", cfg.Format).
+		Msg("starting PostgreSQL dump to stream")
+
+	if cfg.Format == FormatDirectory {
+		return &models.PostgresDumpResult{
+			Error: fmt.Errorf("the directory format cannot be streamed"),
+		}, nil
+	}
+
 	start := time.Now()
+	counter := &countingWriter{w: w}
+	err := s.executor.Stream(ctx, connectionEnv(cfg), counter, "pg_dump", dumpArgs(cfg, "")...)
 	result := &models.PostgresDumpResult{
-		OutputPath: outputPath,
+		SizeBytes: counter.n,
+		Duration:  time.Since(start),
+		Error:     err,
 	}
-
-	// Ensure output directory exists
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0o750); err != nil {
-		result.Error = fmt.Errorf("failed to create output directory: %w", err)
-		result.Duration = time.Since(start)
+	if err != nil {
 		return result, nil
 	}
 
-	// Build pg_dump arguments
-	args := []string{
-		"-h", cfg.Host,
-		"-p", fmt.Sprintf("%d", cfg.Port),
-		"-U", cfg.Username,
-		"-d", cfg.Database,
-	}
+	s.logger.Info().
+		Str("database", cfg.Database).
+		Int64("size_bytes", result.SizeBytes).
+		Str("duration", result.Duration.Round(time.Millisecond).String()).
+		Msg("PostgreSQL dump streamed")
+
+	return result, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// dumpArgs builds the pg_dump arguments. outputPath is only used by the
+// directory format, which pg_dump writes itself.
+func dumpArgs(cfg models.PostgresConfig, outputPath string) []string {
+	args := connectionArgs(cfg)
+	args = append(args, "-d", cfg.Database)
 
 	// Add format flag
 	switch cfg.Format {
@@ -121,52 +213,191 @@ func (s *Impl) Dump(ctx context.Context, cfg models.PostgresConfig, outputPath s
 		args = append(args, "-Fp")
 	case FormatTar:
 		args = append(args, "-Ft")
+	case FormatDirectory:
+		// pg_dump creates the directory itself and cannot write it to stdout
+		args = append(args, "-Fd", "-f", outputPath)
 	default:
 		args = append(args, "-Fc") // Default to custom
 	}
 
-	// Set environment for password
+	if cfg.Jobs > 1 {
+		args = append(args, "-j", strconv.Itoa(cfg.Jobs))
+	}
+	if cfg.CompressionLevel != nil {
+		args = append(args, "-Z", strconv.Itoa(*cfg.CompressionLevel))
+	}
+
+	args = appendRepeated(args, "-T", cfg.ExcludeTables)
+	args = appendRepeated(args, "-t", cfg.IncludeTables)
+	args = appendRepeated(args, "-N", cfg.ExcludeSchemas)
+
+	return args
+}
+
+// DumpGlobals dumps roles, grants and tablespaces via pg_dumpall --globals-only.
+func (s *Impl) DumpGlobals(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+	s.logger.Info().
+		Str("host", cfg.Host).
+		Int("port", cfg.Port).
+		Str("output", outputPath).
+		Msg("starting PostgreSQL globals dump")
+
+	args := append(connectionArgs(cfg), "--globals-only")
+
+	return s.execute(ctx, cfg, outputPath, true, "pg_dumpall", args), nil
+}
+
+// versionPattern matches the major version in both "pg_dump (PostgreSQL) 16.1"
+// and "PostgreSQL 16.1 on x86_64-pc-linux-gnu, ..." from SELECT version().
+var versionPattern = regexp.MustCompile(`PostgreSQL\)?\s+(\d+)`)
+
+// CheckVersion returns the major version of the installed pg_dump.
+func (s *Impl) CheckVersion(ctx context.Context) (int, error) {
+	output, err := s.executor.Output(ctx, nil, "pg_dump", "--version")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pg_dump version: %w", err)
+	}
+	return parseMajorVersion(string(output))
+}
+
+// ServerVersion returns the major version of the PostgreSQL server.
+func (s *Impl) ServerVersion(ctx context.Context, cfg models.PostgresConfig) (int, error) {
+	args := connectionArgs(cfg)
+	args = append(args, "-d", cfg.Database, "-tA", "-c", "SELECT version()")
+
+	output, err := s.executor.Output(ctx, connectionEnv(cfg), "psql", args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get server version: %w", err)
+	}
+	return parseMajorVersion(string(output))
+}
+
+// parseMajorVersion extracts the major version from pg_dump --version or
+// SELECT version() output, e.g. "pg_dump (PostgreSQL) 14.20 (Homebrew)" is 14.
+func parseMajorVersion(output string) (int, error) {
+	m := versionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return 0, fmt.Errorf("unrecognized version output: %q", strings.TrimSpace(output))
+	}
+	return strconv.Atoi(m[1])
+}
+
+// connectionEnv returns the password and SSL settings passed to libpq.
+func connectionEnv(cfg models.PostgresConfig) []string {
 	env := []string{}
 	if cfg.Password != "" {
 		env = append(env, fmt.Sprintf("PGPASSWORD=%s", cfg.Password))
 	}
+	if cfg.SSLMode != "" {
+		env = append(env, fmt.Sprintf("PGSSLMODE=%s", cfg.SSLMode))
+	}
+	if cfg.SSLRootCert != "" {
+		env = append(env, fmt.Sprintf("PGSSLROOTCERT=%s", cfg.SSLRootCert))
+	}
+	return env
+}
 
-	// Execute pg_dump
-	if execErr := s.executor.ExecuteWithEnv(ctx, env, outputPath, "pg_dump", args...); execErr != nil {
-		// Clean up partial file
-		_ = os.Remove(outputPath)
-		result.Error = execErr
+// connectionArgs returns the host, port and user flags shared by pg_dump and pg_dumpall.
+func connectionArgs(cfg models.PostgresConfig) []string {
+	return []string{
+		"-h", cfg.Host,
+		"-p", fmt.Sprintf("%d", cfg.Port),
+		"-U", cfg.Username,
+	}
+}
+
+// execute runs binary with args. With captureStdout its output is written to
+// outputPath, otherwise the command is expected to create outputPath itself.
+func (s *Impl) execute(ctx context.Context, cfg models.PostgresConfig, outputPath string, captureStdout bool, binary string, args []string) *models.PostgresDumpResult {
+	start := time.Now()
+	result := &models.PostgresDumpResult{
+		OutputPath: outputPath,
+	}
+
+	// Ensure output directory exists
+	dir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		result.Error = fmt.Errorf("failed to create output directory: %w", err)
 		result.Duration = time.Since(start)
-		return result, nil //nolint:nilerr // error is stored in result struct by design
+		return result
+	}
+
+	// Set environment for password and SSL
+	env := connectionEnv(cfg)
+
+	stdoutPath := ""
+	if captureStdout {
+		stdoutPath = outputPath
 	}
 
-	// Get file size
-	if info, err := os.Stat(outputPath); err == nil {
-		result.SizeBytes = info.Size()
+	if execErr := s.executor.ExecuteWithEnv(ctx, env, stdoutPath, binary, args...); execErr != nil {
+		// Clean up partial file or directory
+		_ = os.RemoveAll(outputPath)
+		result.Error = execErr
+		result.Duration = time.Since(start)
+		return result
 	}
 
+	// Get file size (summed up for directory dumps)
+	result.SizeBytes = pathSize(outputPath)
+
 	result.Duration = time.Since(start)
 
 	s.logger.Info().
+		Str("binary", binary).
 		Str("output", outputPath).
 		Int64("size_bytes", result.SizeBytes).
 		Str("duration", result.Duration.Round(time.Millisecond).String()).
 		Msg("PostgreSQL dump completed")
 
-	return result, nil
+	return result
+}
+
+// appendRepeated appends flag followed by each value, e.g. -T a -T b.
+func appendRepeated(args []string, flag string, values []string) []string {
+	for _, v := range values {
+		args = append(args, flag, v)
+	}
+	return args
+}
+
+// pathSize returns the size of a file, or the total size of a directory's files.
+func pathSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil //nolint:nilerr // best effort, unreadable entries are skipped
+		}
+		if info, infoErr := d.Info(); infoErr == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
 }
 
 // GetOutputFilename returns a suggested output filename based on config.
 func GetOutputFilename(cfg models.PostgresConfig) string {
-	timestamp := time.Now().Format("20060102-150405")
-	ext := "dump"
+	timestamp := time.Now().Format(timestampLayout)
+	return fmt.Sprintf("%s-%s.%s", cfg.Database, timestamp, fileExtension(cfg.Format))
+}
 
-	switch cfg.Format {
+// StreamFilename returns the file name of a dump streamed into restic. It has
+// no timestamp, so restic keeps the same path in every snapshot.
+func StreamFilename(cfg models.PostgresConfig) string {
+	return fmt.Sprintf("%s.%s", cfg.Database, fileExtension(cfg.Format))
+}
+
+// fileExtension returns the dump file extension for a pg_dump format.
+func fileExtension(format string) string {
+	switch format {
 	case FormatPlain:
-		ext = "sql"
+		return "sql"
 	case FormatTar:
-		ext = FormatTar
+		return FormatTar
+	case FormatDirectory:
+		return "dir"
+	default:
+		return "dump"
 	}
-
-	return fmt.Sprintf("%s-%s.%s", cfg.Database, timestamp, ext)
 }
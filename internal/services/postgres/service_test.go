@@ -1,12 +1,14 @@
 package postgres
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	"github.com/rs/zerolog"
@@ -16,6 +18,22 @@ import (
 
 type mockExecutor struct {
 	executeFunc func(ctx context.Context, env []string, outputPath string, name string, args ...string) error
+	streamFunc  func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error
+	outputFunc  func(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
+}
+
+func (m *mockExecutor) Stream(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+	if m.streamFunc != nil {
+		return m.streamFunc(ctx, env, stdout, name, args...)
+	}
+	return nil
+}
+
+func (m *mockExecutor) Output(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	if m.outputFunc != nil {
+		return m.outputFunc(ctx, env, name, args...)
+	}
+	return nil, nil
 }
 
 func (m *mockExecutor) ExecuteWithEnv(ctx context.Context, env []string, outputPath string, name string, args ...string) error {
@@ -136,6 +154,199 @@ func TestDump_TarFormat(t *testing.T) {
 	assert.Contains(t, capturedArgs, "-Ft")
 }
 
+func TestDump_DirectoryFormatParallel(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputPath := filepath.Join(tmpDir, "test.dir")
+
+	var capturedArgs []string
+	var capturedOutput string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedArgs = args
+			capturedOutput = op
+			// pg_dump creates the directory itself
+			if err := os.MkdirAll(outputPath, 0o750); err != nil {
+				return err
+			}
+			return os.WriteFile(filepath.Join(outputPath, "toc.dat"), []byte("toc"), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	cfg.Format = "directory"
+	cfg.Jobs = 4
+	level := 6
+	cfg.CompressionLevel = &level
+
+	result, err := svc.Dump(context.Background(), cfg, outputPath)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, int64(3), result.SizeBytes)
+	assert.Empty(t, capturedOutput, "directory dumps must not redirect stdout")
+	assert.Subset(t, capturedArgs, []string{"-Fd", "-f", outputPath, "-j", "4", "-Z", "6"})
+}
+
+func TestDump_CompressionLevel(t *testing.T) {
+	tmpDir := t.TempDir()
+	outputPath := filepath.Join(tmpDir, "test.dump")
+
+	var capturedArgs []string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedArgs = args
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	level := 9
+	cfg.CompressionLevel = &level
+
+	result, err := svc.Dump(context.Background(), cfg, outputPath)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.Contains(t, capturedArgs, "-Z")
+	assert.Contains(t, capturedArgs, "9")
+	assert.NotContains(t, capturedArgs, "-j")
+}
+
+func TestDump_CompressionLevelZero(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "test.dump")
+
+	var capturedArgs []string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedArgs = args
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	level := 0
+	cfg.CompressionLevel = &level
+
+	_, err := svc.Dump(context.Background(), cfg, outputPath)
+
+	require.NoError(t, err)
+	assert.Subset(t, capturedArgs, []string{"-Z", "0"})
+}
+
+func TestDump_CompressionLevelUnset(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "test.dump")
+
+	var capturedArgs []string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedArgs = args
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+
+	_, err := svc.Dump(context.Background(), testConfig(), outputPath)
+
+	require.NoError(t, err)
+	assert.NotContains(t, capturedArgs, "-Z")
+}
+
+func TestDump_ParallelJobsRequireDirectoryFormat(t *testing.T) {
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			t.Fatal("executor should not be called")
+			return nil
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	cfg.Jobs = 2
+
+	result, err := svc.Dump(context.Background(), cfg, filepath.Join(t.TempDir(), "test.dump"))
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "directory format")
+}
+
+func TestDump_TableAndSchemaFilters(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *models.PostgresConfig)
+		expected []string
+	}{
+		{
+			name:     "exclude tables",
+			modify:   func(cfg *models.PostgresConfig) { cfg.ExcludeTables = []string{"logs", "audit.*"} },
+			expected: []string{"-T", "logs", "-T", "audit.*"},
+		},
+		{
+			name:     "include tables",
+			modify:   func(cfg *models.PostgresConfig) { cfg.IncludeTables = []string{"users", "orders"} },
+			expected: []string{"-t", "users", "-t", "orders"},
+		},
+		{
+			name:     "exclude schemas",
+			modify:   func(cfg *models.PostgresConfig) { cfg.ExcludeSchemas = []string{"staging", "tmp"} },
+			expected: []string{"-N", "staging", "-N", "tmp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedArgs []string
+			executor := &mockExecutor{
+				executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+					capturedArgs = args
+					return os.WriteFile(op, []byte(""), 0o600)
+				},
+			}
+
+			svc := NewWithExecutor(testLogger(), executor)
+			cfg := testConfig()
+			tt.modify(&cfg)
+
+			result, err := svc.Dump(context.Background(), cfg, filepath.Join(t.TempDir(), "test.dump"))
+
+			require.NoError(t, err)
+			assert.Nil(t, result.Error)
+			// Filters are appended last, in order
+			assert.Equal(t, tt.expected, capturedArgs[len(capturedArgs)-len(tt.expected):])
+		})
+	}
+}
+
+func TestDump_NoFilters(t *testing.T) {
+	var capturedArgs []string
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedArgs = args
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	cfg.ExcludeTables = []string{}
+
+	result, err := svc.Dump(context.Background(), cfg, filepath.Join(t.TempDir(), "test.dump"))
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.NotContains(t, capturedArgs, "-T")
+	assert.NotContains(t, capturedArgs, "-t")
+	assert.NotContains(t, capturedArgs, "-N")
+}
+
 func TestDump_ExecutorError(t *testing.T) {
 	tmpDir := t.TempDir()
 	outputPath := filepath.Join(tmpDir, "test.dump")
@@ -187,6 +398,49 @@ func TestDump_NoPassword(t *testing.T) {
 	}
 }
 
+func TestDump_SSLEnv(t *testing.T) {
+	var capturedEnv []string
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedEnv = env
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	cfg := testConfig()
+	cfg.SSLMode = "verify-full"
+	cfg.SSLRootCert = "/etc/ssl/pg-ca.crt"
+
+	result, err := svc.Dump(context.Background(), cfg, filepath.Join(t.TempDir(), "test.dump"))
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.Contains(t, capturedEnv, "PGSSLMODE=verify-full")
+	assert.Contains(t, capturedEnv, "PGSSLROOTCERT=/etc/ssl/pg-ca.crt")
+}
+
+func TestDump_NoSSLEnv(t *testing.T) {
+	var capturedEnv []string
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedEnv = env
+			return os.WriteFile(op, []byte(""), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+
+	result, err := svc.Dump(context.Background(), testConfig(), filepath.Join(t.TempDir(), "test.dump"))
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	for _, e := range capturedEnv {
+		assert.NotContains(t, e, "PGSSLMODE")
+		assert.NotContains(t, e, "PGSSLROOTCERT")
+	}
+}
+
 func TestDump_CreatesDirectory(t *testing.T) {
 	tmpDir := t.TempDir()
 	outputPath := filepath.Join(tmpDir, "subdir", "nested", "test.dump")
@@ -208,6 +462,54 @@ func TestDump_CreatesDirectory(t *testing.T) {
 	assert.NoError(t, statErr)
 }
 
+func TestDumpGlobals_Success(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "globals.sql")
+
+	var capturedName string
+	var capturedArgs []string
+	var capturedEnv []string
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			capturedName = name
+			capturedArgs = args
+			capturedEnv = env
+			return os.WriteFile(op, []byte("CREATE ROLE app;"), 0o600)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.DumpGlobals(context.Background(), testConfig(), outputPath)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, outputPath, result.OutputPath)
+	assert.Greater(t, result.SizeBytes, int64(0))
+
+	assert.Equal(t, "pg_dumpall", capturedName)
+	assert.Equal(t, []string{"-h", "localhost", "-p", "5432", "-U", "postgres", "--globals-only"}, capturedArgs)
+	assert.Contains(t, capturedEnv, "PGPASSWORD=secret")
+}
+
+func TestDumpGlobals_ExecutorError(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "globals.sql")
+
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, env []string, op string, name string, args ...string) error {
+			_ = os.WriteFile(op, []byte("partial"), 0o600)
+			return errors.New("pg_dumpall failed: permission denied")
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.DumpGlobals(context.Background(), testConfig(), outputPath)
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "permission denied")
+	assert.NoFileExists(t, outputPath)
+}
+
 func TestGetOutputFilename(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -217,6 +519,7 @@ func TestGetOutputFilename(t *testing.T) {
 		{"custom format", "custom", ".dump"},
 		{"plain format", "plain", ".sql"},
 		{"tar format", "tar", ".tar"},
+		{"directory format", "directory", ".dir"},
 		{"empty format", "", ".dump"},
 	}
 
@@ -254,7 +557,7 @@ func TestDefaultExecutor_CapturesStderr(t *testing.T) {
 	)
 
 	require.Error(t, err)
-	assert.Contains(t, err.Error(), "pg_dump failed")
+	assert.Contains(t, err.Error(), "sh failed")
 	assert.Contains(t, err.Error(), "error message")
 }
 
@@ -280,3 +583,250 @@ func TestDefaultExecutor_SuccessNoStderr(t *testing.T) {
 	require.NoError(t, readErr)
 	assert.Contains(t, string(content), "success output")
 }
+
+func TestRotateDumps(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"myapp-20240112-030000.dump",
+		"myapp-20240115-030000.dump",
+		"myapp-20240113-030000.dump",
+		"myapp-20240114-030000.dump",
+		"otherdb-20240101-030000.dump", // other database
+		"myapp-notes.txt",              // not a dump
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("dump"), 0o600))
+	}
+	// Directory format dumps are rotated as a whole
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "myapp-20240111-030000.dir"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "myapp-20240111-030000.dir", "toc.dat"), []byte("toc"), 0o600))
+
+	removed, err := RotateDumps(dir, "myapp", 2)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "myapp-20240113-030000.dump"),
+		filepath.Join(dir, "myapp-20240112-030000.dump"),
+		filepath.Join(dir, "myapp-20240111-030000.dir"),
+	}, removed)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{
+		"myapp-20240115-030000.dump",
+		"myapp-20240114-030000.dump",
+		"otherdb-20240101-030000.dump",
+		"myapp-notes.txt",
+	}, names)
+}
+
+func TestRotateDumps_FewerThanKeep(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "myapp-20240115-030000.sql"), []byte("dump"), 0o600))
+
+	removed, err := RotateDumps(dir, "myapp", 3)
+
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	assert.FileExists(t, filepath.Join(dir, "myapp-20240115-030000.sql"))
+}
+
+func TestDumpTimestamp(t *testing.T) {
+	ts, ok := dumpTimestamp("myapp-20240115-030405.dump", "myapp")
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2024, 1, 15, 3, 4, 5, 0, time.Local), ts)
+
+	_, ok = dumpTimestamp("my-app-20240115-030405.dump", "my")
+	assert.False(t, ok, "database name prefix must match exactly")
+	_, ok = dumpTimestamp("myapp-20240115-030405", "myapp")
+	assert.False(t, ok, "extension is required")
+	_, ok = dumpTimestamp("myapp-latest.dump", "myapp")
+	assert.False(t, ok)
+}
+
+func TestMoveDump(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "myapp-20240115-030000.dump")
+	require.NoError(t, os.WriteFile(src, []byte("dump"), 0o600))
+	localDir := filepath.Join(t.TempDir(), "pg-dumps")
+
+	dst, err := MoveDump(src, localDir)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(localDir, "myapp-20240115-030000.dump"), dst)
+	assert.NoFileExists(t, src)
+	content, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, "dump", string(content))
+}
+
+func TestCopyPath_Directory(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "myapp-20240115-030000.dir")
+	require.NoError(t, os.MkdirAll(src, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "toc.dat"), []byte("toc"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "3001.dat.gz"), []byte("data"), 0o600))
+	dst := filepath.Join(t.TempDir(), "myapp-20240115-030000.dir")
+
+	require.NoError(t, copyPath(src, dst))
+
+	content, err := os.ReadFile(filepath.Join(dst, "3001.dat.gz"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+	assert.FileExists(t, filepath.Join(dst, "toc.dat"))
+}
+
+func TestParseMajorVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected int
+	}{
+		{name: "pg_dump", output: "pg_dump (PostgreSQL) 16.1\n", expected: 16},
+		{name: "homebrew", output: "pg_dump (PostgreSQL) 14.20 (Homebrew)\n", expected: 14},
+		{name: "debian", output: "pg_dump (PostgreSQL) 15.5 (Debian 15.5-1.pgdg120+1)\n", expected: 15},
+		{name: "legacy", output: "pg_dump (PostgreSQL) 9.6.24\n", expected: 9},
+		{name: "server", output: "PostgreSQL 17.2 on x86_64-pc-linux-musl, compiled by gcc (Alpine 13.2.1) 13.2.1, 64-bit\n", expected: 17},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			major, err := parseMajorVersion(tt.output)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, major)
+		})
+	}
+}
+
+func TestParseMajorVersion_Invalid(t *testing.T) {
+	_, err := parseMajorVersion("command not found")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unrecognized version output")
+}
+
+func TestCheckVersion(t *testing.T) {
+	var capturedName string
+	var capturedArgs []string
+	executor := &mockExecutor{
+		outputFunc: func(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+			capturedName = name
+			capturedArgs = args
+			return []byte("pg_dump (PostgreSQL) 16.4\n"), nil
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	major, err := svc.CheckVersion(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 16, major)
+	assert.Equal(t, "pg_dump", capturedName)
+	assert.Equal(t, []string{"--version"}, capturedArgs)
+}
+
+func TestServerVersion(t *testing.T) {
+	var capturedName string
+	var capturedArgs, capturedEnv []string
+	executor := &mockExecutor{
+		outputFunc: func(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+			capturedName = name
+			capturedArgs = args
+			capturedEnv = env
+			return []byte("PostgreSQL 16.1 (Debian 16.1-1.pgdg120+1) on x86_64-pc-linux-gnu\n"), nil
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	major, err := svc.ServerVersion(context.Background(), testConfig())
+
+	require.NoError(t, err)
+	assert.Equal(t, 16, major)
+	assert.Equal(t, "psql", capturedName)
+	assert.Equal(t, []string{"-h", "localhost", "-p", "5432", "-U", "postgres", "-d", "testdb", "-tA", "-c", "SELECT version()"}, capturedArgs)
+	assert.Contains(t, capturedEnv, "PGPASSWORD=secret")
+}
+
+func TestServerVersion_Error(t *testing.T) {
+	executor := &mockExecutor{
+		outputFunc: func(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+			return nil, errors.New("psql failed: connection refused")
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	_, err := svc.ServerVersion(context.Background(), testConfig())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection refused")
+}
+
+func TestDumpTo_WritesToWriter(t *testing.T) {
+	var capturedArgs []string
+	executor := &mockExecutor{
+		streamFunc: func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+			capturedArgs = args
+			_, err := stdout.Write([]byte("PGDMP"))
+			return err
+		},
+	}
+
+	var buf bytes.Buffer
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.DumpTo(context.Background(), testConfig(), &buf)
+
+	require.NoError(t, err)
+	require.NoError(t, result.Error)
+	assert.Equal(t, "PGDMP", buf.String())
+	assert.Equal(t, int64(5), result.SizeBytes)
+	assert.Empty(t, result.OutputPath)
+	assert.NotContains(t, capturedArgs, "-f")
+}
+
+func TestDumpTo_DirectoryFormatRejected(t *testing.T) {
+	cfg := testConfig()
+	cfg.Format = FormatDirectory
+
+	svc := NewWithExecutor(testLogger(), &mockExecutor{})
+	result, err := svc.DumpTo(context.Background(), cfg, io.Discard)
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "cannot be streamed")
+}
+
+func TestDumpTo_Failure(t *testing.T) {
+	executor := &mockExecutor{
+		streamFunc: func(ctx context.Context, env []string, stdout io.Writer, name string, args ...string) error {
+			return errors.New("pg_dump failed: connection refused")
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.DumpTo(context.Background(), testConfig(), io.Discard)
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "connection refused")
+}
+
+func TestStreamFilename(t *testing.T) {
+	cfg := testConfig()
+	cfg.Database = "app"
+
+	assert.Equal(t, "app.dump", StreamFilename(cfg))
+	cfg.Format = FormatPlain
+	assert.Equal(t, "app.sql", StreamFilename(cfg))
+}
+
+func TestDefaultExecutor_Stream(t *testing.T) {
+	executor := &DefaultExecutor{}
+
+	var buf bytes.Buffer
+	err := executor.Stream(context.Background(), nil, &buf, "sh", "-c", "echo streamed")
+
+	require.NoError(t, err)
+	assert.Equal(t, "streamed\n", buf.String())
+}
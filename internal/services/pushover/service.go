@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fgeck/gorestic-homelab/internal/format"
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	"github.com/rs/zerolog"
 )
@@ -105,6 +106,9 @@ func (s *Impl) formatMessage(msg models.PushoverMessage) (string, string) {
 	} else {
 		title = "Backup Failed"
 	}
+	if msg.DryRun {
+		title += " (dry-run)"
+	}
 
 	var b bytes.Buffer
 
@@ -119,15 +123,23 @@ func (s *Impl) formatMessage(msg models.PushoverMessage) (string, string) {
 		fmt.Fprintf(&b, "  Files new: %d\n", msg.FilesNew)
 		fmt.Fprintf(&b, "  Files changed: %d\n", msg.FilesChanged)
 		fmt.Fprintf(&b, "  Files unmodified: %d\n", msg.FilesUnmodified)
-		fmt.Fprintf(&b, "  Data added: %s\n", formatBytes(msg.DataAdded))
+		fmt.Fprintf(&b, "  Data added: %s\n", format.Bytes(msg.DataAdded))
 		fmt.Fprintf(&b, "  Total files: %d\n", msg.TotalFiles)
-		fmt.Fprintf(&b, "  Total size: %s\n", formatBytes(msg.TotalBytes))
+		fmt.Fprintf(&b, "  Total size: %s\n", format.Bytes(msg.TotalBytes))
 
 		if msg.SnapshotsRemoved > 0 || msg.SnapshotsKept > 0 {
 			b.WriteString("\nRetention:\n")
 			fmt.Fprintf(&b, "  Snapshots kept: %d\n", msg.SnapshotsKept)
 			fmt.Fprintf(&b, "  Snapshots removed: %d\n", msg.SnapshotsRemoved)
 		}
+
+		if msg.RepoTotalSize > 0 {
+			b.WriteString("\nRepository:\n")
+			fmt.Fprintf(&b, "  Size: %s\n", format.Bytes(msg.RepoTotalSize))
+			if msg.SnapshotCount > 0 {
+				fmt.Fprintf(&b, "  Snapshots: %d\n", msg.SnapshotCount)
+			}
+		}
 	} else {
 		b.WriteString("\nError Details:\n")
 		fmt.Fprintf(&b, "  Failed step: %s\n", msg.FailedStep)
@@ -136,16 +148,3 @@ func (s *Impl) formatMessage(msg models.PushoverMessage) (string, string) {
 
 	return title, b.String()
 }
-
-func formatBytes(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
-	}
-	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
-}
@@ -190,6 +190,8 @@ func TestFormatMessage_Success(t *testing.T) {
 		TotalBytes:       1024 * 1024 * 1024 * 2, // 2 GB
 		SnapshotsRemoved: 3,
 		SnapshotsKept:    30,
+		RepoTotalSize:    1024 * 1024 * 1024 * 5, // 5 GB
+		SnapshotCount:    42,
 	}
 
 	title, body := svc.formatMessage(msg)
@@ -203,6 +205,8 @@ func TestFormatMessage_Success(t *testing.T) {
 	assert.Contains(t, body, "Files unmodified: 1000")
 	assert.Contains(t, body, "Snapshots kept: 30")
 	assert.Contains(t, body, "Snapshots removed: 3")
+	assert.Contains(t, body, "Size: 5.0 GiB")
+	assert.Contains(t, body, "Snapshots: 42")
 	// Verify no HTML tags
 	assert.NotContains(t, body, "<b>")
 	assert.NotContains(t, body, "<code>")
@@ -228,28 +232,6 @@ func TestFormatMessage_Failure(t *testing.T) {
 	assert.Contains(t, body, "timeout waiting for target")
 }
 
-func TestFormatBytes(t *testing.T) {
-	tests := []struct {
-		bytes    int64
-		expected string
-	}{
-		{0, "0 B"},
-		{500, "500 B"},
-		{1024, "1.0 KiB"},
-		{1024 * 1024, "1.0 MiB"},
-		{1024 * 1024 * 1024, "1.0 GiB"},
-		{1024 * 1024 * 1024 * 2, "2.0 GiB"},
-		{1536 * 1024, "1.5 MiB"},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.expected, func(t *testing.T) {
-			result := formatBytes(tt.bytes)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestSendNotification_ContextCancelled(t *testing.T) {
 	httpClient := &mockHTTPClient{
 		doFunc: func(req *http.Request) (*http.Response, error) {
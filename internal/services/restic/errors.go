@@ -0,0 +1,118 @@
+package restic
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+)
+
+// errorPatterns maps fragments of restic's output to error kinds. They are
+// matched in order: restic adds "Is there a repository at the following
+// location?" to every failure to open the repository, so a network error has
+// to win over a missing repository.
+var errorPatterns = []struct {
+	kind      models.ErrorKind
+	fragments []string
+}{
+	{models.ErrorKindDiskFull, []string{
+		"no space left on device",
+		"disk quota exceeded",
+	}},
+	{models.ErrorKindWrongPassword, []string{
+		"wrong password or no key found",
+	}},
+	{models.ErrorKindLocked, []string{
+		"repository is already locked",
+		"unable to create lock",
+		"locked exclusively",
+	}},
+	{models.ErrorKindNetwork, []string{
+		"connection refused",
+		"connection reset",
+		"broken pipe",
+		"no route to host",
+		"network is unreachable",
+		"no such host",
+		"timeout",
+		"timed out",
+		"tls handshake",
+		"unexpected eof",
+		"502 bad gateway",
+		"503 service unavailable",
+	}},
+	{models.ErrorKindCorruption, []string{
+		"ciphertext verification failed",
+		"hash mismatch",
+		"pack id does not match",
+		"blob id does not match",
+		"unexpected pack id",
+		"is damaged",
+		"pack file cannot be listed",
+		"repository contains errors",
+		"invalid data returned",
+	}},
+	{models.ErrorKindNotInitialized, []string{
+		"is there a repository at the following location",
+		"repository does not exist",
+		"config file does not exist",
+	}},
+}
+
+// ClassifyError returns the kind of a failed restic command from its output
+// and the error returned when running it.
+func ClassifyError(output string, err error) models.ErrorKind {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) {
+		return models.ErrorKindNetwork
+	}
+
+	text := strings.ToLower(output)
+	if err != nil {
+		text += "\n" + strings.ToLower(err.Error())
+	}
+	for _, pattern := range errorPatterns {
+		for _, fragment := range pattern.fragments {
+			if strings.Contains(text, fragment) {
+				return pattern.kind
+			}
+		}
+	}
+	return models.ErrorKindUnknown
+}
+
+// Error is a failed restic command together with its kind and output.
+type Error struct {
+	Kind   models.ErrorKind
+	Err    error
+	Output string // combined output of the failed command, empty if there was none
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// classify wraps err with the kind classified from the command output.
+func classify(output []byte, err error) *Error {
+	return &Error{
+		Kind:   ClassifyError(string(output), err),
+		Err:    err,
+		Output: strings.TrimSpace(string(output)),
+	}
+}
+
+// KindOf returns the kind of err. Errors not created by this package are
+// classified from their message.
+func KindOf(err error) models.ErrorKind {
+	var resticErr *Error
+	if errors.As(err, &resticErr) {
+		return resticErr.Kind
+	}
+	return ClassifyError("", err)
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	mock "github.com/stretchr/testify/mock"
@@ -304,3 +305,98 @@ func (_c *MockCommandExecutor_ExecuteWithEnvStreaming_Call) RunAndReturn(run fun
 	_c.Call.Return(run)
 	return _c
 }
+
+// ExecuteWithStdin provides a mock function for the type MockCommandExecutor
+func (_mock *MockCommandExecutor) ExecuteWithStdin(ctx context.Context, env []string, stdin io.Reader, name string, args ...string) ([]byte, error) {
+	var tmpRet mock.Arguments
+	if len(args) > 0 {
+		tmpRet = _mock.Called(ctx, env, stdin, name, args)
+	} else {
+		tmpRet = _mock.Called(ctx, env, stdin, name)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteWithStdin")
+	}
+
+	var r0 []byte
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, io.Reader, string, ...string) ([]byte, error)); ok {
+		return returnFunc(ctx, env, stdin, name, args...)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string, io.Reader, string, ...string) []byte); ok {
+		r0 = returnFunc(ctx, env, stdin, name, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string, io.Reader, string, ...string) error); ok {
+		r1 = returnFunc(ctx, env, stdin, name, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCommandExecutor_ExecuteWithStdin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecuteWithStdin'
+type MockCommandExecutor_ExecuteWithStdin_Call struct {
+	*mock.Call
+}
+
+// ExecuteWithStdin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - env []string
+//   - stdin io.Reader
+//   - name string
+//   - args ...string
+func (_e *MockCommandExecutor_Expecter) ExecuteWithStdin(ctx interface{}, env interface{}, stdin interface{}, name interface{}, args ...interface{}) *MockCommandExecutor_ExecuteWithStdin_Call {
+	return &MockCommandExecutor_ExecuteWithStdin_Call{Call: _e.mock.On("ExecuteWithStdin",
+		append([]interface{}{ctx, env, stdin, name}, args...)...)}
+}
+
+func (_c *MockCommandExecutor_ExecuteWithStdin_Call) Run(run func(ctx context.Context, env []string, stdin io.Reader, name string, args ...string)) *MockCommandExecutor_ExecuteWithStdin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		var arg2 io.Reader
+		if args[2] != nil {
+			arg2 = args[2].(io.Reader)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		var arg4 []string
+		var variadicArgs []string
+		if len(args) > 4 {
+			variadicArgs = args[4].([]string)
+		}
+		arg4 = variadicArgs
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCommandExecutor_ExecuteWithStdin_Call) Return(bytes []byte, err error) *MockCommandExecutor_ExecuteWithStdin_Call {
+	_c.Call.Return(bytes, err)
+	return _c
+}
+
+func (_c *MockCommandExecutor_ExecuteWithStdin_Call) RunAndReturn(run func(ctx context.Context, env []string, stdin io.Reader, name string, args ...string) ([]byte, error)) *MockCommandExecutor_ExecuteWithStdin_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	"io"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	mock "github.com/stretchr/testify/mock"
@@ -39,8 +40,8 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 }
 
 // Backup provides a mock function for the type MockService
-func (_mock *MockService) Backup(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings) (*models.BackupResult, error) {
-	ret := _mock.Called(ctx, cfg, settings)
+func (_mock *MockService) Backup(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, progressCb models.ResticProgressCallback) (*models.BackupResult, error) {
+	ret := _mock.Called(ctx, cfg, settings, progressCb)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Backup")
@@ -48,18 +49,18 @@ func (_mock *MockService) Backup(ctx context.Context, cfg models.ResticConfig, s
 
 	var r0 *models.BackupResult
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.BackupSettings) (*models.BackupResult, error)); ok {
-		return returnFunc(ctx, cfg, settings)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.BackupSettings, models.ResticProgressCallback) (*models.BackupResult, error)); ok {
+		return returnFunc(ctx, cfg, settings, progressCb)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.BackupSettings) *models.BackupResult); ok {
-		r0 = returnFunc(ctx, cfg, settings)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.BackupSettings, models.ResticProgressCallback) *models.BackupResult); ok {
+		r0 = returnFunc(ctx, cfg, settings, progressCb)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*models.BackupResult)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.BackupSettings) error); ok {
-		r1 = returnFunc(ctx, cfg, settings)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.BackupSettings, models.ResticProgressCallback) error); ok {
+		r1 = returnFunc(ctx, cfg, settings, progressCb)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -75,11 +76,12 @@ type MockService_Backup_Call struct {
 //   - ctx context.Context
 //   - cfg models.ResticConfig
 //   - settings models.BackupSettings
-func (_e *MockService_Expecter) Backup(ctx interface{}, cfg interface{}, settings interface{}) *MockService_Backup_Call {
-	return &MockService_Backup_Call{Call: _e.mock.On("Backup", ctx, cfg, settings)}
+//   - progressCb models.ResticProgressCallback
+func (_e *MockService_Expecter) Backup(ctx interface{}, cfg interface{}, settings interface{}, progressCb interface{}) *MockService_Backup_Call {
+	return &MockService_Backup_Call{Call: _e.mock.On("Backup", ctx, cfg, settings, progressCb)}
 }
 
-func (_c *MockService_Backup_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings)) *MockService_Backup_Call {
+func (_c *MockService_Backup_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, progressCb models.ResticProgressCallback)) *MockService_Backup_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -93,10 +95,15 @@ func (_c *MockService_Backup_Call) Run(run func(ctx context.Context, cfg models.
 		if args[2] != nil {
 			arg2 = args[2].(models.BackupSettings)
 		}
+		var arg3 models.ResticProgressCallback
+		if args[3] != nil {
+			arg3 = args[3].(models.ResticProgressCallback)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -107,7 +114,87 @@ func (_c *MockService_Backup_Call) Return(backupResult *models.BackupResult, err
 	return _c
 }
 
-func (_c *MockService_Backup_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings) (*models.BackupResult, error)) *MockService_Backup_Call {
+func (_c *MockService_Backup_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, progressCb models.ResticProgressCallback) (*models.BackupResult, error)) *MockService_Backup_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// BackupStdin provides a mock function for the type MockService
+func (_mock *MockService) BackupStdin(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader) (*models.BackupResult, error) {
+	ret := _mock.Called(ctx, cfg, settings, stdin)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackupStdin")
+	}
+
+	var r0 *models.BackupResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.BackupSettings, io.Reader) (*models.BackupResult, error)); ok {
+		return returnFunc(ctx, cfg, settings, stdin)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.BackupSettings, io.Reader) *models.BackupResult); ok {
+		r0 = returnFunc(ctx, cfg, settings, stdin)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.BackupResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.BackupSettings, io.Reader) error); ok {
+		r1 = returnFunc(ctx, cfg, settings, stdin)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_BackupStdin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BackupStdin'
+type MockService_BackupStdin_Call struct {
+	*mock.Call
+}
+
+// BackupStdin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+//   - settings models.BackupSettings
+//   - stdin io.Reader
+func (_e *MockService_Expecter) BackupStdin(ctx interface{}, cfg interface{}, settings interface{}, stdin interface{}) *MockService_BackupStdin_Call {
+	return &MockService_BackupStdin_Call{Call: _e.mock.On("BackupStdin", ctx, cfg, settings, stdin)}
+}
+
+func (_c *MockService_BackupStdin_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader)) *MockService_BackupStdin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		var arg2 models.BackupSettings
+		if args[2] != nil {
+			arg2 = args[2].(models.BackupSettings)
+		}
+		var arg3 io.Reader
+		if args[3] != nil {
+			arg3 = args[3].(io.Reader)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_BackupStdin_Call) Return(backupResult *models.BackupResult, err error) *MockService_BackupStdin_Call {
+	_c.Call.Return(backupResult, err)
+	return _c
+}
+
+func (_c *MockService_BackupStdin_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader) (*models.BackupResult, error)) *MockService_BackupStdin_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -186,6 +273,226 @@ func (_c *MockService_Check_Call) RunAndReturn(run func(ctx context.Context, cfg
 	return _c
 }
 
+// CheckBinary provides a mock function for the type MockService
+func (_mock *MockService) CheckBinary(ctx context.Context) (string, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckBinary")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_CheckBinary_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckBinary'
+type MockService_CheckBinary_Call struct {
+	*mock.Call
+}
+
+// CheckBinary is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockService_Expecter) CheckBinary(ctx interface{}) *MockService_CheckBinary_Call {
+	return &MockService_CheckBinary_Call{Call: _e.mock.On("CheckBinary", ctx)}
+}
+
+func (_c *MockService_CheckBinary_Call) Run(run func(ctx context.Context)) *MockService_CheckBinary_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_CheckBinary_Call) Return(s string, err error) *MockService_CheckBinary_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockService_CheckBinary_Call) RunAndReturn(run func(ctx context.Context) (string, error)) *MockService_CheckBinary_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Copy provides a mock function for the type MockService
+func (_mock *MockService) Copy(ctx context.Context, srcCfg models.ResticConfig, dstCfg models.ResticConfig, opts models.CopyOptions) (*models.CopyResult, error) {
+	ret := _mock.Called(ctx, srcCfg, dstCfg, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Copy")
+	}
+
+	var r0 *models.CopyResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.ResticConfig, models.CopyOptions) (*models.CopyResult, error)); ok {
+		return returnFunc(ctx, srcCfg, dstCfg, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.ResticConfig, models.CopyOptions) *models.CopyResult); ok {
+		r0 = returnFunc(ctx, srcCfg, dstCfg, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.CopyResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.ResticConfig, models.CopyOptions) error); ok {
+		r1 = returnFunc(ctx, srcCfg, dstCfg, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Copy_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Copy'
+type MockService_Copy_Call struct {
+	*mock.Call
+}
+
+// Copy is a helper method to define mock.On call
+//   - ctx context.Context
+//   - srcCfg models.ResticConfig
+//   - dstCfg models.ResticConfig
+//   - opts models.CopyOptions
+func (_e *MockService_Expecter) Copy(ctx interface{}, srcCfg interface{}, dstCfg interface{}, opts interface{}) *MockService_Copy_Call {
+	return &MockService_Copy_Call{Call: _e.mock.On("Copy", ctx, srcCfg, dstCfg, opts)}
+}
+
+func (_c *MockService_Copy_Call) Run(run func(ctx context.Context, srcCfg models.ResticConfig, dstCfg models.ResticConfig, opts models.CopyOptions)) *MockService_Copy_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		var arg2 models.ResticConfig
+		if args[2] != nil {
+			arg2 = args[2].(models.ResticConfig)
+		}
+		var arg3 models.CopyOptions
+		if args[3] != nil {
+			arg3 = args[3].(models.CopyOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Copy_Call) Return(copyResult *models.CopyResult, err error) *MockService_Copy_Call {
+	_c.Call.Return(copyResult, err)
+	return _c
+}
+
+func (_c *MockService_Copy_Call) RunAndReturn(run func(ctx context.Context, srcCfg models.ResticConfig, dstCfg models.ResticConfig, opts models.CopyOptions) (*models.CopyResult, error)) *MockService_Copy_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Diff provides a mock function for the type MockService
+func (_mock *MockService) Diff(ctx context.Context, cfg models.ResticConfig, snapA string, snapB string) (*models.DiffResult, error) {
+	ret := _mock.Called(ctx, cfg, snapA, snapB)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Diff")
+	}
+
+	var r0 *models.DiffResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, string, string) (*models.DiffResult, error)); ok {
+		return returnFunc(ctx, cfg, snapA, snapB)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, string, string) *models.DiffResult); ok {
+		r0 = returnFunc(ctx, cfg, snapA, snapB)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.DiffResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, string, string) error); ok {
+		r1 = returnFunc(ctx, cfg, snapA, snapB)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Diff_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Diff'
+type MockService_Diff_Call struct {
+	*mock.Call
+}
+
+// Diff is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+//   - snapA string
+//   - snapB string
+func (_e *MockService_Expecter) Diff(ctx interface{}, cfg interface{}, snapA interface{}, snapB interface{}) *MockService_Diff_Call {
+	return &MockService_Diff_Call{Call: _e.mock.On("Diff", ctx, cfg, snapA, snapB)}
+}
+
+func (_c *MockService_Diff_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, snapA string, snapB string)) *MockService_Diff_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 string
+		if args[3] != nil {
+			arg3 = args[3].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Diff_Call) Return(diffResult *models.DiffResult, err error) *MockService_Diff_Call {
+	_c.Call.Return(diffResult, err)
+	return _c
+}
+
+func (_c *MockService_Diff_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, snapA string, snapB string) (*models.DiffResult, error)) *MockService_Diff_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Forget provides a mock function for the type MockService
 func (_mock *MockService) Forget(ctx context.Context, cfg models.ResticConfig, policy models.RetentionPolicy) (*models.ForgetResult, error) {
 	ret := _mock.Called(ctx, cfg, policy)
@@ -261,20 +568,31 @@ func (_c *MockService_Forget_Call) RunAndReturn(run func(ctx context.Context, cf
 }
 
 // Init provides a mock function for the type MockService
-func (_mock *MockService) Init(ctx context.Context, cfg models.ResticConfig) error {
+func (_mock *MockService) Init(ctx context.Context, cfg models.ResticConfig) (*models.InitResult, error) {
 	ret := _mock.Called(ctx, cfg)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Init")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) error); ok {
+	var r0 *models.InitResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) (*models.InitResult, error)); ok {
+		return returnFunc(ctx, cfg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) *models.InitResult); ok {
 		r0 = returnFunc(ctx, cfg)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.InitResult)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig) error); ok {
+		r1 = returnFunc(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
 // MockService_Init_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Init'
@@ -307,57 +625,58 @@ func (_c *MockService_Init_Call) Run(run func(ctx context.Context, cfg models.Re
 	return _c
 }
 
-func (_c *MockService_Init_Call) Return(err error) *MockService_Init_Call {
-	_c.Call.Return(err)
+func (_c *MockService_Init_Call) Return(initResult *models.InitResult, err error) *MockService_Init_Call {
+	_c.Call.Return(initResult, err)
 	return _c
 }
 
-func (_c *MockService_Init_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) error) *MockService_Init_Call {
+func (_c *MockService_Init_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) (*models.InitResult, error)) *MockService_Init_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Snapshots provides a mock function for the type MockService
-func (_mock *MockService) Snapshots(ctx context.Context, cfg models.ResticConfig) ([]models.Snapshot, error) {
-	ret := _mock.Called(ctx, cfg)
+// LatestSnapshots provides a mock function for the type MockService
+func (_mock *MockService) LatestSnapshots(ctx context.Context, cfg models.ResticConfig, groupBy string) ([]models.SnapshotGroup, error) {
+	ret := _mock.Called(ctx, cfg, groupBy)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Snapshots")
+		panic("no return value specified for LatestSnapshots")
 	}
 
-	var r0 []models.Snapshot
+	var r0 []models.SnapshotGroup
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) ([]models.Snapshot, error)); ok {
-		return returnFunc(ctx, cfg)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, string) ([]models.SnapshotGroup, error)); ok {
+		return returnFunc(ctx, cfg, groupBy)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) []models.Snapshot); ok {
-		r0 = returnFunc(ctx, cfg)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, string) []models.SnapshotGroup); ok {
+		r0 = returnFunc(ctx, cfg, groupBy)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Snapshot)
+			r0 = ret.Get(0).([]models.SnapshotGroup)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig) error); ok {
-		r1 = returnFunc(ctx, cfg)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, string) error); ok {
+		r1 = returnFunc(ctx, cfg, groupBy)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockService_Snapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Snapshots'
-type MockService_Snapshots_Call struct {
+// MockService_LatestSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LatestSnapshots'
+type MockService_LatestSnapshots_Call struct {
 	*mock.Call
 }
 
-// Snapshots is a helper method to define mock.On call
+// LatestSnapshots is a helper method to define mock.On call
 //   - ctx context.Context
 //   - cfg models.ResticConfig
-func (_e *MockService_Expecter) Snapshots(ctx interface{}, cfg interface{}) *MockService_Snapshots_Call {
-	return &MockService_Snapshots_Call{Call: _e.mock.On("Snapshots", ctx, cfg)}
+//   - groupBy string
+func (_e *MockService_Expecter) LatestSnapshots(ctx interface{}, cfg interface{}, groupBy interface{}) *MockService_LatestSnapshots_Call {
+	return &MockService_LatestSnapshots_Call{Call: _e.mock.On("LatestSnapshots", ctx, cfg, groupBy)}
 }
 
-func (_c *MockService_Snapshots_Call) Run(run func(ctx context.Context, cfg models.ResticConfig)) *MockService_Snapshots_Call {
+func (_c *MockService_LatestSnapshots_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, groupBy string)) *MockService_LatestSnapshots_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -367,42 +686,473 @@ func (_c *MockService_Snapshots_Call) Run(run func(ctx context.Context, cfg mode
 		if args[1] != nil {
 			arg1 = args[1].(models.ResticConfig)
 		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockService_Snapshots_Call) Return(snapshots []models.Snapshot, err error) *MockService_Snapshots_Call {
-	_c.Call.Return(snapshots, err)
+func (_c *MockService_LatestSnapshots_Call) Return(snapshotGroups []models.SnapshotGroup, err error) *MockService_LatestSnapshots_Call {
+	_c.Call.Return(snapshotGroups, err)
 	return _c
 }
 
-func (_c *MockService_Snapshots_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) ([]models.Snapshot, error)) *MockService_Snapshots_Call {
+func (_c *MockService_LatestSnapshots_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, groupBy string) ([]models.SnapshotGroup, error)) *MockService_LatestSnapshots_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Unlock provides a mock function for the type MockService
-func (_mock *MockService) Unlock(ctx context.Context, cfg models.ResticConfig) error {
-	ret := _mock.Called(ctx, cfg)
+// Prune provides a mock function for the type MockService
+func (_mock *MockService) Prune(ctx context.Context, cfg models.ResticConfig, settings models.PruneSettings) (*models.PruneResult, error) {
+	ret := _mock.Called(ctx, cfg, settings)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Unlock")
+		panic("no return value specified for Prune")
 	}
 
-	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) error); ok {
-		r0 = returnFunc(ctx, cfg)
+	var r0 *models.PruneResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.PruneSettings) (*models.PruneResult, error)); ok {
+		return returnFunc(ctx, cfg, settings)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.PruneSettings) *models.PruneResult); ok {
+		r0 = returnFunc(ctx, cfg, settings)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.PruneResult)
+		}
 	}
-	return r0
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.PruneSettings) error); ok {
+		r1 = returnFunc(ctx, cfg, settings)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockService_Unlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unlock'
+// MockService_Prune_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Prune'
+type MockService_Prune_Call struct {
+	*mock.Call
+}
+
+// Prune is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+//   - settings models.PruneSettings
+func (_e *MockService_Expecter) Prune(ctx interface{}, cfg interface{}, settings interface{}) *MockService_Prune_Call {
+	return &MockService_Prune_Call{Call: _e.mock.On("Prune", ctx, cfg, settings)}
+}
+
+func (_c *MockService_Prune_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, settings models.PruneSettings)) *MockService_Prune_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		var arg2 models.PruneSettings
+		if args[2] != nil {
+			arg2 = args[2].(models.PruneSettings)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Prune_Call) Return(pruneResult *models.PruneResult, err error) *MockService_Prune_Call {
+	_c.Call.Return(pruneResult, err)
+	return _c
+}
+
+func (_c *MockService_Prune_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, settings models.PruneSettings) (*models.PruneResult, error)) *MockService_Prune_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepairIndex provides a mock function for the type MockService
+func (_mock *MockService) RepairIndex(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error) {
+	ret := _mock.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepairIndex")
+	}
+
+	var r0 *models.RepairResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) (*models.RepairResult, error)); ok {
+		return returnFunc(ctx, cfg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) *models.RepairResult); ok {
+		r0 = returnFunc(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RepairResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig) error); ok {
+		r1 = returnFunc(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RepairIndex_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepairIndex'
+type MockService_RepairIndex_Call struct {
+	*mock.Call
+}
+
+// RepairIndex is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+func (_e *MockService_Expecter) RepairIndex(ctx interface{}, cfg interface{}) *MockService_RepairIndex_Call {
+	return &MockService_RepairIndex_Call{Call: _e.mock.On("RepairIndex", ctx, cfg)}
+}
+
+func (_c *MockService_RepairIndex_Call) Run(run func(ctx context.Context, cfg models.ResticConfig)) *MockService_RepairIndex_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RepairIndex_Call) Return(repairResult *models.RepairResult, err error) *MockService_RepairIndex_Call {
+	_c.Call.Return(repairResult, err)
+	return _c
+}
+
+func (_c *MockService_RepairIndex_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error)) *MockService_RepairIndex_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RepairSnapshots provides a mock function for the type MockService
+func (_mock *MockService) RepairSnapshots(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error) {
+	ret := _mock.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RepairSnapshots")
+	}
+
+	var r0 *models.RepairResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) (*models.RepairResult, error)); ok {
+		return returnFunc(ctx, cfg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) *models.RepairResult); ok {
+		r0 = returnFunc(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.RepairResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig) error); ok {
+		r1 = returnFunc(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_RepairSnapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RepairSnapshots'
+type MockService_RepairSnapshots_Call struct {
+	*mock.Call
+}
+
+// RepairSnapshots is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+func (_e *MockService_Expecter) RepairSnapshots(ctx interface{}, cfg interface{}) *MockService_RepairSnapshots_Call {
+	return &MockService_RepairSnapshots_Call{Call: _e.mock.On("RepairSnapshots", ctx, cfg)}
+}
+
+func (_c *MockService_RepairSnapshots_Call) Run(run func(ctx context.Context, cfg models.ResticConfig)) *MockService_RepairSnapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_RepairSnapshots_Call) Return(repairResult *models.RepairResult, err error) *MockService_RepairSnapshots_Call {
+	_c.Call.Return(repairResult, err)
+	return _c
+}
+
+func (_c *MockService_RepairSnapshots_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error)) *MockService_RepairSnapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Snapshots provides a mock function for the type MockService
+func (_mock *MockService) Snapshots(ctx context.Context, cfg models.ResticConfig, filter models.SnapshotFilter) ([]models.Snapshot, error) {
+	ret := _mock.Called(ctx, cfg, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Snapshots")
+	}
+
+	var r0 []models.Snapshot
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.SnapshotFilter) ([]models.Snapshot, error)); ok {
+		return returnFunc(ctx, cfg, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.SnapshotFilter) []models.Snapshot); ok {
+		r0 = returnFunc(ctx, cfg, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Snapshot)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.SnapshotFilter) error); ok {
+		r1 = returnFunc(ctx, cfg, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Snapshots_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Snapshots'
+type MockService_Snapshots_Call struct {
+	*mock.Call
+}
+
+// Snapshots is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+//   - filter models.SnapshotFilter
+func (_e *MockService_Expecter) Snapshots(ctx interface{}, cfg interface{}, filter interface{}) *MockService_Snapshots_Call {
+	return &MockService_Snapshots_Call{Call: _e.mock.On("Snapshots", ctx, cfg, filter)}
+}
+
+func (_c *MockService_Snapshots_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, filter models.SnapshotFilter)) *MockService_Snapshots_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		var arg2 models.SnapshotFilter
+		if args[2] != nil {
+			arg2 = args[2].(models.SnapshotFilter)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Snapshots_Call) Return(snapshots []models.Snapshot, err error) *MockService_Snapshots_Call {
+	_c.Call.Return(snapshots, err)
+	return _c
+}
+
+func (_c *MockService_Snapshots_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, filter models.SnapshotFilter) ([]models.Snapshot, error)) *MockService_Snapshots_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stats provides a mock function for the type MockService
+func (_mock *MockService) Stats(ctx context.Context, cfg models.ResticConfig, mode models.StatsMode) (*models.StatsResult, error) {
+	ret := _mock.Called(ctx, cfg, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stats")
+	}
+
+	var r0 *models.StatsResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.StatsMode) (*models.StatsResult, error)); ok {
+		return returnFunc(ctx, cfg, mode)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig, models.StatsMode) *models.StatsResult); ok {
+		r0 = returnFunc(ctx, cfg, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.StatsResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig, models.StatsMode) error); ok {
+		r1 = returnFunc(ctx, cfg, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Stats_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stats'
+type MockService_Stats_Call struct {
+	*mock.Call
+}
+
+// Stats is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+//   - mode models.StatsMode
+func (_e *MockService_Expecter) Stats(ctx interface{}, cfg interface{}, mode interface{}) *MockService_Stats_Call {
+	return &MockService_Stats_Call{Call: _e.mock.On("Stats", ctx, cfg, mode)}
+}
+
+func (_c *MockService_Stats_Call) Run(run func(ctx context.Context, cfg models.ResticConfig, mode models.StatsMode)) *MockService_Stats_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		var arg2 models.StatsMode
+		if args[2] != nil {
+			arg2 = args[2].(models.StatsMode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_Stats_Call) Return(statsResult *models.StatsResult, err error) *MockService_Stats_Call {
+	_c.Call.Return(statsResult, err)
+	return _c
+}
+
+func (_c *MockService_Stats_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig, mode models.StatsMode) (*models.StatsResult, error)) *MockService_Stats_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TestConnection provides a mock function for the type MockService
+func (_mock *MockService) TestConnection(ctx context.Context, cfg models.ResticConfig) error {
+	ret := _mock.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TestConnection")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) error); ok {
+		r0 = returnFunc(ctx, cfg)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockService_TestConnection_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TestConnection'
+type MockService_TestConnection_Call struct {
+	*mock.Call
+}
+
+// TestConnection is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.ResticConfig
+func (_e *MockService_Expecter) TestConnection(ctx interface{}, cfg interface{}) *MockService_TestConnection_Call {
+	return &MockService_TestConnection_Call{Call: _e.mock.On("TestConnection", ctx, cfg)}
+}
+
+func (_c *MockService_TestConnection_Call) Run(run func(ctx context.Context, cfg models.ResticConfig)) *MockService_TestConnection_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.ResticConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.ResticConfig)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_TestConnection_Call) Return(err error) *MockService_TestConnection_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockService_TestConnection_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) error) *MockService_TestConnection_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Unlock provides a mock function for the type MockService
+func (_mock *MockService) Unlock(ctx context.Context, cfg models.ResticConfig) (*models.UnlockResult, error) {
+	ret := _mock.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 *models.UnlockResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) (*models.UnlockResult, error)); ok {
+		return returnFunc(ctx, cfg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.ResticConfig) *models.UnlockResult); ok {
+		r0 = returnFunc(ctx, cfg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.UnlockResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.ResticConfig) error); ok {
+		r1 = returnFunc(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_Unlock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Unlock'
 type MockService_Unlock_Call struct {
 	*mock.Call
 }
@@ -432,12 +1182,12 @@ func (_c *MockService_Unlock_Call) Run(run func(ctx context.Context, cfg models.
 	return _c
 }
 
-func (_c *MockService_Unlock_Call) Return(err error) *MockService_Unlock_Call {
-	_c.Call.Return(err)
+func (_c *MockService_Unlock_Call) Return(unlockResult *models.UnlockResult, err error) *MockService_Unlock_Call {
+	_c.Call.Return(unlockResult, err)
 	return _c
 }
 
-func (_c *MockService_Unlock_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) error) *MockService_Unlock_Call {
+func (_c *MockService_Unlock_Call) RunAndReturn(run func(ctx context.Context, cfg models.ResticConfig) (*models.UnlockResult, error)) *MockService_Unlock_Call {
 	_c.Call.Return(run)
 	return _c
 }
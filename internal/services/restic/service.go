@@ -6,9 +6,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net/url"
 	"os"
 	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,12 +25,22 @@ import (
 
 // Service defines the interface for restic operations.
 type Service interface {
-	Init(ctx context.Context, cfg models.ResticConfig) error
-	Unlock(ctx context.Context, cfg models.ResticConfig) error
-	Snapshots(ctx context.Context, cfg models.ResticConfig) ([]models.Snapshot, error)
-	Backup(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings) (*models.BackupResult, error)
+	CheckBinary(ctx context.Context) (string, error)
+	TestConnection(ctx context.Context, cfg models.ResticConfig) error
+	Init(ctx context.Context, cfg models.ResticConfig) (*models.InitResult, error)
+	Unlock(ctx context.Context, cfg models.ResticConfig) (*models.UnlockResult, error)
+	Snapshots(ctx context.Context, cfg models.ResticConfig, filter models.SnapshotFilter) ([]models.Snapshot, error)
+	LatestSnapshots(ctx context.Context, cfg models.ResticConfig, groupBy string) ([]models.SnapshotGroup, error)
+	Backup(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, progressCb models.ResticProgressCallback) (*models.BackupResult, error)
+	BackupStdin(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader) (*models.BackupResult, error)
 	Forget(ctx context.Context, cfg models.ResticConfig, policy models.RetentionPolicy) (*models.ForgetResult, error)
+	Prune(ctx context.Context, cfg models.ResticConfig, settings models.PruneSettings) (*models.PruneResult, error)
+	Copy(ctx context.Context, srcCfg, dstCfg models.ResticConfig, opts models.CopyOptions) (*models.CopyResult, error)
 	Check(ctx context.Context, cfg models.ResticConfig, settings models.CheckSettings) (*models.CheckResult, error)
+	RepairIndex(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error)
+	RepairSnapshots(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error)
+	Stats(ctx context.Context, cfg models.ResticConfig, mode models.StatsMode) (*models.StatsResult, error)
+	Diff(ctx context.Context, cfg models.ResticConfig, snapA, snapB string) (*models.DiffResult, error)
 }
 
 // CommandExecutor allows mocking exec.Command in tests.
@@ -31,6 +48,7 @@ type CommandExecutor interface {
 	Execute(ctx context.Context, name string, args ...string) ([]byte, error)
 	ExecuteWithEnv(ctx context.Context, env []string, name string, args ...string) ([]byte, error)
 	ExecuteWithEnvStreaming(ctx context.Context, env []string, progressCb models.ResticProgressCallback, name string, args ...string) ([]byte, error)
+	ExecuteWithStdin(ctx context.Context, env []string, stdin io.Reader, name string, args ...string) ([]byte, error)
 }
 
 // DefaultExecutor is the default command executor using os/exec.
@@ -51,6 +69,7 @@ func (e *DefaultExecutor) ExecuteWithEnv(ctx context.Context, env []string, name
 
 // ExecuteWithEnvStreaming runs a command with environment variables and streams stdout line-by-line.
 // It calls progressCb for each line that contains a status message, and returns all output.
+// If ctx is cancelled, the command is killed and the context error is returned.
 func (e *DefaultExecutor) ExecuteWithEnvStreaming(ctx context.Context, env []string, progressCb models.ResticProgressCallback, name string, args ...string) ([]byte, error) {
 	cmd := exec.CommandContext(ctx, name, args...)
 	cmd.Env = append(os.Environ(), env...)
@@ -71,6 +90,10 @@ func (e *DefaultExecutor) ExecuteWithEnvStreaming(ctx context.Context, env []str
 	var output bytes.Buffer
 	scanner := bufio.NewScanner(stdout)
 	for scanner.Scan() {
+		if ctx.Err() != nil {
+			break
+		}
+
 		line := scanner.Bytes()
 		output.Write(line)
 		output.WriteByte('\n')
@@ -86,6 +109,13 @@ func (e *DefaultExecutor) ExecuteWithEnvStreaming(ctx context.Context, env []str
 		}
 	}
 
+	// Stop reading and terminate restic instead of waiting for it to exit on its own
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return output.Bytes(), ctxErr
+	}
+
 	// Wait for command to complete
 	err = cmd.Wait()
 
@@ -97,6 +127,50 @@ func (e *DefaultExecutor) ExecuteWithEnvStreaming(ctx context.Context, env []str
 	return output.Bytes(), err
 }
 
+// ExecuteWithStdin runs a command with environment variables, feeding stdin to
+// its standard input, and returns the combined output. If reading stdin fails,
+// e.g. because the producing dump failed, the command is killed before its
+// input is closed, so it never sees a clean end of input.
+func (e *DefaultExecutor) ExecuteWithStdin(ctx context.Context, env []string, stdin io.Reader, name string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = append(os.Environ(), env...)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	pipe, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start command: %w", err)
+	}
+
+	copyErr := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(pipe, stdin)
+		if err != nil {
+			_ = cmd.Process.Kill()
+		}
+		_ = pipe.Close()
+		copyErr <- err
+	}()
+
+	err = cmd.Wait()
+	if err != nil {
+		select {
+		case readErr := <-copyErr:
+			if readErr != nil {
+				err = fmt.Errorf("%w: reading stdin: %w", err, readErr)
+			}
+		default:
+		}
+	}
+	return output.Bytes(), err
+}
+
 // formatKBytes formats bytes as kilobytes with thousand separators.
 func formatKBytes(bytes uint64) string {
 	kb := bytes / 1024
@@ -158,73 +232,232 @@ func (s *Impl) buildEnv(cfg models.ResticConfig) []string {
 	if cfg.RestPassword != "" {
 		env = append(env, fmt.Sprintf("RESTIC_REST_PASSWORD=%s", cfg.RestPassword))
 	}
+	if cfg.CacheDir != "" {
+		env = append(env, fmt.Sprintf("RESTIC_CACHE_DIR=%s", cfg.CacheDir))
+	}
+	if isRclone(cfg) && cfg.RcloneConfigPath != "" {
+		env = append(env, fmt.Sprintf("RCLONE_CONFIG=%s", cfg.RcloneConfigPath))
+	}
+
+	// Sort keys so the environment is deterministic
+	keys := make([]string, 0, len(cfg.EnvVars))
+	for key := range cfg.EnvVars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		env = append(env, fmt.Sprintf("%s=%s", key, cfg.EnvVars[key]))
+	}
 
 	return env
 }
 
+// globalArgs prepends global restic flags to the subcommand and its arguments.
+func globalArgs(cfg models.ResticConfig, args ...string) []string {
+	var global []string
+	if cfg.NoCache {
+		global = append(global, "--no-cache")
+	}
+	if cfg.InsecureTLS {
+		global = append(global, "--insecure-tls")
+	}
+	if cfg.CACertPath != "" {
+		global = append(global, "--cacert", cfg.CACertPath)
+	}
+	if isRclone(cfg) && cfg.RcloneArgs != "" {
+		global = append(global, "-o", "rclone.args="+cfg.RcloneArgs)
+	}
+	if len(global) == 0 {
+		return args
+	}
+	return append(global, args...)
+}
+
+// isRclone reports whether the repository uses the rclone backend.
+func isRclone(cfg models.ResticConfig) bool {
+	return strings.HasPrefix(cfg.Repository, "rclone:")
+}
+
+// readOnlyArgs is globalArgs for commands that only read the repository.
+// With cfg.NoLock they run without creating a repository lock.
+func readOnlyArgs(cfg models.ResticConfig, args ...string) []string {
+	if cfg.NoLock {
+		args = append([]string{"--no-lock"}, args...)
+	}
+	return globalArgs(cfg, args...)
+}
+
+// MinVersion is the oldest restic release supporting every command used here
+// (repair snapshots was added in 0.16.0).
+const MinVersion = "0.16.0"
+
+var versionPattern = regexp.MustCompile(`restic (\d+)\.(\d+)\.(\d+)`)
+
+// CheckBinary runs restic version and returns the installed version.
+// It fails if restic is not on PATH or older than MinVersion.
+func (s *Impl) CheckBinary(ctx context.Context) (string, error) {
+	output, err := s.executor.Execute(ctx, "restic", "version")
+	if err != nil {
+		return "", fmt.Errorf("restic not found or too old (need >= %s): %w", MinVersion, err)
+	}
+
+	version, err := parseVersion(string(output))
+	if err != nil {
+		return "", fmt.Errorf("restic not found or too old (need >= %s): %w", MinVersion, err)
+	}
+	if compareVersions(version, MinVersion) < 0 {
+		return version, fmt.Errorf("restic not found or too old (need >= %s): found %s", MinVersion, version)
+	}
+
+	s.logger.Debug().Str("version", version).Msg("restic binary found")
+
+	return version, nil
+}
+
+// parseVersion extracts the semantic version from restic version output,
+// e.g. "restic 0.16.4 compiled with go1.21.6 on linux/amd64".
+func parseVersion(output string) (string, error) {
+	m := versionPattern.FindStringSubmatch(output)
+	if m == nil {
+		return "", fmt.Errorf("unrecognized version output: %q", strings.TrimSpace(output))
+	}
+	return m[1] + "." + m[2] + "." + m[3], nil
+}
+
+// compareVersions compares two major.minor.patch versions and returns
+// -1, 0 or 1. Both versions must be well-formed.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := range as {
+		x, _ := strconv.Atoi(as[i])
+		y, _ := strconv.Atoi(bs[i])
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// TestConnection checks that the repository is reachable and the password is
+// correct by reading its config. It does not modify the repository. The
+// returned *Error tells a wrong password apart from an unreachable repository.
+func (s *Impl) TestConnection(ctx context.Context, cfg models.ResticConfig) error {
+	s.logger.Debug().Str("repository", cfg.Repository).Msg("testing repository connection")
+
+	env := s.buildEnv(cfg)
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "cat", "config")...)
+	if err == nil {
+		return nil
+	}
+
+	failure := classify(output, err)
+	switch failure.Kind {
+	case models.ErrorKindWrongPassword:
+		failure.Err = fmt.Errorf("wrong repository password: %w", err)
+	case models.ErrorKindNotInitialized:
+		failure.Err = fmt.Errorf("repository does not exist at %s: %w", cfg.Repository, err)
+	default:
+		failure.Err = fmt.Errorf("repository unreachable: %w, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return failure
+}
+
 // Init initializes a restic repository if it doesn't exist.
-func (s *Impl) Init(ctx context.Context, cfg models.ResticConfig) error {
+// The result reports whether a new repository was created.
+func (s *Impl) Init(ctx context.Context, cfg models.ResticConfig) (*models.InitResult, error) {
 	s.logger.Info().Str("repository", cfg.Repository).Msg("checking if repository needs initialization")
 
 	env := s.buildEnv(cfg)
 
 	// Check if repository already exists by running snapshots
-	_, err := s.executor.ExecuteWithEnv(ctx, env, "restic", "snapshots", "--json")
+	_, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "snapshots", "--json")...)
 	if err == nil {
 		s.logger.Info().Msg("repository already initialized")
-		return nil
+		return &models.InitResult{Created: false}, nil
 	}
 
 	// Initialize repository
 	s.logger.Info().Msg("initializing repository")
-	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", "init")
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "init")...)
 	if err != nil {
-		return fmt.Errorf("failed to initialize repository: %w, output: %s", err, string(output))
+		return nil, fmt.Errorf("failed to initialize repository: %w, output: %s", err, string(output))
 	}
 
 	s.logger.Info().Msg("repository initialized successfully")
-	return nil
+	return &models.InitResult{Created: true}, nil
 }
 
 // Unlock checks for and handles stale locks in the repository.
 // If cfg.FailOnLocked is true (default), it returns an error when locks exist.
 // If cfg.FailOnLocked is false, it removes the locks and continues.
-func (s *Impl) Unlock(ctx context.Context, cfg models.ResticConfig) error {
+// The result reports how many locks were removed.
+func (s *Impl) Unlock(ctx context.Context, cfg models.ResticConfig) (*models.UnlockResult, error) {
 	s.logger.Debug().Msg("checking for stale locks")
 
 	env := s.buildEnv(cfg)
 
 	// List existing locks
-	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", "list", "locks")
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "list", "locks", "--json")...)
 	if err != nil {
-		return fmt.Errorf("failed to list locks: %w, output: %s", err, string(output))
+		return nil, fmt.Errorf("failed to list locks: %w, output: %s", err, string(output))
 	}
 
+	lockCount := countLocks(output)
+
 	// If no locks, nothing to do
-	if len(bytes.TrimSpace(output)) == 0 {
+	if lockCount == 0 {
 		s.logger.Debug().Msg("no locks found")
-		return nil
+		return &models.UnlockResult{}, nil
 	}
 
-	// Count locks (one ID per line)
-	lockCount := len(bytes.Split(bytes.TrimSpace(output), []byte("\n")))
-
 	// If fail_on_locked is true, return an error instead of removing locks
 	if cfg.FailOnLocked {
 		s.logger.Error().Int("lock_count", lockCount).Msg("repository is locked")
-		return fmt.Errorf("repository has %d stale lock(s); set fail_on_locked: false to auto-remove", lockCount)
+		return nil, fmt.Errorf("repository has %d stale lock(s); set fail_on_locked: false to auto-remove", lockCount)
 	}
 
-	s.logger.Warn().Int("lock_count", lockCount).Msg("found stale locks, removing")
+	s.logger.Warn().Int("lock_count", lockCount).Msg("found locks, removing stale ones")
 
 	// Run unlock to remove stale locks
-	output, err = s.executor.ExecuteWithEnv(ctx, env, "restic", "unlock")
+	output, err = s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "unlock")...)
 	if err != nil {
-		return fmt.Errorf("failed to unlock repository: %w, output: %s", err, string(output))
+		return nil, fmt.Errorf("failed to unlock repository: %w, output: %s", err, string(output))
 	}
 
-	s.logger.Info().Msg("stale locks removed successfully")
-	return nil
+	// restic unlock keeps locks of running operations, count what is left
+	output, err = s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "list", "locks", "--json")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list locks: %w, output: %s", err, string(output))
+	}
+	removed := max(lockCount-countLocks(output), 0)
+
+	s.logger.Info().Int("lock_count", removed).Msg("stale locks removed successfully")
+	return &models.UnlockResult{LocksRemoved: removed}, nil
+}
+
+// countLocks counts the lock IDs printed by restic list locks.
+// Depending on the restic version the IDs are a JSON array or one ID per line.
+func countLocks(output []byte) int {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return 0
+	}
+
+	var ids []string
+	if err := json.Unmarshal(output, &ids); err == nil {
+		return len(ids)
+	}
+
+	count := 0
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) > 0 {
+			count++
+		}
+	}
+	return count
 }
 
 // snapshotJSON is the JSON structure returned by restic snapshots --json.
@@ -236,12 +469,21 @@ type snapshotJSON struct {
 	Paths    []string  `json:"paths"`
 }
 
-// Snapshots returns a list of snapshots in the repository.
-func (s *Impl) Snapshots(ctx context.Context, cfg models.ResticConfig) ([]models.Snapshot, error) {
-	s.logger.Debug().Msg("listing snapshots")
+// Snapshots returns a list of snapshots in the repository, optionally filtered.
+func (s *Impl) Snapshots(ctx context.Context, cfg models.ResticConfig, filter models.SnapshotFilter) ([]models.Snapshot, error) {
+	s.logger.Debug().Strs("tags", filter.Tags).Str("host", filter.Host).Msg("listing snapshots")
 
 	env := s.buildEnv(cfg)
-	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", "snapshots", "--json")
+
+	args := []string{"snapshots", "--json"}
+	for _, tag := range filter.Tags {
+		args = append(args, "--tag", tag)
+	}
+	if filter.Host != "" {
+		args = append(args, "--host", filter.Host)
+	}
+
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", readOnlyArgs(cfg, args...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list snapshots: %w, output: %s", err, string(output))
 	}
@@ -253,19 +495,79 @@ func (s *Impl) Snapshots(ctx context.Context, cfg models.ResticConfig) ([]models
 
 	result := make([]models.Snapshot, len(snapshots))
 	for i, snap := range snapshots {
-		result[i] = models.Snapshot{
-			ID:       snap.ID,
-			Time:     snap.Time,
-			Hostname: snap.Hostname,
-			Tags:     snap.Tags,
-			Paths:    snap.Paths,
-		}
+		result[i] = snap.toModel()
 	}
 
 	s.logger.Debug().Int("count", len(result)).Msg("snapshots listed")
 	return result, nil
 }
 
+func (snap snapshotJSON) toModel() models.Snapshot {
+	return models.Snapshot{
+		ID:       snap.ID,
+		Time:     snap.Time,
+		Hostname: snap.Hostname,
+		Tags:     snap.Tags,
+		Paths:    snap.Paths,
+	}
+}
+
+// DefaultSnapshotGroupBy groups snapshots like restic does for --latest.
+const DefaultSnapshotGroupBy = "host,paths"
+
+// snapshotGroupJSON is one group returned by restic snapshots --group-by --json.
+type snapshotGroupJSON struct {
+	GroupKey struct {
+		Hostname string   `json:"hostname"`
+		Paths    []string `json:"paths"`
+		Tags     []string `json:"tags"`
+	} `json:"group_key"`
+	Snapshots []snapshotJSON `json:"snapshots"`
+}
+
+// LatestSnapshots returns the latest snapshot of each group. groupBy is a
+// comma-separated list of host, paths and tags, DefaultSnapshotGroupBy if empty.
+func (s *Impl) LatestSnapshots(ctx context.Context, cfg models.ResticConfig, groupBy string) ([]models.SnapshotGroup, error) {
+	if groupBy == "" {
+		groupBy = DefaultSnapshotGroupBy
+	}
+	s.logger.Debug().Str("group_by", groupBy).Msg("listing latest snapshots")
+
+	env := s.buildEnv(cfg)
+	args := readOnlyArgs(cfg, "snapshots", "--latest", "1", "--group-by", groupBy, "--json")
+
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w, output: %s", err, string(output))
+	}
+
+	var groups []snapshotGroupJSON
+	if err := json.Unmarshal(output, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot groups: %w", err)
+	}
+
+	result := make([]models.SnapshotGroup, len(groups))
+	for i, group := range groups {
+		snapshots := make([]models.Snapshot, len(group.Snapshots))
+		for j, snap := range group.Snapshots {
+			snapshots[j] = snap.toModel()
+		}
+		result[i] = models.SnapshotGroup{
+			Hostname:  group.GroupKey.Hostname,
+			Paths:     group.GroupKey.Paths,
+			Tags:      group.GroupKey.Tags,
+			Snapshots: snapshots,
+		}
+	}
+
+	s.logger.Debug().Int("groups", len(result)).Msg("latest snapshots listed")
+	return result, nil
+}
+
+// exitCodeIncomplete is restic's exit code for a backup that created a
+// snapshot but could not read all source files.
+const exitCodeIncomplete = 3
+
 // backupSummary is the summary part of restic backup --json output.
 type backupSummary struct {
 	MessageType         string  `json:"message_type"`
@@ -279,14 +581,45 @@ type backupSummary struct {
 	SnapshotID          string  `json:"snapshot_id"`
 }
 
+// backupMessage is an error or warning line of restic backup --json output.
+type backupMessage struct {
+	MessageType string          `json:"message_type"`
+	Message     string          `json:"message"`
+	Error       json.RawMessage `json:"error"`
+	Item        string          `json:"item"`
+}
+
+// text formats the message as "<item>: <message>". The error is an object
+// with a message field, but is accepted as a plain string too.
+func (m backupMessage) text() string {
+	text := m.Message
+	var errObj struct {
+		Message string `json:"message"`
+	}
+	var errStr string
+	switch {
+	case json.Unmarshal(m.Error, &errObj) == nil && errObj.Message != "":
+		text = errObj.Message
+	case json.Unmarshal(m.Error, &errStr) == nil && errStr != "":
+		text = errStr
+	}
+	if m.Item != "" {
+		return m.Item + ": " + text
+	}
+	return text
+}
+
 // Backup performs a backup operation.
-func (s *Impl) Backup(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings) (*models.BackupResult, error) {
-	s.logger.Info().Strs("paths", settings.Paths).Msg("starting backup")
+func (s *Impl) Backup(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, progressCb models.ResticProgressCallback) (*models.BackupResult, error) {
+	s.logger.Info().Strs("paths", settings.Paths).Strs("dump_paths", settings.DumpPaths).Msg("starting backup")
 
 	start := time.Now()
 	env := s.buildEnv(cfg)
 
 	args := []string{"backup", "--json"}
+	if cfg.DryRun {
+		args = append(args, "--dry-run")
+	}
 
 	// Add hostname
 	if settings.Host != "" {
@@ -298,17 +631,57 @@ func (s *Impl) Backup(ctx context.Context, cfg models.ResticConfig, settings mod
 		args = append(args, "--tag", tag)
 	}
 
-	// Add paths
-	args = append(args, settings.Paths...)
+	// Add exclude patterns
+	for _, pattern := range settings.Excludes {
+		args = append(args, "--exclude", pattern)
+	}
+	if settings.ExcludeCaches {
+		args = append(args, "--exclude-caches")
+	}
+	for _, filename := range settings.ExcludeIfPresent {
+		args = append(args, "--exclude-if-present", filename)
+	}
+	if settings.OneFileSystem {
+		args = append(args, "--one-file-system")
+	}
+
+	args = append(args, tuningArgs(cfg)...)
+	if settings.ExcludeFile != "" {
+		args = append(args, "--exclude-file", settings.ExcludeFile)
+	}
+
+	// Add paths, either from a file or positionally
+	if settings.FilesFrom != "" {
+		args = append(args, "--files-from", settings.FilesFrom)
+	} else {
+		args = append(args, settings.Paths...)
+	}
+	args = append(args, settings.DumpPaths...)
 
 	var output []byte
 	var err error
 
-	// Use streaming executor when debug logging is enabled to show progress
-	if s.logger.GetLevel() <= zerolog.DebugLevel {
+	// Use streaming executor when debug logging is enabled to show progress,
+	// or when the caller wants progress updates
+	debug := s.logger.GetLevel() <= zerolog.DebugLevel
+	if debug || progressCb != nil {
 		lastLoggedPercent := -1
 		lastLogTime := time.Time{}
-		progressCb := func(progress models.BackupProgress) {
+		streamCb := func(progress models.BackupProgress) {
+			// Progress after a cancellation is stale, the backup is being aborted
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
+
+			if progressCb != nil {
+				progressCb(progress)
+			}
+			if !debug {
+				return
+			}
+
 			currentPercent := int(progress.PercentDone * 100)
 			now := time.Now()
 
@@ -325,39 +698,110 @@ func (s *Impl) Backup(ctx context.Context, cfg models.ResticConfig, settings mod
 					Msg("backup progress")
 			}
 		}
-		output, err = s.executor.ExecuteWithEnvStreaming(ctx, env, progressCb, "restic", args...)
+		output, err = s.executor.ExecuteWithEnvStreaming(ctx, env, streamCb, "restic", globalArgs(cfg, args...)...)
 	} else {
-		output, err = s.executor.ExecuteWithEnv(ctx, env, "restic", args...)
+		output, err = s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, args...)...)
 	}
 
-	if err != nil {
+	return s.backupResult(ctx, start, output, err), nil
+}
+
+// BackupStdin backs up everything read from stdin as a single file named
+// settings.StdinFilename, e.g. a database dump piped into restic.
+func (s *Impl) BackupStdin(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader) (*models.BackupResult, error) {
+	s.logger.Info().Str("filename", settings.StdinFilename).Msg("starting backup from stdin")
+
+	start := time.Now()
+	env := s.buildEnv(cfg)
+
+	args := []string{"backup", "--json"}
+	if cfg.DryRun {
+		args = append(args, "--dry-run")
+	}
+	if settings.Host != "" {
+		args = append(args, "--host", settings.Host)
+	}
+	for _, tag := range settings.Tags {
+		args = append(args, "--tag", tag)
+	}
+	args = append(args, tuningArgs(cfg)...)
+	args = append(args, "--stdin", "--stdin-filename", settings.StdinFilename)
+
+	output, err := s.executor.ExecuteWithStdin(ctx, env, stdin, "restic", globalArgs(cfg, args...)...)
+
+	return s.backupResult(ctx, start, output, err), nil
+}
+
+// tuningArgs returns the backup performance flags; zero values keep the restic defaults.
+func tuningArgs(cfg models.ResticConfig) []string {
+	var args []string
+	if cfg.PackSize > 0 {
+		args = append(args, "--pack-size", strconv.Itoa(cfg.PackSize))
+	}
+	if cfg.ReadConcurrency > 0 {
+		args = append(args, "--read-concurrency", strconv.Itoa(cfg.ReadConcurrency))
+	}
+	if cfg.CompressionLevel != "" {
+		args = append(args, "--compression", cfg.CompressionLevel)
+	}
+	return args
+}
+
+// backupResult builds the result of a restic backup from its JSON output and exit error.
+func (s *Impl) backupResult(ctx context.Context, start time.Time, output []byte, err error) *models.BackupResult {
+	// A cancelled backup never counts as a snapshot, even if restic printed a summary
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		failure := classify(nil, fmt.Errorf("backup cancelled: %w", ctxErr))
 		return &models.BackupResult{
-			Duration: time.Since(start),
-			Error:    fmt.Errorf("backup failed: %w, output: %s", err, string(output)),
-		}, nil
+			Duration:  time.Since(start),
+			Error:     failure,
+			ErrorKind: failure.Kind,
+		}
 	}
 
-	// Parse the JSON output to find the summary line
+	// Parse the JSON output for the summary line and any error/warning messages
 	var summary backupSummary
+	var warnings, errorMessages []string
 	lines := bytes.Split(output, []byte("\n"))
 	for _, line := range lines {
 		if len(line) == 0 {
 			continue
 		}
-		var msg struct {
-			MessageType string `json:"message_type"`
-		}
+		var msg backupMessage
 		if err := json.Unmarshal(line, &msg); err != nil {
 			continue
 		}
-		if msg.MessageType == "summary" {
+		switch msg.MessageType {
+		case "summary":
 			if err := json.Unmarshal(line, &summary); err != nil {
 				s.logger.Warn().Err(err).Msg("failed to parse backup summary")
 			}
-			break
+		case "error":
+			errorMessages = append(errorMessages, msg.text())
+		case "warning":
+			warnings = append(warnings, msg.text())
 		}
 	}
 
+	// Exit code 3 means some source files could not be read, but the
+	// snapshot was still created; only treat it as a failure without one
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != exitCodeIncomplete || summary.SnapshotID == "" {
+			failure := classify(output, fmt.Errorf("backup failed: %w, output: %s", err, string(output)))
+			return &models.BackupResult{
+				Duration:  time.Since(start),
+				Error:     failure,
+				ErrorKind: failure.Kind,
+			}
+		}
+		warnings = append(warnings, backupWarnings(lines)...)
+		s.logger.Warn().
+			Str("snapshot_id", summary.SnapshotID).
+			Strs("warnings", warnings).
+			Msg("backup incomplete: some source files could not be read")
+	}
+
 	result := &models.BackupResult{
 		SnapshotID:          summary.SnapshotID,
 		FilesNew:            summary.FilesNew,
@@ -367,6 +811,8 @@ func (s *Impl) Backup(ctx context.Context, cfg models.ResticConfig, settings mod
 		TotalFilesProcessed: summary.TotalFilesProcessed,
 		TotalBytesProcessed: summary.TotalBytesProcessed,
 		Duration:            time.Since(start),
+		Warnings:            warnings,
+		Errors:              errorMessages,
 	}
 
 	s.logger.Info().
@@ -374,10 +820,25 @@ func (s *Impl) Backup(ctx context.Context, cfg models.ResticConfig, settings mod
 		Int("files_new", result.FilesNew).
 		Int("files_changed", result.FilesChanged).
 		Int64("data_added", result.DataAdded).
+		Int("errors", len(result.Errors)).
 		Str("duration", result.Duration.Round(time.Millisecond).String()).
 		Msg("backup completed")
 
-	return result, nil
+	return result
+}
+
+// backupWarnings returns the non-JSON lines of restic backup output, i.e. the
+// error messages restic prints for files it could not read.
+func backupWarnings(lines [][]byte) []string {
+	var warnings []string
+	for _, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || json.Valid(line) {
+			continue
+		}
+		warnings = append(warnings, string(line))
+	}
+	return warnings
 }
 
 // forgetGroup is the JSON structure returned by restic forget --json.
@@ -412,16 +873,32 @@ func extractJSONArray(output []byte) []byte {
 // Forget removes old snapshots according to the retention policy.
 func (s *Impl) Forget(ctx context.Context, cfg models.ResticConfig, policy models.RetentionPolicy) (*models.ForgetResult, error) {
 	s.logger.Info().
+		Int("keep_last", policy.KeepLast).
+		Int("keep_hourly", policy.KeepHourly).
 		Int("keep_daily", policy.KeepDaily).
 		Int("keep_weekly", policy.KeepWeekly).
 		Int("keep_monthly", policy.KeepMonthly).
+		Int("keep_yearly", policy.KeepYearly).
+		Str("keep_within", policy.KeepWithin).
+		Strs("keep_tags", policy.KeepTags).
+		Strs("tags", policy.Tags).
+		Str("group_by", policy.GroupBy).
 		Msg("applying retention policy")
 
 	start := time.Now()
 	env := s.buildEnv(cfg)
 
-	args := []string{"forget", "--prune", "--json"}
+	args := []string{"forget", "--json"}
+	if cfg.DryRun {
+		args = append(args, "--dry-run")
+	}
 
+	if policy.KeepLast > 0 {
+		args = append(args, "--keep-last", fmt.Sprintf("%d", policy.KeepLast))
+	}
+	if policy.KeepHourly > 0 {
+		args = append(args, "--keep-hourly", fmt.Sprintf("%d", policy.KeepHourly))
+	}
 	if policy.KeepDaily > 0 {
 		args = append(args, "--keep-daily", fmt.Sprintf("%d", policy.KeepDaily))
 	}
@@ -431,12 +908,31 @@ func (s *Impl) Forget(ctx context.Context, cfg models.ResticConfig, policy model
 	if policy.KeepMonthly > 0 {
 		args = append(args, "--keep-monthly", fmt.Sprintf("%d", policy.KeepMonthly))
 	}
+	if policy.KeepYearly > 0 {
+		args = append(args, "--keep-yearly", fmt.Sprintf("%d", policy.KeepYearly))
+	}
+	if policy.KeepWithin != "" {
+		args = append(args, "--keep-within", policy.KeepWithin)
+	}
+	for _, tag := range policy.KeepTags {
+		args = append(args, "--keep-tag", tag)
+	}
 
-	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", args...)
+	// Limit forget to matching snapshots and override the grouping
+	for _, tag := range policy.Tags {
+		args = append(args, "--tag", tag)
+	}
+	if policy.GroupBy != "" {
+		args = append(args, "--group-by", strings.ReplaceAll(policy.GroupBy, " ", ""))
+	}
+
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, args...)...)
 	if err != nil {
+		failure := classify(output, fmt.Errorf("forget failed: %w, output: %s", err, string(output)))
 		return &models.ForgetResult{
-			Duration: time.Since(start),
-			Error:    fmt.Errorf("forget failed: %w, output: %s", err, string(output)),
+			Duration:  time.Since(start),
+			Error:     failure,
+			ErrorKind: failure.Kind,
 		}, nil
 	}
 
@@ -466,33 +962,207 @@ func (s *Impl) Forget(ctx context.Context, cfg models.ResticConfig, policy model
 	return result, nil
 }
 
+// Copy copies snapshots from the source repository to the destination repository.
+// The destination is passed as RESTIC_REPOSITORY2 and RESTIC_PASSWORD2 and is
+// initialized first if it does not exist yet.
+func (s *Impl) Copy(ctx context.Context, srcCfg, dstCfg models.ResticConfig, opts models.CopyOptions) (*models.CopyResult, error) {
+	s.logger.Info().Str("destination", dstCfg.Repository).Msg("copying snapshots")
+
+	start := time.Now()
+	env, err := s.pairEnv(srcCfg, dstCfg)
+	if err == nil {
+		err = s.initCopyDestination(ctx, srcCfg, dstCfg)
+	}
+	if err != nil {
+		return &models.CopyResult{Duration: time.Since(start), Error: err}, nil
+	}
+
+	args := []string{"copy"}
+	if opts.Host != "" {
+		args = append(args, "--host", opts.Host)
+	}
+	for _, tag := range opts.Tags {
+		args = append(args, "--tag", tag)
+	}
+
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(srcCfg, args...)...)
+	if err != nil {
+		return &models.CopyResult{
+			Duration: time.Since(start),
+			Error:    fmt.Errorf("copy failed: %w, output: %s", err, string(output)),
+		}, nil
+	}
+
+	result := &models.CopyResult{
+		Duration: time.Since(start),
+	}
+
+	s.logger.Info().Str("duration", result.Duration.Round(time.Millisecond).String()).Msg("snapshots copied")
+
+	return result, nil
+}
+
+// initCopyDestination initializes the copy destination unless it already
+// exists, with the chunker parameters of the source so copied data deduplicates
+// with later backups. Global flags of the source apply to both, as for copy.
+func (s *Impl) initCopyDestination(ctx context.Context, srcCfg, dstCfg models.ResticConfig) error {
+	if _, err := s.executor.ExecuteWithEnv(ctx, s.buildEnv(dstCfg), "restic", globalArgs(srcCfg, "cat", "config")...); err == nil {
+		return nil
+	}
+
+	// For init the new repository is the primary one and --repo2 the source
+	env, err := s.pairEnv(dstCfg, srcCfg)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info().Str("destination", dstCfg.Repository).Msg("initializing copy destination")
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(srcCfg, "init", "--copy-chunker-params")...)
+	if err != nil {
+		return fmt.Errorf("failed to initialize copy destination: %w, output: %s", err, string(output))
+	}
+	return nil
+}
+
+// pairEnv builds the environment of a restic command using primary and the
+// second repository secondary at once. restic shares one environment between
+// both, so REST credentials are moved into each repository URL, which is not
+// shown in the process list, and backend variables set to different values for
+// the two repositories are an error.
+func (s *Impl) pairEnv(primary, secondary models.ResticConfig) ([]string, error) {
+	shared := primary
+	shared.Repository = repositoryURL(primary)
+	shared.RestUser, shared.RestPassword = "", ""
+	shared.EnvVars = maps.Clone(primary.EnvVars)
+	if shared.EnvVars == nil {
+		shared.EnvVars = make(map[string]string, len(secondary.EnvVars))
+	}
+	for key, value := range secondary.EnvVars {
+		if current, ok := shared.EnvVars[key]; ok && current != value {
+			return nil, fmt.Errorf("%s is set differently for both repositories, but restic uses one environment for copy", key)
+		}
+		shared.EnvVars[key] = value
+	}
+
+	return append(s.buildEnv(shared),
+		fmt.Sprintf("RESTIC_REPOSITORY2=%s", repositoryURL(secondary)),
+		fmt.Sprintf("RESTIC_PASSWORD2=%s", secondary.Password),
+	), nil
+}
+
+// repositoryURL returns the repository with the REST credentials of cfg in its
+// URL, unless the URL already carries credentials.
+func repositoryURL(cfg models.ResticConfig) string {
+	if !strings.HasPrefix(cfg.Repository, "rest:") || (cfg.RestUser == "" && cfg.RestPassword == "") {
+		return cfg.Repository
+	}
+	u, err := url.Parse(strings.TrimPrefix(cfg.Repository, "rest:"))
+	if err != nil || u.User != nil {
+		return cfg.Repository
+	}
+	u.User = url.UserPassword(cfg.RestUser, cfg.RestPassword)
+	return "rest:" + u.String()
+}
+
+// Prune removes unreferenced data from the repository.
+func (s *Impl) Prune(ctx context.Context, cfg models.ResticConfig, settings models.PruneSettings) (*models.PruneResult, error) {
+	s.logger.Info().Str("max_unused", settings.MaxUnused).Msg("pruning repository")
+
+	start := time.Now()
+	env := s.buildEnv(cfg)
+
+	args := []string{"prune"}
+	if cfg.DryRun {
+		args = append(args, "--dry-run")
+	}
+	if settings.MaxUnused != "" {
+		args = append(args, "--max-unused", settings.MaxUnused)
+	}
+	if settings.RepackCacheableOnly {
+		args = append(args, "--repack-cacheable-only")
+	}
+
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, args...)...)
+	if err != nil {
+		return &models.PruneResult{
+			Duration: time.Since(start),
+			Error:    fmt.Errorf("prune failed: %w, output: %s", err, string(output)),
+		}, nil
+	}
+
+	result := &models.PruneResult{
+		SpaceFreed: parsePruneOutput(output),
+		Duration:   time.Since(start),
+	}
+
+	s.logger.Info().
+		Int64("space_freed", result.SpaceFreed).
+		Str("duration", result.Duration.Round(time.Millisecond).String()).
+		Msg("repository pruned")
+
+	return result, nil
+}
+
+// prunePattern matches the "total prune" line of restic prune output,
+// e.g. "total prune:         74 blobs / 1.072 MiB".
+var prunePattern = regexp.MustCompile(`total prune:\s+\d+ blobs / ([\d.]+) (B|KiB|MiB|GiB|TiB|PiB)`)
+
+// pruneUnits maps restic's size units to bytes.
+var pruneUnits = map[string]float64{
+	"B":   1,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+	"PiB": 1 << 50,
+}
+
+// parsePruneOutput returns the number of bytes freed by restic prune, or 0 if
+// the output contains no prune summary.
+func parsePruneOutput(output []byte) int64 {
+	match := prunePattern.FindSubmatch(output)
+	if match == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(string(match[1]), 64)
+	if err != nil {
+		return 0
+	}
+	return int64(value * pruneUnits[string(match[2])])
+}
+
 // Check verifies the repository integrity.
 func (s *Impl) Check(ctx context.Context, cfg models.ResticConfig, settings models.CheckSettings) (*models.CheckResult, error) {
 	if !settings.Enabled {
 		return &models.CheckResult{Passed: true}, nil
 	}
 
-	s.logger.Info().Str("subset", settings.Subset).Msg("checking repository")
+	s.logger.Info().Str("subset", settings.Subset).Bool("read_data", settings.ReadData).Msg("checking repository")
 
 	start := time.Now()
 	env := s.buildEnv(cfg)
 
 	args := []string{"check"}
-	if settings.Subset != "" {
+	switch {
+	case settings.ReadData:
+		args = append(args, "--read-data")
+	case settings.Subset != "":
 		args = append(args, "--read-data-subset", settings.Subset)
 	}
 
-	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", args...)
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", readOnlyArgs(cfg, args...)...)
 	duration := time.Since(start)
 
 	if err != nil {
 		// Check if it's just warnings or actual errors
 		outputStr := strings.ToLower(string(output))
 		if strings.Contains(outputStr, "error") {
+			failure := classify(output, fmt.Errorf("check failed: %w, output: %s", err, string(output)))
 			return &models.CheckResult{
-				Passed:   false,
-				Duration: duration,
-				Error:    fmt.Errorf("check failed: %w, output: %s", err, string(output)),
+				Passed:    false,
+				Duration:  duration,
+				Error:     failure,
+				ErrorKind: failure.Kind,
 			}, nil
 		}
 	}
@@ -504,3 +1174,165 @@ func (s *Impl) Check(ctx context.Context, cfg models.ResticConfig, settings mode
 		Duration: duration,
 	}, nil
 }
+
+// RepairIndex rebuilds the repository index from the pack files.
+func (s *Impl) RepairIndex(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error) {
+	return s.repair(ctx, cfg, "index")
+}
+
+// RepairSnapshots rewrites snapshots that reference missing data and removes the originals.
+func (s *Impl) RepairSnapshots(ctx context.Context, cfg models.ResticConfig) (*models.RepairResult, error) {
+	return s.repair(ctx, cfg, "snapshots", "--forget")
+}
+
+// repair runs a restic repair subcommand. Output mentioning an error is treated
+// as a failure even if restic exits successfully.
+func (s *Impl) repair(ctx context.Context, cfg models.ResticConfig, what string, args ...string) (*models.RepairResult, error) {
+	s.logger.Info().Str("target", what).Msg("repairing repository")
+
+	start := time.Now()
+	env := s.buildEnv(cfg)
+
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, append([]string{"repair", what}, args...)...)...)
+	duration := time.Since(start)
+
+	if err != nil || strings.Contains(strings.ToLower(string(output)), "error") {
+		if err == nil {
+			err = fmt.Errorf("errors reported")
+		}
+		return &models.RepairResult{
+			Duration: duration,
+			Error:    fmt.Errorf("repair %s failed: %w, output: %s", what, err, string(output)),
+		}, nil
+	}
+
+	s.logger.Info().Str("target", what).Str("duration", duration.Round(time.Millisecond).String()).Msg("repository repaired")
+
+	return &models.RepairResult{
+		Duration: duration,
+	}, nil
+}
+
+// statsJSON is the JSON structure returned by restic stats --json.
+type statsJSON struct {
+	TotalSize      int64 `json:"total_size"`
+	TotalFileCount int   `json:"total_file_count"`
+	SnapshotsCount int   `json:"snapshots_count"`
+}
+
+// statsArgs builds the restic stats arguments for the given mode.
+func statsArgs(mode models.StatsMode) ([]string, error) {
+	switch mode {
+	case models.StatsModeRestoreSize, models.StatsModeRawData, models.StatsModeFilesByContents:
+		return []string{"stats", "--json", "--mode", string(mode)}, nil
+	default:
+		return nil, fmt.Errorf("invalid stats mode %q: must be restore-size, raw-data or files-by-contents", mode)
+	}
+}
+
+// Stats returns the repository size and file count counted in the given mode.
+func (s *Impl) Stats(ctx context.Context, cfg models.ResticConfig, mode models.StatsMode) (*models.StatsResult, error) {
+	args, err := statsArgs(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug().Str("mode", string(mode)).Msg("collecting repository stats")
+
+	env := s.buildEnv(cfg)
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", readOnlyArgs(cfg, args...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository stats: %w, output: %s", err, string(output))
+	}
+
+	var stats statsJSON
+	if err := json.Unmarshal(output, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse stats: %w", err)
+	}
+
+	result := &models.StatsResult{
+		Mode:           mode,
+		TotalSize:      stats.TotalSize,
+		TotalFileCount: stats.TotalFileCount,
+		SnapshotCount:  stats.SnapshotsCount,
+	}
+
+	s.logger.Debug().
+		Int64("total_size", result.TotalSize).
+		Int("total_file_count", result.TotalFileCount).
+		Int("snapshot_count", result.SnapshotCount).
+		Msg("repository stats collected")
+
+	return result, nil
+}
+
+// diffStat is the per-direction summary in the restic diff --json statistics message.
+type diffStat struct {
+	Files int    `json:"files"`
+	Dirs  int    `json:"dirs"`
+	Bytes uint64 `json:"bytes"`
+}
+
+// diffMessage is a line of restic diff --json output.
+type diffMessage struct {
+	MessageType    string   `json:"message_type"`
+	SourceSnapshot string   `json:"source_snapshot"`
+	TargetSnapshot string   `json:"target_snapshot"`
+	ChangedFiles   int      `json:"changed_files"`
+	Added          diffStat `json:"added"`
+	Removed        diffStat `json:"removed"`
+}
+
+// Diff compares two snapshots and returns the added, removed and changed counts.
+func (s *Impl) Diff(ctx context.Context, cfg models.ResticConfig, snapA, snapB string) (*models.DiffResult, error) {
+	s.logger.Debug().Str("from", snapA).Str("to", snapB).Msg("comparing snapshots")
+
+	env := s.buildEnv(cfg)
+	output, err := s.executor.ExecuteWithEnv(ctx, env, "restic", globalArgs(cfg, "diff", "--json", snapA, snapB)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff snapshots: %w, output: %s", err, string(output))
+	}
+
+	result, err := parseDiffOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Debug().
+		Int("files_added", result.FilesAdded).
+		Int("files_removed", result.FilesRemoved).
+		Int("files_changed", result.FilesChanged).
+		Msg("snapshots compared")
+
+	return result, nil
+}
+
+// parseDiffOutput extracts the statistics message from restic diff --json output.
+// Per-file change messages precede it and are skipped.
+func parseDiffOutput(output []byte) (*models.DiffResult, error) {
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] != '{' {
+			continue
+		}
+
+		var msg diffMessage
+		if err := json.Unmarshal(line, &msg); err != nil || msg.MessageType != "statistics" {
+			continue
+		}
+
+		return &models.DiffResult{
+			SnapshotA:    msg.SourceSnapshot,
+			SnapshotB:    msg.TargetSnapshot,
+			FilesAdded:   msg.Added.Files,
+			FilesRemoved: msg.Removed.Files,
+			FilesChanged: msg.ChangedFiles,
+			DirsAdded:    msg.Added.Dirs,
+			DirsRemoved:  msg.Removed.Dirs,
+			BytesAdded:   msg.Added.Bytes,
+			BytesRemoved: msg.Removed.Bytes,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no statistics found in diff output")
+}
@@ -0,0 +1,41 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+)
+
+// waitForNetwork tries to connect to cfg.Address until it succeeds or the
+// timeout expires, so runs started at boot don't fail on a missing network.
+func (s *Impl) waitForNetwork(ctx context.Context, cfg *models.NetworkConfig) error {
+	s.logger.Info().
+		Str("address", cfg.Address).
+		Str("timeout", cfg.Timeout.String()).
+		Msg("waiting for network")
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	for {
+		conn, err := s.dial(ctx, "tcp", cfg.Address)
+		if err == nil {
+			_ = conn.Close()
+			s.logger.Info().
+				Str("address", cfg.Address).
+				Str("waited", time.Since(start).Round(time.Millisecond).String()).
+				Msg("network is ready")
+			return nil
+		}
+		s.logger.Debug().Err(err).Str("address", cfg.Address).Msg("network not ready yet")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("network not reachable: %s did not accept a connection within %s: %w", cfg.Address, cfg.Timeout, err)
+		case <-time.After(cfg.PollInterval):
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/discord"
+	"github.com/fgeck/gorestic-homelab/internal/services/email"
+	"github.com/fgeck/gorestic-homelab/internal/services/ntfy"
+	"github.com/fgeck/gorestic-homelab/internal/services/pushover"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/fgeck/gorestic-homelab/internal/services/slack"
+	"github.com/fgeck/gorestic-homelab/internal/services/telegram"
+	"github.com/fgeck/gorestic-homelab/internal/services/webhook"
+)
+
+// Notifier sends the result of a run to one notification channel.
+type Notifier interface {
+	Notify(ctx context.Context, msg models.TelegramMessage) error
+}
+
+// notificationServices are the services behind the notifiers.
+type notificationServices struct {
+	telegram telegram.Service
+	pushover pushover.Service
+	webhook  webhook.Service
+	discord  discord.Service
+	slack    slack.Service
+	ntfy     ntfy.Service
+	email    email.Service
+}
+
+// configuredNotifiers returns a notifier for every channel configured in cfg.
+func configuredNotifiers(svcs notificationServices, cfg models.BackupConfig) []Notifier {
+	var notifiers []Notifier
+	if cfg.Telegram != nil {
+		notifiers = append(notifiers, telegramNotifier{svc: svcs.telegram, cfg: *cfg.Telegram})
+	}
+	if cfg.Pushover != nil {
+		notifiers = append(notifiers, pushoverNotifier{svc: svcs.pushover, cfg: *cfg.Pushover})
+	}
+	if cfg.Webhook != nil {
+		notifiers = append(notifiers, webhookNotifier{svc: svcs.webhook, cfg: *cfg.Webhook})
+	}
+	if cfg.Discord != nil {
+		notifiers = append(notifiers, discordNotifier{svc: svcs.discord, cfg: *cfg.Discord})
+	}
+	if cfg.Slack != nil {
+		notifiers = append(notifiers, slackNotifier{svc: svcs.slack, cfg: *cfg.Slack})
+	}
+	if cfg.Ntfy != nil {
+		notifiers = append(notifiers, ntfyNotifier{svc: svcs.ntfy, cfg: *cfg.Ntfy})
+	}
+	if cfg.Email != nil {
+		notifiers = append(notifiers, emailNotifier{svc: svcs.email, cfg: *cfg.Email})
+	}
+	return notifiers
+}
+
+// notify sends msg to every notifier. A failing notifier is logged and does
+// not keep the others from being called.
+func (s *Impl) notify(ctx context.Context, msg models.TelegramMessage) {
+	for _, n := range s.notifiers {
+		if err := n.Notify(ctx, msg); err != nil {
+			s.logger.Error().Err(err).Msg("failed to send notification")
+		}
+	}
+}
+
+// logExcerptLines is how many output lines --attach-log adds to notifications.
+const logExcerptLines = 20
+
+// logExcerpt returns the last n lines of the output of the restic command that
+// caused err, without restic's JSON progress lines. It returns an empty string
+// if err does not carry command output.
+func logExcerpt(err error, n int) string {
+	var resticErr *restic.Error
+	if !errors.As(err, &resticErr) || resticErr.Output == "" {
+		return ""
+	}
+
+	var lines []string
+	for _, line := range strings.Split(resticErr.Output, "\n") {
+		if strings.HasPrefix(line, `{"message_type":"status"`) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+type telegramNotifier struct {
+	svc telegram.Service
+	cfg models.TelegramConfig
+}
+
+func (n telegramNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.SendNotification(ctx, n.cfg, msg)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	return nil
+}
+
+type pushoverNotifier struct {
+	svc pushover.Service
+	cfg models.PushoverConfig
+}
+
+func (n pushoverNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.SendNotification(ctx, n.cfg, models.PushoverMessage{
+		Success:          msg.Success,
+		DryRun:           msg.DryRun,
+		Host:             msg.Host,
+		Repository:       msg.Repository,
+		StartTime:        msg.StartTime,
+		Duration:         msg.Duration,
+		FailedStep:       msg.FailedStep,
+		ErrorMessage:     msg.ErrorMessage,
+		SnapshotID:       msg.SnapshotID,
+		FilesNew:         msg.FilesNew,
+		FilesChanged:     msg.FilesChanged,
+		FilesUnmodified:  msg.FilesUnmodified,
+		DataAdded:        msg.DataAdded,
+		TotalFiles:       msg.TotalFiles,
+		TotalBytes:       msg.TotalBytes,
+		SnapshotsKept:    msg.SnapshotsKept,
+		SnapshotsRemoved: msg.SnapshotsRemoved,
+		RepoTotalSize:    msg.RepoTotalSize,
+		SnapshotCount:    msg.SnapshotCount,
+	})
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("pushover: %w", err)
+	}
+	return nil
+}
+
+type webhookNotifier struct {
+	svc webhook.Service
+	cfg models.WebhookConfig
+}
+
+func (n webhookNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.Notify(ctx, n.cfg, msg)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
+
+type discordNotifier struct {
+	svc discord.Service
+	cfg models.DiscordConfig
+}
+
+func (n discordNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.SendNotification(ctx, n.cfg, msg)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return nil
+}
+
+type slackNotifier struct {
+	svc slack.Service
+	cfg models.SlackConfig
+}
+
+func (n slackNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.SendNotification(ctx, n.cfg, msg)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("slack: %w", err)
+	}
+	return nil
+}
+
+type ntfyNotifier struct {
+	svc ntfy.Service
+	cfg models.NtfyConfig
+}
+
+func (n ntfyNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.Notify(ctx, n.cfg, msg)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("ntfy: %w", err)
+	}
+	return nil
+}
+
+type emailNotifier struct {
+	svc email.Service
+	cfg models.EmailConfig
+}
+
+func (n emailNotifier) Notify(ctx context.Context, msg models.TelegramMessage) error {
+	result, err := n.svc.Send(ctx, n.cfg, msg)
+	if err == nil {
+		err = result.Error
+	}
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+)
+
+// checkBackupPaths verifies that the configured backup paths exist and, if
+// RequireNonEmpty is set, are not empty. This catches mounts that failed to
+// come up before restic backs up an empty directory.
+func checkBackupPaths(settings models.BackupSettings) error {
+	if !settings.RequirePathsExist && !settings.RequireNonEmpty {
+		return nil
+	}
+
+	paths := settings.Paths
+	if settings.FilesFrom != "" {
+		paths = nil
+	}
+	for _, target := range settings.Targets {
+		paths = append(paths, target.Paths...)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("backup path %q does not exist", path)
+			}
+			return fmt.Errorf("backup path %q is not accessible: %w", path, err)
+		}
+		if !settings.RequireNonEmpty {
+			continue
+		}
+		empty, err := isEmpty(path, info)
+		if err != nil {
+			return fmt.Errorf("backup path %q is not accessible: %w", path, err)
+		}
+		if empty {
+			return fmt.Errorf("backup path %q is empty", path)
+		}
+	}
+	return nil
+}
+
+// isEmpty reports whether a directory has no entries or a file has no data.
+func isEmpty(path string, info os.FileInfo) (bool, error) {
+	if !info.IsDir() {
+		return info.Size() == 0, nil
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+
+	if _, err := dir.Readdirnames(1); err != nil {
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
@@ -0,0 +1,69 @@
+package runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+)
+
+// progressFileInterval limits how often the progress file is rewritten, as
+// restic reports progress many times per second.
+const progressFileInterval = time.Second
+
+// progressWriter returns a callback that writes the latest backup progress
+// to path, or nil if path is empty. The last update of a backup, at 100%, is
+// always written.
+func (s *Impl) progressWriter(path string) models.ResticProgressCallback {
+	if path == "" {
+		return nil
+	}
+
+	var lastWrite time.Time
+	return func(progress models.BackupProgress) {
+		now := s.now()
+		if now.Sub(lastWrite) < progressFileInterval && progress.PercentDone < 1 {
+			return
+		}
+		lastWrite = now
+
+		if err := writeProgressFile(path, progress); err != nil {
+			s.logger.Warn().Err(err).Str("path", path).Msg("failed to write progress file")
+		}
+	}
+}
+
+// writeProgressFile replaces path with the JSON encoded progress. The file is
+// written to a temp file and renamed, so readers never see it half-written.
+func writeProgressFile(path string, progress models.BackupProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("encoding progress: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp progress file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }() // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("writing progress: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing progress file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, 0o644); err != nil { //nolint:gosec // dashboards must be able to read it
+		return fmt.Errorf("setting progress file permissions: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming progress file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+)
+
+// isRetryableBackupError reports whether a failed backup is likely to succeed
+// when run again, i.e. it failed to reach the repository.
+func isRetryableBackupError(err error) bool {
+	return restic.KindOf(err) == models.ErrorKindNetwork
+}
+
+// backupWithRetry runs a single restic backup, retrying network errors up to
+// settings.Retries times with exponential backoff.
+func (s *Impl) backupWithRetry(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings) (*models.BackupResult, error) {
+	delay := settings.RetryDelay
+	progressCb := s.progressWriter(settings.ProgressFile)
+
+	for attempt := 1; ; attempt++ {
+		result, err := s.resticSvc.Backup(ctx, cfg, settings, progressCb)
+		if err == nil && result.Error != nil {
+			err = result.Error
+		}
+		if err == nil {
+			return result, nil
+		}
+
+		if attempt > settings.Retries || ctx.Err() != nil || !isRetryableBackupError(err) {
+			return nil, err
+		}
+
+		s.logger.Warn().Err(err).
+			Int("attempt", attempt).
+			Str("retry_in", delay.String()).
+			Msg("backup failed with a network error, retrying")
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("backup retry aborted: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
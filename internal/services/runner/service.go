@@ -3,24 +3,56 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/fgeck/gorestic-homelab/internal/services/discord"
+	"github.com/fgeck/gorestic-homelab/internal/services/email"
+	"github.com/fgeck/gorestic-homelab/internal/services/healthcheck"
+	"github.com/fgeck/gorestic-homelab/internal/services/hooks"
+	"github.com/fgeck/gorestic-homelab/internal/services/metrics"
+	"github.com/fgeck/gorestic-homelab/internal/services/mysql"
+	"github.com/fgeck/gorestic-homelab/internal/services/ntfy"
 	"github.com/fgeck/gorestic-homelab/internal/services/postgres"
 	"github.com/fgeck/gorestic-homelab/internal/services/pushover"
 	"github.com/fgeck/gorestic-homelab/internal/services/restic"
+	"github.com/fgeck/gorestic-homelab/internal/services/slack"
+	"github.com/fgeck/gorestic-homelab/internal/services/sqlite"
 	"github.com/fgeck/gorestic-homelab/internal/services/ssh"
 	"github.com/fgeck/gorestic-homelab/internal/services/telegram"
+	"github.com/fgeck/gorestic-homelab/internal/services/webhook"
 	"github.com/fgeck/gorestic-homelab/internal/services/wol"
 	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
 )
 
 // Service defines the interface for the backup runner.
 type Service interface {
 	Run(ctx context.Context, cfg models.BackupConfig) error
+	RunWithSummary(ctx context.Context, cfg models.BackupConfig) (*models.RunSummary, error)
+}
+
+// Services holds the services the runner depends on.
+type Services struct {
+	Restic      restic.Service
+	WOL         wol.Service
+	Postgres    postgres.Service
+	MySQL       mysql.Service
+	SQLite      sqlite.Service
+	SSH         ssh.Service
+	Hooks       hooks.Service
+	Metrics     metrics.Service
+	Healthcheck healthcheck.Service
+
+	// Notifiers receive the result of every run.
+	Notifiers []Notifier
 }
 
 // Impl implements the runner Service interface.
@@ -28,87 +60,181 @@ type Impl struct {
 	resticSvc   restic.Service
 	wolSvc      wol.Service
 	postgresSvc postgres.Service
+	mysqlSvc    mysql.Service
+	sqliteSvc   sqlite.Service
 	sshSvc      ssh.Service
-	telegramSvc telegram.Service
-	pushoverSvc pushover.Service
+	hooksSvc    hooks.Service
+	metricsSvc  metrics.Service
+	healthSvc   healthcheck.Service
+	notifiers   []Notifier
 	logger      zerolog.Logger
 	tempDir     string
+	now         func() time.Time // injectable clock for tag placeholders
+	dial        func(ctx context.Context, network, address string) (net.Conn, error)
 }
 
-// New creates a new runner service.
-func New(logger zerolog.Logger) *Impl {
-	return &Impl{
-		resticSvc:   restic.New(logger),
-		wolSvc:      wol.New(logger),
-		postgresSvc: postgres.New(logger),
-		sshSvc:      ssh.New(logger),
-		telegramSvc: telegram.New(logger),
-		pushoverSvc: pushover.New(logger),
-		logger:      logger,
-		tempDir:     os.TempDir(),
+// New creates a new runner service that notifies the channels configured in cfg.
+func New(logger zerolog.Logger, cfg models.BackupConfig) *Impl {
+	var wolTimeout, telegramTimeout time.Duration
+	if cfg.WOL != nil {
+		wolTimeout = cfg.WOL.HTTPTimeout
+	}
+	if cfg.Telegram != nil {
+		telegramTimeout = cfg.Telegram.HTTPTimeout
 	}
+
+	notificationSvcs := notificationServices{
+		telegram: telegram.New(logger, telegramTimeout),
+		pushover: pushover.New(logger),
+		webhook:  webhook.New(logger),
+		discord:  discord.New(logger),
+		slack:    slack.New(logger),
+		ntfy:     ntfy.New(logger),
+		email:    email.New(logger),
+	}
+
+	return NewWithServices(logger, Services{
+		Restic:      restic.New(logger),
+		WOL:         wol.New(logger, wolTimeout),
+		Postgres:    postgres.New(logger),
+		MySQL:       mysql.New(logger),
+		SQLite:      sqlite.New(logger),
+		SSH:         ssh.New(logger),
+		Hooks:       hooks.New(logger),
+		Metrics:     metrics.New(logger),
+		Healthcheck: healthcheck.New(logger),
+		Notifiers:   configuredNotifiers(notificationSvcs, cfg),
+	}, os.TempDir())
 }
 
 // NewWithServices creates a new runner service with custom services (for testing).
-func NewWithServices(
-	logger zerolog.Logger,
-	resticSvc restic.Service,
-	wolSvc wol.Service,
-	postgresSvc postgres.Service,
-	sshSvc ssh.Service,
-	telegramSvc telegram.Service,
-	pushoverSvc pushover.Service,
-	tempDir string,
-) *Impl {
+func NewWithServices(logger zerolog.Logger, services Services, tempDir string) *Impl {
 	return &Impl{
-		resticSvc:   resticSvc,
-		wolSvc:      wolSvc,
-		postgresSvc: postgresSvc,
-		sshSvc:      sshSvc,
-		telegramSvc: telegramSvc,
-		pushoverSvc: pushoverSvc,
+		resticSvc:   services.Restic,
+		wolSvc:      services.WOL,
+		postgresSvc: services.Postgres,
+		mysqlSvc:    services.MySQL,
+		sqliteSvc:   services.SQLite,
+		sshSvc:      services.SSH,
+		hooksSvc:    services.Hooks,
+		metricsSvc:  services.Metrics,
+		healthSvc:   services.Healthcheck,
+		notifiers:   services.Notifiers,
 		logger:      logger,
 		tempDir:     tempDir,
+		now:         time.Now,
+		dial:        (&net.Dialer{}).DialContext,
 	}
 }
 
 // Run executes the complete backup workflow.
-//
+func (s *Impl) Run(ctx context.Context, cfg models.BackupConfig) error {
+	_, err := s.RunWithSummary(ctx, cfg)
+	return err
+}
+
+// RunWithSummary executes the complete backup workflow and returns a summary of the run.
+// The summary is returned on failure as well.
+func (s *Impl) RunWithSummary(ctx context.Context, cfg models.BackupConfig) (*models.RunSummary, error) {
+	summary := &models.RunSummary{}
+	err := s.run(ctx, cfg, summary)
+	return summary, err
+}
+
 //nolint:gocognit,gocyclo // backup workflow has multiple steps by design
-func (s *Impl) Run(ctx context.Context, cfg models.BackupConfig) (returnErr error) {
+func (s *Impl) run(ctx context.Context, cfg models.BackupConfig, summary *models.RunSummary) (returnErr error) {
 	startTime := time.Now()
 	var failedStep string
 	wolAttempted := cfg.WOL != nil
 	wolSucceeded := false
+	skipped := false
 
 	// Track backup results for notification even if later steps fail
 	var backupStats *models.BackupResult
 	var forgetStats *models.ForgetResult
+	var repoStats *models.StatsResult
+	timings := stepTimings{}
+
+	// Fill the summary once everything else, including SSH shutdown, has finished
+	defer func() {
+		*summary = buildRunSummary(buildStats(startTime, cfg, failedStep, returnErr, backupStats, forgetStats))
+		summary.StepTimings = timings
+	}()
+
+	cfg.Restic.DryRun = cfg.DryRun
+
+	if cfg.LockFile != "" {
+		failedStep = "lock"
+		release, err := acquireLock(cfg.LockFile)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	// All transient artifacts go into a per-run directory, removed as a whole on
+	// exit. Deferred calls also run when a later step panics.
+	failedStep = "temp_dir"
+	runDir, err := s.createRunDir(cfg.TempDir)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.RemoveAll(runDir); err != nil {
+			s.logger.Warn().Err(err).Str("dir", runDir).Msg("failed to remove run directory")
+		}
+	}()
 
 	s.logger.Info().
 		Str("repository", cfg.Restic.Repository).
 		Str("host", cfg.Backup.Host).
+		Bool("dry_run", cfg.DryRun).
 		Msg("starting backup run")
 
-	// Send notification on exit if configured (registered first, runs last due to LIFO)
-	defer func() {
-		if cfg.Telegram != nil {
-			s.sendNotificationWithStats(ctx, cfg, startTime, failedStep, returnErr, backupStats, forgetStats)
+	// Send notification on exit if configured
+	notifyOnExit := func() {
+		switch {
+		case returnErr != nil:
+			s.pingHealthcheck(ctx, cfg, models.HealthcheckFail)
+		case skipped:
+			s.pingHealthcheck(ctx, cfg, models.HealthcheckSkipped)
+		default:
+			s.pingHealthcheck(ctx, cfg, models.HealthcheckSuccess)
 		}
-		if cfg.Pushover != nil {
-			s.sendPushoverNotification(ctx, cfg, startTime, failedStep, returnErr, backupStats, forgetStats)
+		if cfg.MetricsFile != "" {
+			s.writeMetrics(cfg, startTime, failedStep, returnErr, skipped, backupStats, forgetStats, repoStats)
 		}
-	}()
+		if skipped {
+			s.logger.Debug().Msg("notifications skipped, no backup was made")
+			return
+		}
+		if !shouldNotify(cfg.NotifyOn, returnErr == nil) {
+			s.logger.Debug().Str("notify_on", cfg.NotifyOn).Msg("notifications skipped")
+			return
+		}
+		msg := buildTelegramMessage(buildStats(startTime, cfg, failedStep, returnErr, backupStats, forgetStats), repoStats)
+		if cfg.AttachLog && returnErr != nil {
+			msg.LogExcerpt = logExcerpt(returnErr, logExcerptLines)
+		}
+		s.notify(ctx, msg)
+	}
 
 	// SSH shutdown runs on exit if configured and either:
 	// - WOL was not configured (standalone SSH shutdown), or
 	// - WOL was configured and succeeded (machine was woken up)
 	// This ensures the target machine is shut down even if backup fails
-	// (registered second, runs before Telegram notification)
-	defer func() {
+	shutdownOnExit := func() {
+		// Checked first, as a dry-run skips WOL and so never counts as woken
+		if cfg.SSHShutdown != nil && cfg.DryRun {
+			s.logger.Info().Msg("SSH shutdown skipped (dry-run)")
+			return
+		}
 		shouldShutdown := cfg.SSHShutdown != nil && (!wolAttempted || wolSucceeded)
 		if shouldShutdown {
-			if err := s.runSSHShutdown(ctx, cfg.SSHShutdown); err != nil {
+			stop := timings.start("ssh_shutdown")
+			err := s.runSSHShutdown(ctx, cfg.SSHShutdown)
+			stop()
+			if err != nil {
 				s.logger.Error().Err(err).Msg("SSH shutdown failed")
 				// Don't override returnErr if backup already failed
 				if returnErr == nil {
@@ -117,88 +243,267 @@ func (s *Impl) Run(ctx context.Context, cfg models.BackupConfig) (returnErr erro
 				}
 			}
 		}
-	}()
+	}
+
+	// Deferred calls run LIFO. By default the shutdown runs first, so a failed
+	// shutdown is reported. With notify.before_shutdown notifications are sent
+	// while the network path through the target host is still up.
+	if cfg.NotifyBeforeShutdown {
+		defer shutdownOnExit()
+		defer notifyOnExit()
+	} else {
+		defer notifyOnExit()
+		defer shutdownOnExit()
+	}
+
+	// Wait for the network first, e.g. when started at boot, so the start ping
+	// does not hit a network that is not up yet
+	if cfg.Network != nil {
+		failedStep = "network"
+		stop := timings.start("network")
+		err := s.waitForNetwork(ctx, cfg.Network)
+		stop()
+		if err != nil {
+			returnErr = err
+			return err
+		}
+	}
+
+	s.pingHealthcheck(ctx, cfg, models.HealthcheckStart)
+
+	// Fail fast if restic is missing, before waking any host
+	failedStep = "restic"
+	if _, err := s.resticSvc.CheckBinary(ctx); err != nil {
+		returnErr = err
+		return err
+	}
 
 	// Step 1: Wake-on-LAN (if configured)
-	if cfg.WOL != nil {
+	switch {
+	case cfg.WOL != nil && cfg.DryRun:
+		s.logger.Info().Msg("WOL skipped (dry-run)")
+	case cfg.WOL != nil:
 		failedStep = "wol"
-		if err := s.runWOL(ctx, cfg.WOL); err != nil {
+		stop := timings.start("wol")
+		err := s.runWOL(ctx, cfg.WOL)
+		stop()
+		if err != nil {
 			returnErr = err
 			return err
 		}
 		wolSucceeded = true
 	}
 
-	// Step 2: Initialize repository (if needed)
-	failedStep = "init"
-	if err := s.resticSvc.Init(ctx, cfg.Restic); err != nil {
-		returnErr = err
-		return fmt.Errorf("init failed: %w", err)
+	// Steps 2 and 3 modify the repository, so a dry-run skips them
+	if cfg.DryRun {
+		s.logger.Info().Msg("init and unlock skipped (dry-run)")
+	} else {
+		// Step 2: Initialize repository (if needed)
+		failedStep = "init"
+		stop := timings.start("init")
+		_, err = s.resticSvc.Init(ctx, cfg.Restic)
+		stop()
+		if err != nil {
+			returnErr = err
+			return fmt.Errorf("init failed: %w", err)
+		}
+
+		// Step 3: Unlock repository (remove stale locks)
+		failedStep = "unlock"
+		stop = timings.start("unlock")
+		_, err = s.resticSvc.Unlock(ctx, cfg.Restic)
+		stop()
+		if err != nil {
+			returnErr = err
+			return fmt.Errorf("unlock failed: %w", err)
+		}
 	}
 
-	// Step 3: Unlock repository (remove stale locks)
-	failedStep = "unlock"
-	if err := s.resticSvc.Unlock(ctx, cfg.Restic); err != nil {
-		returnErr = err
-		return fmt.Errorf("unlock failed: %w", err)
+	// Skip the run if this host was backed up recently (unless forced). This
+	// runs after WOL, Init and Unlock because the repository may live on the
+	// woken host, so that host is woken and shut down again for a skipped run.
+	if cfg.Backup.MinInterval > 0 && !cfg.Force {
+		failedStep = "min_interval"
+		recent, found, err := s.recentSnapshot(ctx, cfg)
+		if err != nil {
+			returnErr = err
+			return err
+		}
+		if found {
+			s.logger.Info().
+				Str("snapshot_id", recent.ID).
+				Time("snapshot_time", recent.Time).
+				Str("min_interval", cfg.Backup.MinInterval.String()).
+				Msg("recent snapshot exists, skipping")
+			skipped = true
+			failedStep = ""
+			return nil
+		}
 	}
 
-	// Step 4: PostgreSQL dump (if configured)
-	var pgDumpPath string
+	// Post-hooks always run once pre-hooks were started
+	// (deferred after SSH shutdown, so they run before it)
+	defer func() {
+		if len(cfg.PostHooks) > 0 {
+			defer timings.start("post_hook")()
+		}
+		s.runPostHooks(ctx, cfg)
+	}()
+
+	// Pre-hooks (if configured)
+	if len(cfg.PreHooks) > 0 {
+		failedStep = "pre_hook"
+		stop := timings.start("pre_hook")
+		err := s.runPreHooks(ctx, cfg)
+		stop()
+		if err != nil {
+			returnErr = err
+			return err
+		}
+	}
+
+	// Step 4: Database dumps (if configured)
+	var dumpPaths, postgresPaths []string
+	// Clean up after backup, including dumps of a partially failed run
+	defer func() {
+		for _, path := range dumpPaths {
+			_ = os.RemoveAll(path) // directory format dumps are directories
+		}
+	}()
 	if cfg.Postgres != nil {
 		failedStep = "postgres"
-		var err error
-		pgDumpPath, err = s.runPostgresDump(ctx, cfg.Postgres)
+		stop := timings.start("postgres")
+		paths, err := s.runPostgresDump(ctx, cfg.Postgres, runDir)
+		stop()
+		dumpPaths = append(dumpPaths, paths...)
+		postgresPaths = paths
+		if err != nil {
+			returnErr = err
+			return err
+		}
+	}
+	if cfg.MySQL != nil {
+		failedStep = "mysql"
+		stop := timings.start("mysql")
+		path, err := s.runMySQLDump(ctx, cfg.MySQL, runDir)
+		stop()
+		if err != nil {
+			returnErr = err
+			return err
+		}
+		dumpPaths = append(dumpPaths, path)
+	}
+	if cfg.SQLite != nil {
+		failedStep = "sqlite"
+		stop := timings.start("sqlite")
+		paths, err := s.runSQLiteBackup(ctx, cfg.SQLite, runDir)
+		stop()
+		dumpPaths = append(dumpPaths, paths...)
 		if err != nil {
 			returnErr = err
 			return err
 		}
-		defer func() { _ = os.Remove(pgDumpPath) }() // Clean up after backup
 	}
 
 	// Step 5: Backup
 	failedStep = "backup"
-	backupPaths := cfg.Backup.Paths
-	if pgDumpPath != "" {
-		backupPaths = append(backupPaths, pgDumpPath)
-	}
-
-	backupResult, err := s.resticSvc.Backup(ctx, cfg.Restic, models.BackupSettings{
-		Paths: backupPaths,
-		Tags:  cfg.Backup.Tags,
-		Host:  cfg.Backup.Host,
-	})
+	stop := timings.start("backup")
+	backupResult, err := s.runBackups(ctx, cfg, dumpPaths)
+	stop()
 	if err != nil {
 		returnErr = err
 		return fmt.Errorf("backup failed: %w", err)
 	}
-	if backupResult.Error != nil {
-		returnErr = backupResult.Error
-		return fmt.Errorf("backup failed: %w", backupResult.Error)
-	}
 
 	// Store backup stats for notification (even if later steps fail)
 	backupStats = backupResult
 
-	// Step 6: Apply retention policy
-	failedStep = "forget"
-	forgetResult, err := s.resticSvc.Forget(ctx, cfg.Restic, cfg.Retention)
-	if err != nil {
+	if err := checkBackupThresholds(cfg.Backup, backupResult); err != nil {
 		returnErr = err
-		return fmt.Errorf("forget failed: %w", err)
+		return err
 	}
-	if forgetResult.Error != nil {
-		returnErr = forgetResult.Error
-		return fmt.Errorf("forget failed: %w", forgetResult.Error)
+
+	// Keep PostgreSQL dumps as a local restore cache (if enabled)
+	if cfg.Postgres != nil && cfg.Postgres.KeepLocal > 0 {
+		s.retainPostgresDumps(cfg.Postgres, postgresPaths)
 	}
 
-	// Store forget stats for notification
-	forgetStats = forgetResult
+	// Step 6: Copy snapshots to the second repository (if configured)
+	if cfg.CopyTo != nil && cfg.CopyTo.Enabled {
+		if cfg.DryRun {
+			s.logger.Info().Str("destination", cfg.CopyTo.Repository).Msg("dry run: skipping copy")
+		} else {
+			failedStep = "copy"
+			stop := timings.start("copy")
+			copyResult, err := s.resticSvc.Copy(ctx, cfg.Restic, cfg.CopyTo.ResticConfig, models.CopyOptions{Host: cfg.Backup.Host})
+			stop()
+			if err != nil {
+				returnErr = err
+				return fmt.Errorf("copy failed: %w", err)
+			}
+			if copyResult.Error != nil {
+				returnErr = copyResult.Error
+				return fmt.Errorf("copy failed: %w", copyResult.Error)
+			}
+		}
+	}
+
+	// Step 7: Apply retention policy (skipped with prune if nothing changed and configured so)
+	skipRetention := cfg.Retention.SkipWhenUnchanged && backupResult.SnapshotID == ""
+	if skipRetention {
+		s.logger.Info().Msg("no snapshot created, skipping retention")
+	} else {
+		failedStep = "forget"
+		stop = timings.start("forget")
+		forgetResult, err := s.resticSvc.Forget(ctx, cfg.Restic, cfg.Retention)
+		stop()
+		if err != nil {
+			returnErr = err
+			return fmt.Errorf("forget failed: %w", err)
+		}
+		if forgetResult.Error != nil {
+			returnErr = forgetResult.Error
+			return fmt.Errorf("forget failed: %w", forgetResult.Error)
+		}
+
+		// Store forget stats for notification
+		forgetStats = forgetResult
+	}
+
+	// Step 8: Prune unreferenced data (if enabled)
+	if cfg.Retention.Prune.Enabled && !skipRetention {
+		failedStep = "prune"
+		stop := timings.start("prune")
+		pruneResult, err := s.resticSvc.Prune(ctx, cfg.Restic, cfg.Retention.Prune)
+		stop()
+		if err != nil {
+			returnErr = err
+			return fmt.Errorf("prune failed: %w", err)
+		}
+		if pruneResult.Error != nil {
+			returnErr = pruneResult.Error
+			return fmt.Errorf("prune failed: %w", pruneResult.Error)
+		}
+		forgetStats.SpaceFreed = pruneResult.SpaceFreed
+	}
+
+	// Collect repository stats for notifications and metrics (best effort).
+	// This runs after prune, so the size reflects the space it freed.
+	if len(s.notifiers) > 0 || cfg.MetricsFile != "" {
+		stats, err := s.resticSvc.Stats(ctx, cfg.Restic, models.StatsModeRawData)
+		if err != nil {
+			s.logger.Warn().Err(err).Msg("failed to collect repository stats")
+		} else {
+			repoStats = stats
+		}
+	}
 
-	// Step 7: Repository check (if enabled)
+	// Step 9: Repository check (if enabled)
 	if cfg.Check.Enabled {
 		failedStep = "check"
+		stop := timings.start("check")
 		checkResult, err := s.resticSvc.Check(ctx, cfg.Restic, cfg.Check)
+		stop()
 		if err != nil {
 			returnErr = err
 			return fmt.Errorf("check failed: %w", err)
@@ -222,6 +527,146 @@ func (s *Impl) Run(ctx context.Context, cfg models.BackupConfig) (returnErr erro
 	return nil
 }
 
+// recentSnapshot returns the newest snapshot of the configured host if it is
+// younger than cfg.Backup.MinInterval.
+func (s *Impl) recentSnapshot(ctx context.Context, cfg models.BackupConfig) (models.Snapshot, bool, error) {
+	snapshots, err := s.resticSvc.Snapshots(ctx, cfg.Restic, models.SnapshotFilter{Host: cfg.Backup.Host})
+	if err != nil {
+		return models.Snapshot{}, false, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(snapshots) == 0 {
+		return models.Snapshot{}, false, nil
+	}
+
+	newest := snapshots[0]
+	for _, snapshot := range snapshots[1:] {
+		if snapshot.Time.After(newest.Time) {
+			newest = snapshot
+		}
+	}
+	return newest, s.now().Sub(newest.Time) < cfg.Backup.MinInterval, nil
+}
+
+// PartialError is returned when a backup fails after earlier snapshots of
+// the same run were created, e.g. for the first of several backup targets.
+type PartialError struct {
+	Err       error
+	Snapshots int // snapshots created before the failure
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%v (%d snapshots created before the failure)", e.Err, e.Snapshots)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// runBackups creates one snapshot of the flat backup paths plus the database
+// dumps, one snapshot per backup target and one per streamed PostgreSQL database.
+// The returned result combines all snapshots.
+func (s *Impl) runBackups(ctx context.Context, cfg models.BackupConfig, dumpPaths []string) (*models.BackupResult, error) {
+	if err := checkBackupPaths(cfg.Backup); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+
+	// backupJob is one snapshot: a file backup or a streamed PostgreSQL database
+	type backupJob struct {
+		settings models.BackupSettings
+		database string
+	}
+
+	var jobs []backupJob
+	if len(cfg.Backup.Paths) > 0 || cfg.Backup.FilesFrom != "" || len(dumpPaths) > 0 {
+		flat := cfg.Backup
+		flat.Targets = nil
+		flat.DumpPaths = dumpPaths
+		flat.Tags = expandTags(cfg.Backup.Tags, now, cfg.Backup.Host)
+		jobs = append(jobs, backupJob{settings: flat})
+	}
+	for _, target := range cfg.Backup.Targets {
+		targetSettings := cfg.Backup
+		targetSettings.Targets = nil
+		targetSettings.FilesFrom = ""
+		targetSettings.Paths = target.Paths
+		targetSettings.Tags = expandTags(target.Tags, now, cfg.Backup.Host)
+		jobs = append(jobs, backupJob{settings: targetSettings})
+	}
+	if cfg.Postgres != nil && cfg.Postgres.StreamToRestic {
+		for _, db := range cfg.Postgres.DatabaseNames() {
+			streamSettings := cfg.Backup
+			streamSettings.Targets = nil
+			streamSettings.FilesFrom = ""
+			streamSettings.Paths = nil
+			streamSettings.Tags = expandTags(cfg.Backup.Tags, now, cfg.Backup.Host)
+			jobs = append(jobs, backupJob{settings: streamSettings, database: db})
+		}
+	}
+
+	results := make([]*models.BackupResult, 0, len(jobs))
+	for _, job := range jobs {
+		backupSettings := job.settings
+		var result *models.BackupResult
+		var err error
+		if job.database != "" {
+			result, err = s.streamPostgresDump(ctx, cfg, backupSettings, job.database)
+		} else {
+			result, err = s.backupWithRetry(ctx, cfg.Restic, backupSettings)
+		}
+		if err != nil && len(results) > 0 {
+			return nil, &PartialError{Err: err, Snapshots: len(results)}
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.logger.Info().
+			Str("snapshot_id", result.SnapshotID).
+			Strs("tags", backupSettings.Tags).
+			Msg("snapshot created")
+		results = append(results, result)
+	}
+
+	return mergeBackupResults(results), nil
+}
+
+// mergeBackupResults sums the stats of several snapshots for notifications.
+func mergeBackupResults(results []*models.BackupResult) *models.BackupResult {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	merged := &models.BackupResult{}
+	ids := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.SnapshotID != "" {
+			ids = append(ids, result.SnapshotID)
+		}
+		merged.FilesNew += result.FilesNew
+		merged.FilesChanged += result.FilesChanged
+		merged.FilesUnmodified += result.FilesUnmodified
+		merged.DataAdded += result.DataAdded
+		merged.TotalFilesProcessed += result.TotalFilesProcessed
+		merged.TotalBytesProcessed += result.TotalBytesProcessed
+		merged.Duration += result.Duration
+		merged.Warnings = append(merged.Warnings, result.Warnings...)
+		merged.Errors = append(merged.Errors, result.Errors...)
+	}
+	merged.SnapshotID = strings.Join(ids, ", ")
+	return merged
+}
+
+// checkBackupThresholds fails if the backup added less data or processed fewer
+// files than configured, which usually means the backup silently missed data.
+func checkBackupThresholds(settings models.BackupSettings, result *models.BackupResult) error {
+	if result.DataAdded < settings.MinDataAdded || result.TotalFilesProcessed < settings.MinFilesProcessed {
+		return fmt.Errorf("backup produced suspiciously little data: %d bytes added (min %d), %d files processed (min %d)",
+			result.DataAdded, settings.MinDataAdded, result.TotalFilesProcessed, settings.MinFilesProcessed)
+	}
+	return nil
+}
+
 func (s *Impl) runWOL(ctx context.Context, cfg *models.WOLConfig) error {
 	result, err := s.wolSvc.Wake(ctx, *cfg)
 	if err != nil {
@@ -238,20 +683,255 @@ func (s *Impl) runWOL(ctx context.Context, cfg *models.WOLConfig) error {
 	return nil
 }
 
-func (s *Impl) runPostgresDump(ctx context.Context, cfg *models.PostgresConfig) (string, error) {
-	outputPath := filepath.Join(s.tempDir, postgres.GetOutputFilename(*cfg))
+func (s *Impl) runPreHooks(ctx context.Context, cfg models.BackupConfig) error {
+	for _, command := range cfg.PreHooks {
+		if cfg.DryRun {
+			s.logger.Info().Str("command", command).Msg("pre-hook skipped (dry-run)")
+			continue
+		}
+		result, err := s.hooksSvc.Run(ctx, command)
+		if err != nil {
+			return fmt.Errorf("pre-hook failed: %w", err)
+		}
+		if result.Error != nil {
+			return fmt.Errorf("pre-hook failed: %w", result.Error)
+		}
+	}
+	return nil
+}
+
+func (s *Impl) runPostHooks(ctx context.Context, cfg models.BackupConfig) {
+	for _, command := range cfg.PostHooks {
+		if cfg.DryRun {
+			s.logger.Info().Str("command", command).Msg("post-hook skipped (dry-run)")
+			continue
+		}
+		result, err := s.hooksSvc.Run(ctx, command)
+		if err != nil {
+			s.logger.Error().Err(err).Str("command", command).Msg("post-hook failed")
+			continue
+		}
+		if result.Error != nil {
+			s.logger.Error().Err(result.Error).Str("command", command).Msg("post-hook failed")
+		}
+	}
+}
+
+// createRunDir creates a unique directory for this run's transient files below
+// parent, or below the service temp dir if parent is empty.
+func (s *Impl) createRunDir(parent string) (string, error) {
+	if parent == "" {
+		parent = s.tempDir
+	}
+	if err := os.MkdirAll(parent, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	dir, err := os.MkdirTemp(parent, "gorestic-run-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create run directory: %w", err)
+	}
+	return dir, nil
+}
+
+// runPostgresDump dumps each configured database into its own file, plus the
+// globals if enabled. The paths of completed dumps are returned even on error
+// so they can be cleaned up.
+func (s *Impl) runPostgresDump(ctx context.Context, cfg *models.PostgresConfig, runDir string) ([]string, error) {
+	if err := s.checkPostgresVersion(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	// Streamed databases are piped into restic during the backup step instead
+	var paths []string
+	if !cfg.StreamToRestic {
+		var err error
+		paths, err = s.dumpPostgresDatabases(ctx, cfg, runDir)
+		if err != nil {
+			return paths, err
+		}
+	}
+
+	if cfg.DumpGlobals {
+		outputPath := filepath.Join(runDir, postgres.GlobalsFilename)
+
+		result, err := s.postgresSvc.DumpGlobals(ctx, *cfg, outputPath)
+		if err != nil {
+			return paths, fmt.Errorf("PostgreSQL globals dump failed: %w", err)
+		}
+		if result.Error != nil {
+			return paths, fmt.Errorf("PostgreSQL globals dump failed: %w", result.Error)
+		}
+		paths = append(paths, result.OutputPath)
+	}
+
+	return paths, nil
+}
+
+// dumpPostgresDatabases dumps up to Parallelism databases at a time into runDir.
+// Every database is attempted, paths are kept in config order and all failures are reported.
+func (s *Impl) dumpPostgresDatabases(ctx context.Context, cfg *models.PostgresConfig, runDir string) ([]string, error) {
+	names := cfg.DatabaseNames()
+	dumpPaths := make([]string, len(names))
+	dumpErrs := make([]error, len(names))
+
+	var group errgroup.Group
+	group.SetLimit(max(cfg.Parallelism, 1))
+	for i, db := range names {
+		group.Go(func() error {
+			dumpPaths[i], dumpErrs[i] = s.dumpPostgresDatabase(ctx, *cfg, db, runDir)
+			return nil
+		})
+	}
+	_ = group.Wait()
+
+	var paths []string
+	for _, path := range dumpPaths {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths, errors.Join(dumpErrs...)
+}
+
+// streamPostgresDump pipes the pg_dump output of db into restic backup --stdin,
+// so the dump never touches the local disk.
+func (s *Impl) streamPostgresDump(ctx context.Context, cfg models.BackupConfig, settings models.BackupSettings, db string) (*models.BackupResult, error) {
+	pgCfg := *cfg.Postgres
+	pgCfg.Database = db
+	settings.StdinFilename = postgres.StreamFilename(pgCfg)
+
+	pr, pw := io.Pipe()
+	dumpDone := make(chan error, 1)
+	go func() {
+		result, err := s.postgresSvc.DumpTo(ctx, pgCfg, pw)
+		if err == nil && result.Error != nil {
+			err = result.Error
+		}
+		// A failed dump must not look like a complete one to restic
+		_ = pw.CloseWithError(err)
+		dumpDone <- err
+	}()
 
-	result, err := s.postgresSvc.Dump(ctx, *cfg, outputPath)
+	result, err := s.resticSvc.BackupStdin(ctx, cfg.Restic, settings, pr)
+	// Unblock the dump if restic stopped reading early
+	_ = pr.CloseWithError(io.ErrClosedPipe)
+	if dumpErr := <-dumpDone; dumpErr != nil {
+		return nil, fmt.Errorf("PostgreSQL dump failed for %s: %w", db, dumpErr)
+	}
 	if err != nil {
-		return "", fmt.Errorf("PostgreSQL dump failed: %w", err)
+		return nil, fmt.Errorf("streamed backup failed for %s: %w", db, err)
 	}
 	if result.Error != nil {
-		return "", fmt.Errorf("PostgreSQL dump failed: %w", result.Error)
+		return nil, fmt.Errorf("streamed backup failed for %s: %w", db, result.Error)
 	}
+	return result, nil
+}
+
+// dumpPostgresDatabase dumps a single database into runDir. Dump files are
+// named after the database, so concurrent dumps do not collide.
+func (s *Impl) dumpPostgresDatabase(ctx context.Context, cfg models.PostgresConfig, db, runDir string) (string, error) {
+	cfg.Database = db
+	outputPath := filepath.Join(runDir, postgres.GetOutputFilename(cfg))
 
+	result, err := s.postgresSvc.Dump(ctx, cfg, outputPath)
+	if err != nil {
+		return "", fmt.Errorf("PostgreSQL dump failed for %s: %w", db, err)
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("PostgreSQL dump failed for %s: %w", db, result.Error)
+	}
 	return result.OutputPath, nil
 }
 
+// checkPostgresVersion fails if pg_dump is older than postgres.min_version and
+// warns if it is older than the server, which pg_dump refuses to dump.
+func (s *Impl) checkPostgresVersion(ctx context.Context, cfg *models.PostgresConfig) error {
+	client, err := s.postgresSvc.CheckVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("PostgreSQL version check failed: %w", err)
+	}
+	if cfg.MinVersion > 0 && client < cfg.MinVersion {
+		return fmt.Errorf("pg_dump version %d is older than postgres.min_version %d", client, cfg.MinVersion)
+	}
+
+	dbCfg := *cfg
+	dbCfg.Database = cfg.DatabaseNames()[0]
+	server, err := s.postgresSvc.ServerVersion(ctx, dbCfg)
+	if err != nil {
+		s.logger.Debug().Err(err).Msg("could not determine PostgreSQL server version")
+		return nil
+	}
+	if client < server {
+		s.logger.Warn().
+			Int("pg_dump_version", client).
+			Int("server_version", server).
+			Msg("pg_dump is older than the PostgreSQL server, the dump will likely fail")
+	}
+
+	return nil
+}
+
+// retainPostgresDumps moves the database dumps into the local dump directory
+// and removes dumps beyond the configured count. Failures are only logged,
+// the dumps are already part of the backup.
+func (s *Impl) retainPostgresDumps(cfg *models.PostgresConfig, paths []string) {
+	for _, path := range paths {
+		if filepath.Base(path) == postgres.GlobalsFilename {
+			continue
+		}
+		dst, err := postgres.MoveDump(path, cfg.LocalDir)
+		if err != nil {
+			s.logger.Error().Err(err).Str("path", path).Msg("failed to keep PostgreSQL dump locally")
+			continue
+		}
+		s.logger.Info().Str("path", dst).Msg("kept PostgreSQL dump locally")
+	}
+
+	for _, db := range cfg.DatabaseNames() {
+		removed, err := postgres.RotateDumps(cfg.LocalDir, db, cfg.KeepLocal)
+		if err != nil {
+			s.logger.Error().Err(err).Str("database", db).Msg("failed to rotate local PostgreSQL dumps")
+			continue
+		}
+		for _, path := range removed {
+			s.logger.Debug().Str("path", path).Msg("removed old PostgreSQL dump")
+		}
+	}
+}
+
+func (s *Impl) runMySQLDump(ctx context.Context, cfg *models.MySQLConfig, runDir string) (string, error) {
+	outputPath := filepath.Join(runDir, mysql.GetOutputFilename(*cfg))
+
+	result, err := s.mysqlSvc.Dump(ctx, *cfg, outputPath)
+	if err != nil {
+		return "", fmt.Errorf("MySQL dump failed: %w", err)
+	}
+	if result.Error != nil {
+		return "", fmt.Errorf("MySQL dump failed: %w", result.Error)
+	}
+
+	return result.OutputPath, nil
+}
+
+// runSQLiteBackup snapshots the configured SQLite databases. Completed
+// snapshots are returned even on error so they can be cleaned up.
+func (s *Impl) runSQLiteBackup(ctx context.Context, cfg *models.SQLiteConfig, runDir string) ([]string, error) {
+	sqliteCfg := *cfg
+	if sqliteCfg.OutputDir == "" {
+		sqliteCfg.OutputDir = runDir
+	}
+
+	result, err := s.sqliteSvc.Backup(ctx, sqliteCfg)
+	if err != nil {
+		return nil, fmt.Errorf("SQLite backup failed: %w", err)
+	}
+	if result.Error != nil {
+		return result.OutputPaths, fmt.Errorf("SQLite backup failed: %w", result.Error)
+	}
+
+	return result.OutputPaths, nil
+}
+
 func (s *Impl) runSSHShutdown(ctx context.Context, cfg *models.SSHShutdownConfig) error {
 	// Load private key if needed
 	if cfg.PrivateKey == nil && cfg.KeyPath != "" {
@@ -274,12 +954,21 @@ func (s *Impl) runSSHShutdown(ctx context.Context, cfg *models.SSHShutdownConfig
 		}
 	}
 
+	if cfg.VerifyDown {
+		if result.Confirmed {
+			s.logger.Info().Str("host", cfg.Host).Msg("SSH shutdown confirmed, host is down")
+		} else {
+			s.logger.Warn().Str("host", cfg.Host).Msg("SSH shutdown not confirmed, host still reachable")
+		}
+	}
+
 	return nil
 }
 
 // notificationStats holds the common data used to build notification messages.
 type notificationStats struct {
 	success          bool
+	dryRun           bool
 	host             string
 	repository       string
 	startTime        time.Time
@@ -295,6 +984,17 @@ type notificationStats struct {
 	totalBytes       int64
 	snapshotsKept    int
 	snapshotsRemoved int
+	spaceFreed       int64
+	unreadableFiles  int
+	throughput       int64
+}
+
+// throughput returns the processed bytes per second, or 0 for a zero duration.
+func throughput(bytes int64, d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64(float64(bytes) / d.Seconds())
 }
 
 func buildStats(
@@ -307,6 +1007,7 @@ func buildStats(
 ) notificationStats {
 	s := notificationStats{
 		success:    runErr == nil,
+		dryRun:     cfg.DryRun,
 		host:       cfg.Backup.Host,
 		repository: cfg.Restic.Repository,
 		startTime:  startTime,
@@ -324,70 +1025,93 @@ func buildStats(
 		s.dataAdded = backupStats.DataAdded
 		s.totalFiles = backupStats.TotalFilesProcessed
 		s.totalBytes = backupStats.TotalBytesProcessed
+		s.unreadableFiles = len(backupStats.Errors)
+		s.throughput = throughput(backupStats.TotalBytesProcessed, backupStats.Duration)
 	}
 	if forgetStats != nil {
 		s.snapshotsKept = forgetStats.SnapshotsKept
 		s.snapshotsRemoved = forgetStats.SnapshotsRemoved
+		s.spaceFreed = forgetStats.SpaceFreed
 	}
 	return s
 }
 
-//nolint:dupl // structurally similar to sendPushoverNotification by design — same stats, different message types and services
-func (s *Impl) sendNotificationWithStats(
-	ctx context.Context,
-	cfg models.BackupConfig,
-	startTime time.Time,
-	failedStep string,
-	runErr error,
-	backupStats *models.BackupResult,
-	forgetStats *models.ForgetResult,
-) {
-	ns := buildStats(startTime, cfg, failedStep, runErr, backupStats, forgetStats)
+// shouldNotify reports whether notifiers fire for a run with the given outcome.
+func shouldNotify(notifyOn string, success bool) bool {
+	switch notifyOn {
+	case models.NotifyFailure:
+		return !success
+	case models.NotifySuccess:
+		return success
+	default:
+		return true
+	}
+}
 
-	// Collect backup stats for notification
-	msg := models.TelegramMessage{
-		Success:          ns.success,
-		Host:             ns.host,
-		Repository:       ns.repository,
-		StartTime:        ns.startTime,
-		Duration:         ns.duration,
-		FailedStep:       ns.failedStep,
-		ErrorMessage:     ns.errorMessage,
-		SnapshotID:       ns.snapshotID,
-		FilesNew:         ns.filesNew,
-		FilesChanged:     ns.filesChanged,
-		FilesUnmodified:  ns.filesUnmodified,
-		DataAdded:        ns.dataAdded,
-		TotalFiles:       ns.totalFiles,
-		TotalBytes:       ns.totalBytes,
-		SnapshotsKept:    ns.snapshotsKept,
-		SnapshotsRemoved: ns.snapshotsRemoved,
+// pingHealthcheck reports the run status to the healthcheck URL, if configured.
+func (s *Impl) pingHealthcheck(ctx context.Context, cfg models.BackupConfig, status models.HealthcheckStatus) {
+	if cfg.Healthcheck == nil {
+		return
+	}
+	if cfg.DryRun {
+		s.logger.Info().Str("status", string(status)).Msg("healthcheck ping skipped (dry-run)")
+		return
 	}
 
-	result, err := s.telegramSvc.SendNotification(ctx, *cfg.Telegram, msg)
+	result, err := s.healthSvc.Ping(ctx, cfg.Healthcheck.PingURL, status)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to send Telegram notification")
+		s.logger.Error().Err(err).Msg("failed to send healthcheck ping")
 		return
 	}
 	if result.Error != nil {
-		s.logger.Error().Err(result.Error).Msg("failed to send Telegram notification")
+		s.logger.Error().Err(result.Error).Msg("failed to send healthcheck ping")
 	}
 }
 
-//nolint:dupl // structurally similar to sendNotificationWithStats by design — same stats, different message types and services
-func (s *Impl) sendPushoverNotification(
-	ctx context.Context,
+// writeMetrics writes the run result to the Prometheus textfile.
+func (s *Impl) writeMetrics(
 	cfg models.BackupConfig,
 	startTime time.Time,
 	failedStep string,
 	runErr error,
+	skipped bool,
 	backupStats *models.BackupResult,
 	forgetStats *models.ForgetResult,
+	repoStats *models.StatsResult,
 ) {
-	ns := buildStats(startTime, cfg, failedStep, runErr, backupStats, forgetStats)
+	if cfg.DryRun {
+		s.logger.Info().Msg("metrics file skipped (dry-run)")
+		return
+	}
+
+	msg := buildTelegramMessage(buildStats(startTime, cfg, failedStep, runErr, backupStats, forgetStats), repoStats)
+	msg.Skipped = skipped
+	if err := s.metricsSvc.Write(cfg.MetricsFile, msg); err != nil {
+		s.logger.Error().Err(err).Str("path", cfg.MetricsFile).Msg("failed to write metrics file")
+	}
+}
 
-	msg := models.PushoverMessage{
+// buildRunSummary converts collected stats into the summary of the run.
+func buildRunSummary(ns notificationStats) models.RunSummary {
+	return models.RunSummary{
 		Success:          ns.success,
+		DryRun:           ns.dryRun,
+		FailedStep:       ns.failedStep,
+		Duration:         ns.duration,
+		SnapshotID:       ns.snapshotID,
+		FilesNew:         ns.filesNew,
+		DataAdded:        ns.dataAdded,
+		SnapshotsKept:    ns.snapshotsKept,
+		SnapshotsRemoved: ns.snapshotsRemoved,
+		ErrorMessage:     ns.errorMessage,
+	}
+}
+
+// buildTelegramMessage converts collected stats into a Telegram message.
+func buildTelegramMessage(ns notificationStats, repoStats *models.StatsResult) models.TelegramMessage {
+	msg := models.TelegramMessage{
+		Success:          ns.success,
+		DryRun:           ns.dryRun,
 		Host:             ns.host,
 		Repository:       ns.repository,
 		StartTime:        ns.startTime,
@@ -403,14 +1127,15 @@ func (s *Impl) sendPushoverNotification(
 		TotalBytes:       ns.totalBytes,
 		SnapshotsKept:    ns.snapshotsKept,
 		SnapshotsRemoved: ns.snapshotsRemoved,
-	}
+		SpaceFreed:       ns.spaceFreed,
+		UnreadableFiles:  ns.unreadableFiles,
 
-	result, err := s.pushoverSvc.SendNotification(ctx, *cfg.Pushover, msg)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("failed to send Pushover notification")
-		return
+		ThroughputBytesPerSec: ns.throughput,
 	}
-	if result.Error != nil {
-		s.logger.Error().Err(result.Error).Msg("failed to send Pushover notification")
+	if repoStats != nil {
+		msg.RepoTotalSize = repoStats.TotalSize
+		msg.RepoFileCount = repoStats.TotalFileCount
+		msg.SnapshotCount = repoStats.SnapshotCount
 	}
+	return msg
 }
@@ -1,18 +1,36 @@
 package runner
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
+	discordmocks "github.com/fgeck/gorestic-homelab/internal/services/discord/mocks"
+	emailmocks "github.com/fgeck/gorestic-homelab/internal/services/email/mocks"
+	healthcheckmocks "github.com/fgeck/gorestic-homelab/internal/services/healthcheck/mocks"
+	hooksmocks "github.com/fgeck/gorestic-homelab/internal/services/hooks/mocks"
+	metricsmocks "github.com/fgeck/gorestic-homelab/internal/services/metrics/mocks"
+	mysqlmocks "github.com/fgeck/gorestic-homelab/internal/services/mysql/mocks"
+	ntfymocks "github.com/fgeck/gorestic-homelab/internal/services/ntfy/mocks"
 	postgresmocks "github.com/fgeck/gorestic-homelab/internal/services/postgres/mocks"
 	pushovermocks "github.com/fgeck/gorestic-homelab/internal/services/pushover/mocks"
+	"github.com/fgeck/gorestic-homelab/internal/services/restic"
 	resticmocks "github.com/fgeck/gorestic-homelab/internal/services/restic/mocks"
+	slackmocks "github.com/fgeck/gorestic-homelab/internal/services/slack/mocks"
+	sqlitemocks "github.com/fgeck/gorestic-homelab/internal/services/sqlite/mocks"
 	sshmocks "github.com/fgeck/gorestic-homelab/internal/services/ssh/mocks"
 	telegrammocks "github.com/fgeck/gorestic-homelab/internal/services/telegram/mocks"
+	webhookmocks "github.com/fgeck/gorestic-homelab/internal/services/webhook/mocks"
 	wolmocks "github.com/fgeck/gorestic-homelab/internal/services/wol/mocks"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -42,63 +60,110 @@ func minimalConfig() models.BackupConfig {
 	}
 }
 
+// testMocks holds a mock for every service the runner uses. Mocks without
+// expectations fail the test when called.
+type testMocks struct {
+	t        *testing.T
+	restic   *resticmocks.MockService
+	wol      *wolmocks.MockService
+	postgres *postgresmocks.MockService
+	mysql    *mysqlmocks.MockService
+	sqlite   *sqlitemocks.MockService
+	ssh      *sshmocks.MockService
+	hooks    *hooksmocks.MockService
+	metrics  *metricsmocks.MockService
+	health   *healthcheckmocks.MockService
+	telegram *telegrammocks.MockService
+	pushover *pushovermocks.MockService
+	webhook  *webhookmocks.MockService
+	discord  *discordmocks.MockService
+	slack    *slackmocks.MockService
+	ntfy     *ntfymocks.MockService
+	email    *emailmocks.MockService
+}
+
+func newTestMocks(t *testing.T) *testMocks {
+	return &testMocks{
+		t:        t,
+		restic:   resticmocks.NewMockService(t),
+		wol:      wolmocks.NewMockService(t),
+		postgres: postgresmocks.NewMockService(t),
+		mysql:    mysqlmocks.NewMockService(t),
+		sqlite:   sqlitemocks.NewMockService(t),
+		ssh:      sshmocks.NewMockService(t),
+		hooks:    hooksmocks.NewMockService(t),
+		metrics:  metricsmocks.NewMockService(t),
+		health:   healthcheckmocks.NewMockService(t),
+		telegram: telegrammocks.NewMockService(t),
+		pushover: pushovermocks.NewMockService(t),
+		webhook:  webhookmocks.NewMockService(t),
+		discord:  discordmocks.NewMockService(t),
+		slack:    slackmocks.NewMockService(t),
+		ntfy:     ntfymocks.NewMockService(t),
+		email:    emailmocks.NewMockService(t),
+	}
+}
+
+// services returns the mocks as runner services, notifying the channels
+// configured in cfg.
+func (m *testMocks) services(cfg models.BackupConfig) Services {
+	return Services{
+		Restic:      m.restic,
+		WOL:         m.wol,
+		Postgres:    m.postgres,
+		MySQL:       m.mysql,
+		SQLite:      m.sqlite,
+		SSH:         m.ssh,
+		Hooks:       m.hooks,
+		Metrics:     m.metrics,
+		Healthcheck: m.health,
+		Notifiers: configuredNotifiers(notificationServices{
+			telegram: m.telegram,
+			pushover: m.pushover,
+			webhook:  m.webhook,
+			discord:  m.discord,
+			slack:    m.slack,
+			ntfy:     m.ntfy,
+			email:    m.email,
+		}, cfg),
+	}
+}
+
+// runner returns a runner using the mocks, notifying the channels configured in cfg.
+func (m *testMocks) runner(cfg models.BackupConfig) *Impl {
+	return NewWithServices(testLogger(), m.services(cfg), m.t.TempDir())
+}
+
 func TestRun_Success_MinimalConfig(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Set up expectations for minimal config
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test123"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
-	err := runner.Run(context.Background(), minimalConfig())
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test123"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
 
 	assert.NoError(t, err)
 }
 
 func TestRun_WithWOL(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// WOL should be called
-	wolSvc.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{PacketSent: true, TargetReady: true}, nil)
+	mocks.wol.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{PacketSent: true, TargetReady: true}, nil)
 
 	// Standard restic operations
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
 
 	cfg := minimalConfig()
 	cfg.WOL = &models.WOLConfig{
@@ -108,38 +173,27 @@ func TestRun_WithWOL(t *testing.T) {
 		PollInterval: 10 * time.Second,
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.NoError(t, err)
 }
 
 func TestRun_WOLFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// WOL fails
-	wolSvc.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{Error: errors.New("timeout")}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.wol.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{Error: errors.New("timeout")}, nil)
 
 	cfg := minimalConfig()
 	cfg.WOL = &models.WOLConfig{
 		MACAddress: "AA:BB:CC:DD:EE:FF",
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
@@ -147,36 +201,23 @@ func TestRun_WOLFailure(t *testing.T) {
 }
 
 func TestRun_WithPostgres(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	var capturedPaths []string
 
 	// Postgres dump should be called
-	postgresSvc.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{OutputPath: "/tmp/dump.sql", SizeBytes: 1024}, nil)
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{OutputPath: "/tmp/dump.sql", SizeBytes: 1024}, nil)
 
 	// Standard restic operations
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings) {
-		capturedPaths = settings.Paths
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedPaths = append(settings.Paths, settings.DumpPaths...)
 	}).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
 
 	cfg := minimalConfig()
 	cfg.Postgres = &models.PostgresConfig{
@@ -187,6 +228,8 @@ func TestRun_WithPostgres(t *testing.T) {
 		Format:   "custom",
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.NoError(t, err)
@@ -194,35 +237,322 @@ func TestRun_WithPostgres(t *testing.T) {
 	assert.Len(t, capturedPaths, 2)
 }
 
+func TestRun_PostgresKeepLocal(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	tempDir := t.TempDir()
+	localDir := t.TempDir()
+	for _, name := range []string{"testdb-20200101-030000.dump", "testdb-20200102-030000.dump"} {
+		require.NoError(t, os.WriteFile(filepath.Join(localDir, name), []byte("old"), 0o600))
+	}
+
+	var dumpName string
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+			dumpName = filepath.Base(outputPath)
+			require.NoError(t, os.WriteFile(outputPath, []byte("new"), 0o600))
+			return &models.PostgresDumpResult{OutputPath: outputPath}, nil
+		})
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Postgres = &models.PostgresConfig{
+		Database:  "testdb",
+		Format:    "custom",
+		KeepLocal: 2,
+		LocalDir:  localDir,
+	}
+
+	runner := NewWithServices(testLogger(), mocks.services(cfg), tempDir)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	// The new dump was moved, not deleted, and the oldest one was rotated out
+	assert.NoFileExists(t, filepath.Join(tempDir, dumpName))
+	assert.FileExists(t, filepath.Join(localDir, dumpName))
+	assert.FileExists(t, filepath.Join(localDir, "testdb-20200102-030000.dump"))
+	assert.NoFileExists(t, filepath.Join(localDir, "testdb-20200101-030000.dump"))
+}
+
+func TestRun_WithMySQL(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedPaths []string
+
+	mocks.mysql.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.MySQLConfig, outputPath string) (*models.MySQLDumpResult, error) {
+		assert.Equal(t, "appdb", cfg.Database)
+		return &models.MySQLDumpResult{OutputPath: outputPath}, nil
+	})
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedPaths = append(settings.Paths, settings.DumpPaths...)
+	}).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.MySQL = &models.MySQLConfig{
+		Host:     "localhost",
+		Port:     3306,
+		Database: "appdb",
+		Username: "root",
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, capturedPaths, 2)
+	assert.Equal(t, "/data", capturedPaths[0])
+	assert.Contains(t, capturedPaths[1], "mysql-appdb-")
+}
+
+func TestRun_MySQLDumpFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.mysql.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.MySQLDumpResult{Error: errors.New("access denied")}, nil)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.MySQL = &models.MySQLConfig{Host: "localhost", Port: 3306, Database: "appdb", Username: "root"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "MySQL dump failed")
+	assert.Contains(t, err.Error(), "access denied")
+}
+
+func TestRun_WithSQLite(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	tempDir := t.TempDir()
+	var capturedPaths []string
+	var outputDir string
+
+	mocks.sqlite.EXPECT().Backup(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.SQLiteConfig) (*models.SQLiteBackupResult, error) {
+		// Output dir falls back to the per-run directory in the runner's temp dir
+		outputDir = cfg.OutputDir
+		assert.Equal(t, tempDir, filepath.Dir(cfg.OutputDir))
+		return &models.SQLiteBackupResult{OutputPaths: []string{filepath.Join(cfg.OutputDir, "srv_app_data.db")}}, nil
+	})
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedPaths = append(settings.Paths, settings.DumpPaths...)
+	}).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.SQLite = &models.SQLiteConfig{Databases: []string{"/srv/app/data.db"}}
+
+	runner := NewWithServices(testLogger(), mocks.services(cfg), tempDir)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/data", filepath.Join(outputDir, "srv_app_data.db")}, capturedPaths)
+}
+
+func TestRun_SQLiteBackupFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.sqlite.EXPECT().Backup(mock.Anything, mock.Anything).Return(&models.SQLiteBackupResult{Error: errors.New("database is locked")}, nil)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.SQLite = &models.SQLiteConfig{Databases: []string{"/srv/app/data.db"}, OutputDir: "/var/tmp/sqlite"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "SQLite backup failed")
+	assert.Contains(t, err.Error(), "database is locked")
+}
+
+func TestRun_WithMultiplePostgresDatabases(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedPaths []string
+	var dumpedDatabases []string
+
+	// One dump per database
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+		dumpedDatabases = append(dumpedDatabases, cfg.Database)
+		return &models.PostgresDumpResult{OutputPath: outputPath}, nil
+	}).Times(2)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedPaths = append(settings.Paths, settings.DumpPaths...)
+	}).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+
+	tempDir := t.TempDir()
+
+	cfg := minimalConfig()
+	cfg.Postgres = &models.PostgresConfig{
+		Host:      "localhost",
+		Port:      5432,
+		Databases: []string{"nextcloud", "immich"},
+		Username:  "postgres",
+		Format:    "custom",
+	}
+
+	runner := NewWithServices(testLogger(), mocks.services(cfg), tempDir)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nextcloud", "immich"}, dumpedDatabases)
+	require.Len(t, capturedPaths, 3)
+	assert.Equal(t, "/data", capturedPaths[0])
+	assert.Equal(t, tempDir, filepath.Dir(filepath.Dir(capturedPaths[1])))
+	assert.Contains(t, filepath.Base(capturedPaths[1]), "nextcloud-")
+	assert.Contains(t, filepath.Base(capturedPaths[2]), "immich-")
+}
+
+func TestRunPostgresDump_Parallel(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var mu sync.Mutex
+	var active, maxActive int
+	var dumped []string
+
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+		mu.Lock()
+		active++
+		maxActive = max(maxActive, active)
+		dumped = append(dumped, filepath.Base(outputPath))
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		active--
+		mu.Unlock()
+
+		if cfg.Database == "immich" {
+			return &models.PostgresDumpResult{Error: errors.New("connection refused")}, nil
+		}
+		return &models.PostgresDumpResult{OutputPath: outputPath}, nil
+	}).Times(3)
+
+	cfg := &models.PostgresConfig{
+		Host:        "localhost",
+		Port:        5432,
+		Databases:   []string{"nextcloud", "immich", "paperless"},
+		Username:    "postgres",
+		Format:      "custom",
+		Parallelism: 2,
+	}
+	runDir := t.TempDir()
+
+	runner := mocks.runner(minimalConfig())
+
+	paths, err := runner.runPostgresDump(context.Background(), cfg, runDir)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PostgreSQL dump failed for immich: connection refused")
+	assert.NotContains(t, err.Error(), "nextcloud")
+	assert.Equal(t, 2, maxActive, "at most two dumps run at once")
+	require.Len(t, dumped, 3)
+	require.Len(t, paths, 2, "successful dumps are returned despite the failure")
+	assert.Equal(t, runDir, filepath.Dir(paths[0]))
+	assert.Contains(t, filepath.Base(paths[0]), "nextcloud-")
+	assert.Contains(t, filepath.Base(paths[1]), "paperless-")
+}
+
+func TestRun_WithPostgresGlobals(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedPaths []string
+	var globalsPath string
+	tempDir := t.TempDir()
+
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{OutputPath: "/tmp/dump.sql"}, nil)
+	mocks.postgres.EXPECT().DumpGlobals(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+		globalsPath = outputPath
+		return &models.PostgresDumpResult{OutputPath: outputPath}, nil
+	})
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedPaths = append(settings.Paths, settings.DumpPaths...)
+	}).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+
+	cfg := minimalConfig()
+	cfg.Postgres = &models.PostgresConfig{
+		Host:        "localhost",
+		Port:        5432,
+		Database:    "testdb",
+		Username:    "postgres",
+		Format:      "custom",
+		DumpGlobals: true,
+	}
+
+	runner := NewWithServices(testLogger(), mocks.services(cfg), tempDir)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/data", "/tmp/dump.sql", globalsPath}, capturedPaths)
+	assert.Equal(t, "globals.sql", filepath.Base(globalsPath))
+	assert.Equal(t, tempDir, filepath.Dir(filepath.Dir(globalsPath)))
+}
+
 func TestRun_PostgresDumpFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Init and unlock succeed, but postgres dump fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	postgresSvc.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{Error: errors.New("connection refused")}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{Error: errors.New("connection refused")}, nil)
 
 	cfg := minimalConfig()
 	cfg.Postgres = &models.PostgresConfig{
 		Database: "testdb",
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
@@ -230,91 +560,319 @@ func TestRun_PostgresDumpFailure(t *testing.T) {
 }
 
 func TestRun_BackupFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Init and unlock succeed, backup fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("disk full")}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
-	err := runner.Run(context.Background(), minimalConfig())
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("disk full")}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "backup failed")
 }
 
+func TestRun_BackupProgressFile(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	progressFile := filepath.Join(t.TempDir(), "progress.json")
+
+	readProgress := func() models.BackupProgress {
+		data, err := os.ReadFile(progressFile)
+		require.NoError(t, err)
+		var progress models.BackupProgress
+		require.NoError(t, json.Unmarshal(data, &progress))
+		return progress
+	}
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(_ context.Context, _ models.ResticConfig, _ models.BackupSettings, progressCb models.ResticProgressCallback) (*models.BackupResult, error) {
+			require.NotNil(t, progressCb)
+
+			progressCb(models.BackupProgress{MessageType: "status", PercentDone: 0.25, FilesDone: 150, TotalFiles: 600})
+			assert.Equal(t, uint64(150), readProgress().FilesDone)
+
+			// Updates within a second of the last write are skipped
+			progressCb(models.BackupProgress{MessageType: "status", PercentDone: 0.3, FilesDone: 180, TotalFiles: 600})
+			assert.Equal(t, uint64(150), readProgress().FilesDone)
+
+			progressCb(models.BackupProgress{MessageType: "status", PercentDone: 1, FilesDone: 600, TotalFiles: 600})
+			return &models.BackupResult{SnapshotID: "abc123"}, nil
+		})
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.ProgressFile = progressFile
+
+	runner := mocks.runner(cfg)
+	runner.now = func() time.Time { return time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC) }
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	progress := readProgress()
+	assert.InDelta(t, 1.0, progress.PercentDone, 0.001)
+	assert.Equal(t, uint64(600), progress.FilesDone)
+
+	leftovers, err := filepath.Glob(progressFile + ".*.tmp")
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestRun_BackupRetriesNetworkErrors(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	networkErr := &models.BackupResult{Error: errors.New("backup failed: exit status 1, output: Fatal: unable to open repository: dial tcp 192.168.1.100:8000: connect: connection refused")}
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(networkErr, nil).Twice()
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "abc123"}, nil).Once()
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.Retries = 3
+	cfg.Backup.RetryDelay = time.Millisecond
+
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", summary.SnapshotID)
+}
+
+func TestRun_BackupRetriesExhausted(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("read tcp: i/o timeout")).Times(3)
+
+	cfg := minimalConfig()
+	cfg.Backup.Retries = 2
+	cfg.Backup.RetryDelay = time.Millisecond
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "i/o timeout")
+}
+
+func TestRun_BackupDoesNotRetryPermanentErrors(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("write /tmp/restic: no space left on device")}, nil).Once()
+
+	cfg := minimalConfig()
+	cfg.Backup.Retries = 3
+	cfg.Backup.RetryDelay = time.Millisecond
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no space left on device")
+}
+
+func TestRun_BackupRetryHonorsCancellation(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(context.Context, models.ResticConfig, models.BackupSettings, models.ResticProgressCallback) (*models.BackupResult, error) {
+			cancel()
+			return &models.BackupResult{Error: errors.New("connection reset by peer")}, nil
+		}).Once()
+
+	cfg := minimalConfig()
+	cfg.Backup.Retries = 3
+	cfg.Backup.RetryDelay = time.Hour
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(ctx, cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "connection reset by peer")
+}
+
+func TestIsRetryableBackupError(t *testing.T) {
+	tests := []struct {
+		err       string
+		retryable bool
+	}{
+		{"dial tcp 192.168.1.100:8000: connect: connection refused", true},
+		{"read tcp 10.0.0.2:51234->10.0.0.1:8000: read: connection reset by peer", true},
+		{"Post \"http://backup.lan:8000/data\": net/http: TLS handshake timeout", true},
+		{"server response unexpected: 503 Service Unavailable (503)", true},
+		{"write /srv/restic/data: no space left on device", false},
+		{"Fatal: wrong password or no key found", false},
+		{"exit status 1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, isRetryableBackupError(errors.New(tt.err)))
+		})
+	}
+}
+
 func TestRun_ForgetFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Backup succeeds, forget fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{Error: errors.New("prune failed")}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
-	err := runner.Run(context.Background(), minimalConfig())
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{Error: errors.New("prune failed")}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "forget failed")
 }
 
+func TestRun_WithPrune(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedSettings models.PruneSettings
+	forgetResult := &models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(forgetResult, nil)
+	mocks.restic.EXPECT().Prune(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.PruneSettings) {
+		capturedSettings = settings
+	}).Return(&models.PruneResult{SpaceFreed: 4096}, nil)
+
+	cfg := minimalConfig()
+	cfg.Retention.Prune = models.PruneSettings{Enabled: true, MaxUnused: "5%"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "5%", capturedSettings.MaxUnused)
+	assert.Equal(t, int64(4096), forgetResult.SpaceFreed, "space freed by prune is reported with the retention stats")
+}
+
+func TestRun_StatsAfterPrune(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var written models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+	prune := mocks.restic.EXPECT().Prune(mock.Anything, mock.Anything, mock.Anything).Return(&models.PruneResult{SpaceFreed: 4096}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{TotalSize: 1 << 30}, nil).NotBefore(prune.Call)
+	mocks.metrics.EXPECT().Write("/tmp/gorestic.prom", mock.Anything).Run(func(path string, msg models.TelegramMessage) {
+		written = msg
+	}).Return(nil)
+
+	// Only a metrics file, no notifier
+	cfg := minimalConfig()
+	cfg.Retention.Prune = models.PruneSettings{Enabled: true}
+	cfg.MetricsFile = "/tmp/gorestic.prom"
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), written.RepoTotalSize)
+}
+
+func TestRun_SnapshotCountAfterForgetAndPrune(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	forget := mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 12, SnapshotsRemoved: 3}, nil)
+	prune := mocks.restic.EXPECT().Prune(mock.Anything, mock.Anything, mock.Anything).Return(&models.PruneResult{}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{TotalSize: 1 << 30, SnapshotCount: 12}, nil).NotBefore(forget.Call, prune.Call)
+	mocks.discord.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.DiscordConfig, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(&models.DiscordResult{MessageSent: true}, nil)
+
+	// Neither Telegram nor a webhook is configured
+	cfg := minimalConfig()
+	cfg.Retention.Prune = models.PruneSettings{Enabled: true}
+	cfg.Discord = &models.DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/x"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, capturedMsg.SnapshotCount)
+}
+
+func TestRun_PruneFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+	mocks.restic.EXPECT().Prune(mock.Anything, mock.Anything, mock.Anything).Return(&models.PruneResult{Error: errors.New("repository locked")}, nil)
+
+	cfg := minimalConfig()
+	cfg.Retention.Prune = models.PruneSettings{Enabled: true}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "prune failed")
+}
+
 func TestRun_WithCheck(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// All operations succeed including check
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-	resticSvc.EXPECT().Check(mock.Anything, mock.Anything, mock.Anything).Return(&models.CheckResult{Passed: true}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().Check(mock.Anything, mock.Anything, mock.Anything).Return(&models.CheckResult{Passed: true}, nil)
 
 	cfg := minimalConfig()
 	cfg.Check = models.CheckSettings{
@@ -322,40 +880,29 @@ func TestRun_WithCheck(t *testing.T) {
 		Subset:  "5%",
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.NoError(t, err)
 }
 
 func TestRun_CheckFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Backup and forget succeed, check fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-	resticSvc.EXPECT().Check(mock.Anything, mock.Anything, mock.Anything).Return(&models.CheckResult{Passed: false, Error: errors.New("corruption detected")}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().Check(mock.Anything, mock.Anything, mock.Anything).Return(&models.CheckResult{Passed: false, Error: errors.New("corruption detected")}, nil)
 
 	cfg := minimalConfig()
 	cfg.Check = models.CheckSettings{Enabled: true}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
@@ -363,30 +910,15 @@ func TestRun_CheckFailure(t *testing.T) {
 }
 
 func TestRun_WithSSHShutdown(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// All operations succeed including SSH shutdown
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-	sshSvc.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: true}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.ssh.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: true}, nil)
 
 	cfg := minimalConfig()
 	cfg.SSHShutdown = &models.SSHShutdownConfig{
@@ -397,36 +929,23 @@ func TestRun_WithSSHShutdown(t *testing.T) {
 		ShutdownDelay: 1,
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.NoError(t, err)
 }
 
 func TestRun_SSHShutdownFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Backup succeeds, SSH shutdown fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
-	sshSvc.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: false, Error: errors.New("connection refused")}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.ssh.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: false, Error: errors.New("connection refused")}, nil)
 
 	cfg := minimalConfig()
 	cfg.SSHShutdown = &models.SSHShutdownConfig{
@@ -434,6 +953,8 @@ func TestRun_SSHShutdownFailure(t *testing.T) {
 		PrivateKey: []byte("test-key"),
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	// SSH shutdown runs in defer, failure is returned even if backup succeeded
@@ -443,34 +964,19 @@ func TestRun_SSHShutdownFailure(t *testing.T) {
 
 func TestRun_SSHShutdownRunsOnBackupFailure(t *testing.T) {
 	// When WOL succeeds but backup fails, SSH shutdown should still run
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// WOL succeeds
-	wolSvc.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{PacketSent: true, TargetReady: true}, nil)
+	mocks.wol.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{PacketSent: true, TargetReady: true}, nil)
 
 	// Backup fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
 
 	// SSH shutdown should still be called (deferred)
-	sshSvc.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: true}, nil)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	mocks.ssh.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: true}, nil)
 
 	cfg := minimalConfig()
 	cfg.WOL = &models.WOLConfig{
@@ -482,6 +988,8 @@ func TestRun_SSHShutdownRunsOnBackupFailure(t *testing.T) {
 		PrivateKey: []byte("test-key"),
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	// Backup failed, but SSH shutdown should still have been called
@@ -491,29 +999,14 @@ func TestRun_SSHShutdownRunsOnBackupFailure(t *testing.T) {
 
 func TestRun_SSHShutdownSkippedWhenWOLFails(t *testing.T) {
 	// When WOL fails, SSH shutdown should NOT run (machine wasn't woken up)
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// WOL fails
-	wolSvc.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{Error: errors.New("WOL failed")}, nil)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.wol.EXPECT().Wake(mock.Anything, mock.Anything).Return(&models.WOLResult{Error: errors.New("WOL failed")}, nil)
 
 	// SSH shutdown should NOT be called because WOL failed
 
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
 	cfg := minimalConfig()
 	cfg.WOL = &models.WOLConfig{
 		MACAddress:  "00:11:22:33:44:55",
@@ -524,6 +1017,8 @@ func TestRun_SSHShutdownSkippedWhenWOLFails(t *testing.T) {
 		PrivateKey: []byte("test-key"),
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	// WOL failed, SSH shutdown should NOT be called
@@ -532,88 +1027,73 @@ func TestRun_SSHShutdownSkippedWhenWOLFails(t *testing.T) {
 }
 
 func TestRun_WithTelegram_Success(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	var capturedMsg models.TelegramMessage
 
 	// Standard operations succeed
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
+		SnapshotID:          "test",
+		TotalBytesProcessed: 100 * 1024 * 1024,
+		Duration:            10 * time.Second,
+		Errors:              []string{"/data/a: permission denied", "/data/b: permission denied"},
+	}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{TotalSize: 5 * 1024 * 1024 * 1024, TotalFileCount: 1200, SnapshotCount: 12}, nil)
 
 	// Telegram notification should be sent
-	telegramSvc.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
 		capturedMsg = msg
 	}).Return(&models.TelegramResult{MessageSent: true}, nil)
 
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
 	cfg := minimalConfig()
 	cfg.Telegram = &models.TelegramConfig{
 		BotToken: "123456:ABC",
-		ChatID:   "-100123",
+		ChatIDs:  []string{"-100123"},
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	require.NoError(t, err)
 	assert.True(t, capturedMsg.Success)
 	assert.Equal(t, "testhost", capturedMsg.Host)
 	assert.Equal(t, "/backup", capturedMsg.Repository)
+	assert.Equal(t, int64(5*1024*1024*1024), capturedMsg.RepoTotalSize)
+	assert.Equal(t, 1200, capturedMsg.RepoFileCount)
+	assert.Equal(t, 12, capturedMsg.SnapshotCount)
+	assert.Equal(t, 2, capturedMsg.UnreadableFiles)
+	assert.Equal(t, int64(10*1024*1024), capturedMsg.ThroughputBytesPerSec)
 }
 
 func TestRun_WithTelegram_Failure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	var capturedMsg models.TelegramMessage
 
 	// Backup fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
 
 	// Telegram notification should still be sent (with failure info)
-	telegramSvc.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
 		capturedMsg = msg
 	}).Return(&models.TelegramResult{MessageSent: true}, nil)
 
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
 	cfg := minimalConfig()
 	cfg.Telegram = &models.TelegramConfig{
 		BotToken: "123456:ABC",
-		ChatID:   "-100123",
+		ChatIDs:  []string{"-100123"},
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	require.Error(t, err)
@@ -622,60 +1102,581 @@ func TestRun_WithTelegram_Failure(t *testing.T) {
 	assert.Contains(t, capturedMsg.ErrorMessage, "backup failed")
 }
 
-func TestRun_ContextCancelled(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+func TestRun_NotifyOn(t *testing.T) {
+	tests := []struct {
+		name       string
+		notifyOn   string
+		backupErr  error
+		wantNotify bool
+	}{
+		{"always on success", models.NotifyAlways, nil, true},
+		{"always on failure", models.NotifyAlways, errors.New("backup failed"), true},
+		{"failure on success", models.NotifyFailure, nil, false},
+		{"failure on failure", models.NotifyFailure, errors.New("backup failed"), true},
+		{"success on success", models.NotifySuccess, nil, true},
+		{"success on failure", models.NotifySuccess, errors.New("backup failed"), false},
+	}
 
-	// Init returns context error
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(context.Canceled)
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test", Error: tt.backupErr}, nil)
+			if tt.backupErr == nil {
+				mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+				mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
+			}
+
+			// Notifiers without expectations fail the test when invoked
+			if tt.wantNotify {
+				mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Return(&models.TelegramResult{MessageSent: true}, nil)
+				mocks.slack.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Return(&models.SlackResult{MessageSent: true}, nil)
+			}
+
+			cfg := minimalConfig()
+			cfg.NotifyOn = tt.notifyOn
+			cfg.Telegram = &models.TelegramConfig{
+				BotToken: "123456:ABC",
+				ChatIDs:  []string{"-100123"},
+			}
+			cfg.Slack = &models.SlackConfig{WebhookURL: "https://hooks.slack.com/services/x"}
+
+			runner := mocks.runner(cfg)
+
+			_ = runner.Run(context.Background(), cfg)
+		})
+	}
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
+// fakeNotifier records the messages it is asked to send.
+type fakeNotifier struct {
+	err      error
+	messages []models.TelegramMessage
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, msg models.TelegramMessage) error {
+	f.messages = append(f.messages, msg)
+	return f.err
+}
+
+func TestRun_NotifiesAllNotifiers(t *testing.T) {
+	tests := []struct {
+		name      string
+		backupErr error
+	}{
+		{"success", nil},
+		{"failure", errors.New("backup failed")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test", Error: tt.backupErr}, nil)
+			if tt.backupErr == nil {
+				mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+				mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
+			}
+
+			// The first notifier fails, which must not keep the second from being called
+			first := &fakeNotifier{err: errors.New("unreachable")}
+			second := &fakeNotifier{}
+			services := mocks.services(minimalConfig())
+			services.Notifiers = []Notifier{first, second}
+			runner := NewWithServices(testLogger(), services, t.TempDir())
+
+			err := runner.Run(context.Background(), minimalConfig())
+
+			assert.Equal(t, tt.backupErr != nil, err != nil)
+			for _, n := range []*fakeNotifier{first, second} {
+				require.Len(t, n.messages, 1)
+				assert.Equal(t, tt.backupErr == nil, n.messages[0].Success)
+				if tt.backupErr != nil {
+					assert.Equal(t, "backup", n.messages[0].FailedStep)
+					assert.Contains(t, n.messages[0].ErrorMessage, "backup failed")
+				} else {
+					assert.Equal(t, "test", n.messages[0].SnapshotID)
+				}
+			}
+		})
+	}
+}
+
+func TestLogExcerpt(t *testing.T) {
+	output := `{"message_type":"status","percent_done":0.5}
+open repository
+{"message_type":"status","percent_done":0.9}
+Fatal: unable to save snapshot: no space left on device`
+	err := fmt.Errorf("backup failed: %w", &restic.Error{Err: errors.New("exit status 1"), Output: output})
+
+	assert.Equal(t, "open repository\nFatal: unable to save snapshot: no space left on device", logExcerpt(err, 20))
+	assert.Equal(t, "Fatal: unable to save snapshot: no space left on device", logExcerpt(err, 1))
+	assert.Empty(t, logExcerpt(errors.New("pg_dump failed"), 20))
+}
+
+func TestRun_AttachLog(t *testing.T) {
+	for _, attachLog := range []bool{true, false} {
+		t.Run(fmt.Sprintf("attach_log=%t", attachLog), func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			var capturedMsg models.TelegramMessage
+
+			backupErr := &restic.Error{Err: errors.New("backup failed: exit status 1"), Output: "Fatal: repository is damaged"}
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: backupErr}, nil)
+			mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+				capturedMsg = msg
+			}).Return(&models.TelegramResult{MessageSent: true}, nil)
+
+			cfg := minimalConfig()
+			cfg.AttachLog = attachLog
+			cfg.Telegram = &models.TelegramConfig{BotToken: "123456:ABC", ChatIDs: []string{"-100123"}}
+
+			runner := mocks.runner(cfg)
+
+			err := runner.Run(context.Background(), cfg)
+
+			require.Error(t, err)
+			if attachLog {
+				assert.Equal(t, "Fatal: repository is damaged", capturedMsg.LogExcerpt)
+			} else {
+				assert.Empty(t, capturedMsg.LogExcerpt)
+			}
+		})
+	}
+}
+
+func TestRun_WithTelegramAndWebhook(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var webhookCfg models.WebhookConfig
+	var webhookMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test", FilesNew: 3}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{TotalSize: 2048}, nil)
+
+	// Both notifiers fire
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Return(&models.TelegramResult{MessageSent: true}, nil)
+	mocks.webhook.EXPECT().Notify(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.WebhookConfig, msg models.TelegramMessage) {
+		webhookCfg = cfg
+		webhookMsg = msg
+	}).Return(&models.WebhookResult{Sent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Telegram = &models.TelegramConfig{
+		BotToken: "123456:ABC",
+		ChatIDs:  []string{"-100123"},
+	}
+	cfg.Webhook = &models.WebhookConfig{URL: "https://example.com/hook", Method: "POST"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", webhookCfg.URL)
+	assert.True(t, webhookMsg.Success)
+	assert.Equal(t, "test", webhookMsg.SnapshotID)
+	assert.Equal(t, 3, webhookMsg.FilesNew)
+	assert.Equal(t, int64(2048), webhookMsg.RepoTotalSize)
+}
+
+func TestRun_WithDiscord_Failure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil, errors.New("repository is locked"))
+
+	mocks.discord.EXPECT().SendNotification(mock.Anything, models.DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/1/x"}, mock.Anything).Run(func(ctx context.Context, cfg models.DiscordConfig, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(&models.DiscordResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Discord = &models.DiscordConfig{WebhookURL: "https://discord.com/api/webhooks/1/x"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.False(t, capturedMsg.Success)
+	assert.Equal(t, "unlock", capturedMsg.FailedStep)
+	assert.Contains(t, capturedMsg.ErrorMessage, "repository is locked")
+}
+
+func TestRun_WithSlack_Success(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{TotalSize: 4096}, nil)
+
+	mocks.slack.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.SlackConfig, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(&models.SlackResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Slack = &models.SlackConfig{WebhookURL: "https://hooks.slack.com/services/x"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, capturedMsg.Success)
+	assert.Equal(t, "test", capturedMsg.SnapshotID)
+	assert.Equal(t, 2, capturedMsg.SnapshotsRemoved)
+	assert.Equal(t, int64(4096), capturedMsg.RepoTotalSize)
+}
+
+func TestRun_WithNtfy_Failure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedCfg models.NtfyConfig
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
+
+	mocks.ntfy.EXPECT().Notify(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.NtfyConfig, msg models.TelegramMessage) {
+		capturedCfg = cfg
+		capturedMsg = msg
+	}).Return(&models.NtfyResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Ntfy = &models.NtfyConfig{Server: "https://ntfy.sh", Topic: "backups"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Equal(t, "backups", capturedCfg.Topic)
+	assert.False(t, capturedMsg.Success)
+	assert.Equal(t, "backup", capturedMsg.FailedStep)
+}
+
+func TestRun_WithEmail_Success(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedCfg models.EmailConfig
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
+
+	mocks.email.EXPECT().Send(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.EmailConfig, msg models.TelegramMessage) {
+		capturedCfg = cfg
+		capturedMsg = msg
+	}).Return(&models.EmailResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Email = &models.EmailConfig{
+		SMTPHost: "smtp.example.com",
+		SMTPPort: 587,
+		From:     "backup@example.com",
+		To:       []string{"admin@example.com"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin@example.com"}, capturedCfg.To)
+	assert.True(t, capturedMsg.Success)
+	assert.Equal(t, "test", capturedMsg.SnapshotID)
+}
+
+func TestRun_DryRun_SkipsWOLInitUnlockAndShutdown(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedResticCfg models.ResticConfig
+	var capturedMsg models.TelegramMessage
+
+	// WOL and SSH mocks, Init and Unlock have no expectations: any call fails the test
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedResticCfg = cfg
+	}).Return(&models.BackupResult{}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(&models.TelegramResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.DryRun = true
+	cfg.WOL = &models.WOLConfig{MACAddress: "AA:BB:CC:DD:EE:FF"}
+	cfg.SSHShutdown = &models.SSHShutdownConfig{
+		Host:       "192.168.1.100",
+		PrivateKey: []byte("test-key"),
+	}
+	cfg.Telegram = &models.TelegramConfig{
+		BotToken: "123456:ABC",
+		ChatIDs:  []string{"-100123"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, capturedResticCfg.DryRun)
+	assert.True(t, capturedMsg.DryRun)
+}
+
+func TestRun_DryRun_LogsSkippedShutdownWithWOL(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.DryRun = true
+	cfg.WOL = &models.WOLConfig{MACAddress: "AA:BB:CC:DD:EE:FF"}
+	cfg.SSHShutdown = &models.SSHShutdownConfig{Host: "192.168.1.100", PrivateKey: []byte("test-key")}
+
+	var logs bytes.Buffer
+	runner := NewWithServices(zerolog.New(&logs), mocks.services(cfg), t.TempDir())
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, logs.String(), "WOL skipped (dry-run)")
+	assert.Contains(t, logs.String(), "SSH shutdown skipped (dry-run)")
+}
+
+func TestRun_PreHookFailureAbortsRun(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	// Backup should NOT be called
+
+	mocks.hooks.EXPECT().Run(mock.Anything, "docker stop app").Return(&models.HookResult{Error: errors.New("exit status 1")}, nil)
+	// Post-hooks still run after a failed pre-hook
+	mocks.hooks.EXPECT().Run(mock.Anything, "docker start app").Return(&models.HookResult{}, nil)
+
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(&models.TelegramResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.PreHooks = []string{"docker stop app"}
+	cfg.PostHooks = []string{"docker start app"}
+	cfg.Telegram = &models.TelegramConfig{
+		BotToken: "123456:ABC",
+		ChatIDs:  []string{"-100123"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "pre-hook failed")
+	assert.Equal(t, "pre_hook", capturedMsg.FailedStep)
+}
+
+func TestRun_PostHookRunsOnBackupFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
+
+	mocks.hooks.EXPECT().Run(mock.Anything, "docker start app").Return(&models.HookResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.PostHooks = []string{"docker start app"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "backup")
+}
+
+func TestRun_PostHookFailureDoesNotFailRun(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+
+	mocks.hooks.EXPECT().Run(mock.Anything, "docker start app").Return(&models.HookResult{Error: errors.New("exit status 1")}, nil)
+
+	cfg := minimalConfig()
+	cfg.PostHooks = []string{"docker start app"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.NoError(t, err)
+}
+
+func TestRun_ConcurrentRunFailsFast(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	cfg := minimalConfig()
+	cfg.LockFile = filepath.Join(t.TempDir(), "run.lock")
+	cfg.PreHooks = []string{"true"}
+
+	// Only the first run gets past the lock
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil).Once()
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil).Once()
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil).Once()
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil).Once()
+
+	runner := mocks.runner(cfg)
+
+	// Start a second run while the first one holds the lock
+	var secondErr error
+	mocks.hooks.EXPECT().Run(mock.Anything, "true").RunAndReturn(func(ctx context.Context, command string) (*models.HookResult, error) {
+		secondErr = runner.Run(ctx, cfg)
+		return &models.HookResult{Command: command}, nil
+	})
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Error(t, secondErr)
+	assert.Contains(t, secondErr.Error(), "another backup run is in progress")
+}
+
+func TestRun_WritesMetricsOnFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
+
+	mocks.metrics.EXPECT().Write("/var/lib/node_exporter/gorestic.prom", mock.Anything).Run(func(path string, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(nil)
+
+	cfg := minimalConfig()
+	cfg.MetricsFile = "/var/lib/node_exporter/gorestic.prom"
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.False(t, capturedMsg.Success)
+	assert.Equal(t, "backup", capturedMsg.FailedStep)
+	assert.Equal(t, "testhost", capturedMsg.Host)
+}
+
+func TestRun_HealthcheckPings(t *testing.T) {
+	tests := []struct {
+		name      string
+		backupErr error
+		final     models.HealthcheckStatus
+	}{
+		{"success", nil, models.HealthcheckSuccess},
+		{"failure", errors.New("backup failed"), models.HealthcheckFail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test", Error: tt.backupErr}, nil)
+			if tt.backupErr == nil {
+				mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+			}
+
+			var pings []models.HealthcheckStatus
+			mocks.health.EXPECT().Ping(mock.Anything, "https://hc-ping.com/uuid", mock.Anything).Run(func(ctx context.Context, pingURL string, status models.HealthcheckStatus) {
+				pings = append(pings, status)
+			}).Return(&models.HealthcheckResult{Pinged: true}, nil)
+
+			cfg := minimalConfig()
+			cfg.Healthcheck = &models.HealthcheckConfig{PingURL: "https://hc-ping.com/uuid"}
+
+			runner := mocks.runner(cfg)
+
+			_ = runner.Run(context.Background(), cfg)
+
+			assert.Equal(t, []models.HealthcheckStatus{models.HealthcheckStart, tt.final}, pings)
+		})
+	}
+}
+
+func TestRun_ContextCancelled(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	// Init returns context error
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(nil, context.Canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	err := runner.Run(ctx, minimalConfig())
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(ctx, cfg)
 
 	assert.Error(t, err)
 }
 
 func TestRun_UnlockFailure(t *testing.T) {
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	// Init succeeds, unlock fails (e.g., repository is locked and fail_on_locked is true)
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(errors.New("repository has 2 stale lock(s)"))
-
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
-	err := runner.Run(context.Background(), minimalConfig())
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil, errors.New("repository has 2 stale lock(s)"))
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "unlock failed")
@@ -683,19 +1684,15 @@ func TestRun_UnlockFailure(t *testing.T) {
 
 func TestRun_TelegramIncludesBackupStatsOnSSHFailure(t *testing.T) {
 	// When backup succeeds but SSH shutdown fails, Telegram should include backup stats
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	var capturedMsg models.TelegramMessage
 
 	// Backup succeeds with stats
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
 		SnapshotID:          "abc123",
 		FilesNew:            10,
 		FilesChanged:        5,
@@ -704,37 +1701,29 @@ func TestRun_TelegramIncludesBackupStatsOnSSHFailure(t *testing.T) {
 		TotalFilesProcessed: 115,
 		TotalBytesProcessed: 10 * 1024 * 1024,
 	}, nil)
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
 
 	// SSH shutdown fails
-	sshSvc.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: false, Error: errors.New("connection refused")}, nil)
+	mocks.ssh.EXPECT().Shutdown(mock.Anything, mock.Anything).Return(&models.SSHResult{CommandRun: false, Error: errors.New("connection refused")}, nil)
 
 	// Telegram should include backup stats even though SSH failed
-	telegramSvc.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
 		capturedMsg = msg
 	}).Return(&models.TelegramResult{MessageSent: true}, nil)
 
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
 	cfg := minimalConfig()
 	cfg.Telegram = &models.TelegramConfig{
 		BotToken: "123456:ABC",
-		ChatID:   "-100123",
+		ChatIDs:  []string{"-100123"},
 	}
 	cfg.SSHShutdown = &models.SSHShutdownConfig{
 		Host:       "192.168.1.100",
 		PrivateKey: []byte("test-key"),
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	// SSH shutdown failed, but backup succeeded
@@ -752,56 +1741,44 @@ func TestRun_TelegramIncludesBackupStatsOnSSHFailure(t *testing.T) {
 
 func TestRun_TelegramIncludesForgetStatsOnCheckFailure(t *testing.T) {
 	// When backup and forget succeed but check fails, Telegram should include both stats
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	var capturedMsg models.TelegramMessage
 
 	// Backup succeeds
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
 		SnapshotID: "snap123",
 		FilesNew:   20,
 		DataAdded:  2048,
 	}, nil)
 
 	// Forget succeeds
-	resticSvc.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{
 		SnapshotsKept:    5,
 		SnapshotsRemoved: 2,
 	}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
 
 	// Check fails
-	resticSvc.EXPECT().Check(mock.Anything, mock.Anything, mock.Anything).Return(&models.CheckResult{Passed: false, Error: errors.New("repository corrupted")}, nil)
+	mocks.restic.EXPECT().Check(mock.Anything, mock.Anything, mock.Anything).Return(&models.CheckResult{Passed: false, Error: errors.New("repository corrupted")}, nil)
 
 	// Telegram should include backup and forget stats
-	telegramSvc.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
 		capturedMsg = msg
 	}).Return(&models.TelegramResult{MessageSent: true}, nil)
 
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
 	cfg := minimalConfig()
 	cfg.Telegram = &models.TelegramConfig{
 		BotToken: "123456:ABC",
-		ChatID:   "-100123",
+		ChatIDs:  []string{"-100123"},
 	}
 	cfg.Check = models.CheckSettings{Enabled: true}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
@@ -818,42 +1795,29 @@ func TestRun_TelegramIncludesForgetStatsOnCheckFailure(t *testing.T) {
 
 func TestRun_TelegramNoBackupStatsOnBackupFailure(t *testing.T) {
 	// When backup fails, Telegram should NOT include backup stats
-	resticSvc := resticmocks.NewMockService(t)
-	wolSvc := wolmocks.NewMockService(t)
-	postgresSvc := postgresmocks.NewMockService(t)
-	sshSvc := sshmocks.NewMockService(t)
-	telegramSvc := telegrammocks.NewMockService(t)
-	pushoverSvc := pushovermocks.NewMockService(t)
+	mocks := newTestMocks(t)
 
 	var capturedMsg models.TelegramMessage
 
 	// Backup fails
-	resticSvc.EXPECT().Init(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Unlock(mock.Anything, mock.Anything).Return(nil)
-	resticSvc.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("backup failed")}, nil)
 
 	// Telegram should NOT include backup stats since backup failed
-	telegramSvc.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
 		capturedMsg = msg
 	}).Return(&models.TelegramResult{MessageSent: true}, nil)
 
-	runner := NewWithServices(
-		testLogger(),
-		resticSvc,
-		wolSvc,
-		postgresSvc,
-		sshSvc,
-		telegramSvc,
-		pushoverSvc,
-		t.TempDir(),
-	)
-
 	cfg := minimalConfig()
 	cfg.Telegram = &models.TelegramConfig{
 		BotToken: "123456:ABC",
-		ChatID:   "-100123",
+		ChatIDs:  []string{"-100123"},
 	}
 
+	runner := mocks.runner(cfg)
+
 	err := runner.Run(context.Background(), cfg)
 
 	assert.Error(t, err)
@@ -865,3 +1829,1099 @@ func TestRun_TelegramNoBackupStatsOnBackupFailure(t *testing.T) {
 	assert.Zero(t, capturedMsg.FilesNew)
 	assert.Zero(t, capturedMsg.DataAdded)
 }
+
+func TestRunWithSummary_Success(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
+		SnapshotID: "abc123",
+		FilesNew:   10,
+		DataAdded:  2048,
+	}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5, SnapshotsRemoved: 2}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	assert.True(t, summary.Success)
+	assert.Empty(t, summary.FailedStep)
+	assert.Empty(t, summary.ErrorMessage)
+	assert.Equal(t, "abc123", summary.SnapshotID)
+	assert.Equal(t, 10, summary.FilesNew)
+	assert.Equal(t, int64(2048), summary.DataAdded)
+	assert.Equal(t, 5, summary.SnapshotsKept)
+	assert.Equal(t, 2, summary.SnapshotsRemoved)
+	assert.Positive(t, summary.Duration)
+}
+
+func TestRunWithSummary_Failure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "abc123"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{Error: errors.New("repository locked")}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.Error(t, err)
+	require.NotNil(t, summary)
+	assert.False(t, summary.Success)
+	assert.Equal(t, "forget", summary.FailedStep)
+	assert.Contains(t, summary.ErrorMessage, "repository locked")
+	assert.Equal(t, "abc123", summary.SnapshotID, "backup stats are kept when a later step fails")
+	assert.Zero(t, summary.SnapshotsKept)
+	assert.Contains(t, summary.StepTimings, "forget", "the failed step is timed too")
+}
+
+func TestRunWithSummary_RecordsStepTimings(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, _ models.ResticConfig, _ models.BackupSettings, _ models.ResticProgressCallback) (*models.BackupResult, error) {
+			time.Sleep(10 * time.Millisecond)
+			return &models.BackupResult{SnapshotID: "abc123"}, nil
+		})
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.NotNil(t, summary)
+	assert.GreaterOrEqual(t, summary.StepTimings["backup"], 10*time.Millisecond)
+	assert.Contains(t, summary.StepTimings, "forget")
+	assert.Contains(t, summary.StepTimings, "init")
+	assert.NotContains(t, summary.StepTimings, "wol", "steps that did not run are not timed")
+	assert.NotContains(t, summary.StepTimings, "check")
+}
+
+func TestRunWithSummary_BackupPathChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    func(dir string) models.BackupSettings
+		errContains string
+	}{
+		{
+			name: "missing path",
+			settings: func(dir string) models.BackupSettings {
+				return models.BackupSettings{
+					Paths:             []string{dir, filepath.Join(dir, "not-mounted")},
+					RequirePathsExist: true,
+				}
+			},
+			errContains: "does not exist",
+		},
+		{
+			name: "empty path when non-empty is required",
+			settings: func(dir string) models.BackupSettings {
+				return models.BackupSettings{
+					Paths:             []string{dir},
+					RequirePathsExist: true,
+					RequireNonEmpty:   true,
+				}
+			},
+			errContains: "is empty",
+		},
+		{
+			name: "missing target path",
+			settings: func(dir string) models.BackupSettings {
+				return models.BackupSettings{
+					Targets:           []models.BackupTarget{{Paths: []string{filepath.Join(dir, "gone")}}},
+					RequirePathsExist: true,
+				}
+			},
+			errContains: "does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+
+			cfg := minimalConfig()
+			cfg.Backup = tt.settings(t.TempDir())
+
+			runner := mocks.runner(cfg)
+
+			summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+			assert.Equal(t, "backup", summary.FailedStep)
+		})
+	}
+}
+
+func TestRunWithSummary_BackupBelowThreshold(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{
+		SnapshotID:          "abc123",
+		DataAdded:           512,
+		TotalFilesProcessed: 2,
+	}, nil)
+	// Forget must not run after the threshold check failed
+
+	cfg := minimalConfig()
+	cfg.Backup.MinDataAdded = 1024 * 1024
+	cfg.Backup.MinFilesProcessed = 100
+
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backup produced suspiciously little data")
+	assert.Equal(t, "backup", summary.FailedStep)
+	assert.Equal(t, "abc123", summary.SnapshotID, "backup stats are kept for notifications")
+}
+
+func TestThroughput(t *testing.T) {
+	assert.Equal(t, int64(1024*1024), throughput(10*1024*1024, 10*time.Second))
+	assert.Equal(t, int64(2048), throughput(1024, 500*time.Millisecond))
+	assert.Zero(t, throughput(1024, 0), "zero duration must not divide by zero")
+}
+
+func TestCheckBackupThresholds(t *testing.T) {
+	result := &models.BackupResult{DataAdded: 2048, TotalFilesProcessed: 10}
+
+	assert.NoError(t, checkBackupThresholds(models.BackupSettings{}, result), "disabled by default")
+	assert.NoError(t, checkBackupThresholds(models.BackupSettings{MinDataAdded: 2048, MinFilesProcessed: 10}, result))
+	assert.ErrorContains(t, checkBackupThresholds(models.BackupSettings{MinDataAdded: 4096}, result), "suspiciously little data")
+	assert.ErrorContains(t, checkBackupThresholds(models.BackupSettings{MinFilesProcessed: 11}, result), "suspiciously little data")
+}
+
+func TestCheckBackupPaths(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file"), []byte("data"), 0o600))
+	emptyFile := filepath.Join(t.TempDir(), "empty")
+	require.NoError(t, os.WriteFile(emptyFile, nil, 0o600))
+	missing := filepath.Join(dir, "missing")
+
+	assert.NoError(t, checkBackupPaths(models.BackupSettings{Paths: []string{dir}, RequirePathsExist: true, RequireNonEmpty: true}))
+	assert.NoError(t, checkBackupPaths(models.BackupSettings{Paths: []string{missing}}), "checks disabled")
+	assert.NoError(t, checkBackupPaths(models.BackupSettings{Paths: []string{missing}, FilesFrom: "/etc/files.txt", RequirePathsExist: true}), "paths are replaced by files_from")
+	assert.ErrorContains(t, checkBackupPaths(models.BackupSettings{Paths: []string{missing}, RequirePathsExist: true}), "does not exist")
+	assert.ErrorContains(t, checkBackupPaths(models.BackupSettings{Paths: []string{emptyFile}, RequireNonEmpty: true}), "is empty")
+}
+
+func TestRunWithSummary_WaitForNetwork(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "abc123"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Network = &models.NetworkConfig{
+		Address:      "offsite.example.com:443",
+		Timeout:      time.Second,
+		PollInterval: time.Millisecond,
+	}
+
+	// Unreachable twice, then the network comes up
+	var attempts int
+	var dialedAddress string
+	runner := mocks.runner(cfg)
+	runner.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		attempts++
+		dialedAddress = address
+		if attempts < 3 {
+			return nil, errors.New("dial tcp: lookup offsite.example.com: no such host")
+		}
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, "offsite.example.com:443", dialedAddress)
+	assert.Contains(t, summary.StepTimings, "network")
+}
+
+func TestRun_WaitForNetworkBeforeHealthcheckStart(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var dialed bool
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "abc123"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+	mocks.health.EXPECT().Ping(mock.Anything, "https://hc-ping.com/uuid", models.HealthcheckStart).Run(func(ctx context.Context, pingURL string, status models.HealthcheckStatus) {
+		assert.True(t, dialed, "start ping is sent once the network is up")
+	}).Return(&models.HealthcheckResult{Pinged: true}, nil)
+	mocks.health.EXPECT().Ping(mock.Anything, "https://hc-ping.com/uuid", models.HealthcheckSuccess).Return(&models.HealthcheckResult{Pinged: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Healthcheck = &models.HealthcheckConfig{PingURL: "https://hc-ping.com/uuid"}
+	cfg.Network = &models.NetworkConfig{
+		Address:      "offsite.example.com:443",
+		Timeout:      time.Second,
+		PollInterval: time.Millisecond,
+	}
+
+	runner := mocks.runner(cfg)
+	runner.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		dialed = true
+		client, server := net.Pipe()
+		_ = server.Close()
+		return client, nil
+	}
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+}
+
+func TestRunWithSummary_WaitForNetworkTimeout(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	// Nothing else runs when the network never comes up
+	cfg := minimalConfig()
+	cfg.Network = &models.NetworkConfig{
+		Address:      "offsite.example.com:443",
+		Timeout:      50 * time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	}
+
+	runner := mocks.runner(cfg)
+	runner.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, errors.New("connect: network is unreachable")
+	}
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "network not reachable")
+	assert.Contains(t, err.Error(), "network is unreachable")
+	assert.Equal(t, "network", summary.FailedStep)
+}
+
+func TestRun_SkipRetentionWhenUnchanged(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{}, nil)
+	// No Forget or Prune expectations: both must be skipped
+
+	cfg := minimalConfig()
+	cfg.Retention.SkipWhenUnchanged = true
+	cfg.Retention.Prune = models.PruneSettings{Enabled: true}
+
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, summary.Success)
+	assert.NotContains(t, summary.StepTimings, "forget")
+}
+
+func TestRun_RetentionRunsWithoutSnapshotByDefault(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 3}, nil)
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+}
+
+func TestMergeBackupResults_SkipsEmptySnapshotIDs(t *testing.T) {
+	merged := mergeBackupResults([]*models.BackupResult{
+		{SnapshotID: "abc", FilesNew: 1},
+		{FilesNew: 2},
+		{SnapshotID: "def"},
+	})
+
+	assert.Equal(t, "abc, def", merged.SnapshotID)
+	assert.Equal(t, 3, merged.FilesNew)
+	assert.Empty(t, mergeBackupResults([]*models.BackupResult{{}, {}}).SnapshotID)
+}
+
+func TestExpandTags(t *testing.T) {
+	now := time.Date(2024, 1, 15, 3, 4, 5, 0, time.UTC)
+
+	tags := expandTags([]string{"daily-{date}", "{weekday}", "at-{time}", "{host}", "automated"}, now, "nas")
+
+	assert.Equal(t, []string{"daily-2024-01-15", "monday", "at-03-04-05", "nas", "automated"}, tags)
+	assert.Nil(t, expandTags(nil, now, "nas"))
+}
+
+func TestRun_ExpandsTagPlaceholders(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedTags []string
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+		capturedTags = settings.Tags
+	}).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.Tags = []string{"daily-{date}", "automated"}
+
+	runner := mocks.runner(cfg)
+	runner.now = func() time.Time { return time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC) }
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"daily-2024-01-15", "automated"}, capturedTags)
+	assert.Equal(t, []string{"daily-{date}", "automated"}, cfg.Backup.Tags, "config tags are not modified")
+}
+
+func TestRun_BackupTargets(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var captured []models.BackupSettings
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) (*models.BackupResult, error) {
+			captured = append(captured, settings)
+			return &models.BackupResult{SnapshotID: fmt.Sprintf("snap%d", len(captured)), FilesNew: 10}, nil
+		}).Times(2)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.Paths = nil
+	cfg.Backup.Excludes = []string{"*.tmp"}
+	cfg.Backup.Targets = []models.BackupTarget{
+		{Paths: []string{"/etc"}, Tags: []string{"config"}},
+		{Paths: []string{"/var/lib/docker"}, Tags: []string{"docker", "volumes"}},
+	}
+
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, captured, 2)
+	assert.Equal(t, []string{"/etc"}, captured[0].Paths)
+	assert.Equal(t, []string{"config"}, captured[0].Tags)
+	assert.Equal(t, []string{"/var/lib/docker"}, captured[1].Paths)
+	assert.Equal(t, []string{"docker", "volumes"}, captured[1].Tags)
+	for _, settings := range captured {
+		assert.Equal(t, []string{"*.tmp"}, settings.Excludes, "shared settings apply to every target")
+		assert.Equal(t, "testhost", settings.Host)
+		assert.Empty(t, settings.Targets)
+	}
+	assert.Equal(t, "snap1, snap2", summary.SnapshotID)
+	assert.Equal(t, 20, summary.FilesNew)
+}
+
+func TestRun_BackupTargetsWithFlatPaths(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var captured []models.BackupSettings
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) (*models.BackupResult, error) {
+			captured = append(captured, settings)
+			return &models.BackupResult{SnapshotID: "snap"}, nil
+		}).Times(2)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.Tags = []string{"daily"}
+	cfg.Backup.Targets = []models.BackupTarget{{Paths: []string{"/etc"}, Tags: []string{"config"}}}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Len(t, captured, 2)
+	assert.Equal(t, []string{"/data"}, captured[0].Paths)
+	assert.Equal(t, []string{"daily"}, captured[0].Tags)
+	assert.Equal(t, []string{"/etc"}, captured[1].Paths)
+	assert.Equal(t, []string{"config"}, captured[1].Tags)
+}
+
+func TestRun_BackupTargetFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "snap1"}, nil).Once()
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{Error: errors.New("permission denied")}, nil).Once()
+
+	cfg := minimalConfig()
+	cfg.Backup.Targets = []models.BackupTarget{{Paths: []string{"/etc"}, Tags: []string{"config"}}}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "backup failed: permission denied")
+}
+
+func TestRun_WithCopyTo(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedDst models.ResticConfig
+	var capturedOpts models.CopyOptions
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Copy(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, srcCfg, dstCfg models.ResticConfig, opts models.CopyOptions) {
+		capturedDst = dstCfg
+		capturedOpts = opts
+	}).Return(&models.CopyResult{}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.Host = "homelab"
+	cfg.CopyTo = &models.CopyToConfig{
+		Enabled:      true,
+		ResticConfig: models.ResticConfig{Repository: "/offsite", Password: "offsite-secret"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, "/offsite", capturedDst.Repository)
+	assert.Equal(t, "offsite-secret", capturedDst.Password)
+	assert.Equal(t, "homelab", capturedOpts.Host)
+}
+
+func TestRun_CopyToFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Copy(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.CopyResult{Error: errors.New("wrong password")}, nil)
+
+	cfg := minimalConfig()
+	cfg.CopyTo = &models.CopyToConfig{
+		Enabled:      true,
+		ResticConfig: models.ResticConfig{Repository: "/offsite", Password: "offsite-secret"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "copy failed")
+}
+
+func TestRun_CopyToDisabled(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.CopyTo = &models.CopyToConfig{
+		Enabled:      false,
+		ResticConfig: models.ResticConfig{Repository: "/offsite", Password: "offsite-secret"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	mocks.restic.AssertNotCalled(t, "Copy", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRun_ResticBinaryMissing(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("", errors.New("restic not found or too old (need >= 0.16.0)"))
+
+	cfg := minimalConfig()
+	cfg.WOL = &models.WOLConfig{MACAddress: "00:11:22:33:44:55"}
+
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "restic not found or too old")
+	assert.Equal(t, "restic", summary.FailedStep)
+	mocks.wol.AssertNotCalled(t, "Wake", mock.Anything, mock.Anything)
+}
+
+func TestRun_PostgresMinVersionUnmet(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(14, nil)
+
+	cfg := minimalConfig()
+	cfg.Postgres = &models.PostgresConfig{
+		Database:   "testdb",
+		MinVersion: 16,
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "pg_dump version 14 is older than postgres.min_version 16")
+	mocks.postgres.AssertNotCalled(t, "Dump", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRun_PostgresClientOlderThanServer(t *testing.T) {
+	tests := []struct {
+		name      string
+		serverErr error
+	}{
+		{name: "older client only warns"},
+		{name: "unknown server version is ignored", serverErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			var server int
+			if tt.serverErr == nil {
+				server = 17
+			}
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+			mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(server, tt.serverErr)
+			mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{OutputPath: "/tmp/dump.sql"}, nil)
+			mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+			mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+			cfg := minimalConfig()
+			cfg.Postgres = &models.PostgresConfig{
+				Database:   "testdb",
+				MinVersion: 16,
+			}
+
+			runner := mocks.runner(cfg)
+
+			err := runner.Run(context.Background(), cfg)
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRun_RemovesRunDirectory(t *testing.T) {
+	tests := []struct {
+		name      string
+		backupErr error
+		panics    bool
+	}{
+		{name: "success"},
+		{name: "failure", backupErr: errors.New("repository unreachable")},
+		{name: "panic", panics: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			var runDir string
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+			mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+			mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.PostgresConfig, outputPath string) (*models.PostgresDumpResult, error) {
+				runDir = filepath.Dir(outputPath)
+				require.NoError(t, os.WriteFile(outputPath, []byte("dump"), 0o600))
+				return &models.PostgresDumpResult{OutputPath: outputPath}, nil
+			})
+			backup := mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			switch {
+			case tt.panics:
+				backup.Run(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, _ models.ResticProgressCallback) {
+					panic("unexpected")
+				})
+			case tt.backupErr != nil:
+				backup.Return(nil, tt.backupErr)
+			default:
+				backup.Return(&models.BackupResult{SnapshotID: "test"}, nil)
+				mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+			}
+
+			cfg := minimalConfig()
+			cfg.TempDir = filepath.Join(t.TempDir(), "work")
+			cfg.Postgres = &models.PostgresConfig{Database: "testdb"}
+
+			runner := mocks.runner(cfg)
+			if tt.panics {
+				assert.Panics(t, func() { _ = runner.Run(context.Background(), cfg) })
+			} else {
+				err := runner.Run(context.Background(), cfg)
+				assert.Equal(t, tt.backupErr == nil, err == nil)
+			}
+
+			require.NotEmpty(t, runDir)
+			assert.Equal(t, cfg.TempDir, filepath.Dir(runDir))
+			assert.NoDirExists(t, runDir)
+			entries, err := os.ReadDir(cfg.TempDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestRun_SkipsWhenRecentSnapshotExists(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Snapshots(mock.Anything, mock.Anything, models.SnapshotFilter{Host: "testhost"}).Return([]models.Snapshot{
+		{ID: "old", Time: now.Add(-48 * time.Hour)},
+		{ID: "recent", Time: now.Add(-2 * time.Hour)},
+	}, nil)
+	// No backup, forget or notification expected
+
+	cfg := minimalConfig()
+	cfg.Backup.Host = "testhost"
+	cfg.Backup.MinInterval = 12 * time.Hour
+	cfg.Telegram = &models.TelegramConfig{BotToken: "token", ChatIDs: []string{"123"}}
+
+	runner := mocks.runner(cfg)
+	runner.now = func() time.Time { return now }
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, summary.Success)
+}
+
+func TestRun_SkippedRunReportsSkip(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Snapshots(mock.Anything, mock.Anything, mock.Anything).Return([]models.Snapshot{{ID: "recent", Time: now.Add(-time.Hour)}}, nil)
+	mocks.health.EXPECT().Ping(mock.Anything, "https://hc-ping.com/uuid", models.HealthcheckStart).Return(&models.HealthcheckResult{Pinged: true}, nil)
+	mocks.health.EXPECT().Ping(mock.Anything, "https://hc-ping.com/uuid", models.HealthcheckSkipped).Return(&models.HealthcheckResult{Pinged: true}, nil)
+	var written models.TelegramMessage
+	mocks.metrics.EXPECT().Write("/tmp/gorestic.prom", mock.Anything).Run(func(path string, msg models.TelegramMessage) {
+		written = msg
+	}).Return(nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.MinInterval = 12 * time.Hour
+	cfg.Healthcheck = &models.HealthcheckConfig{PingURL: "https://hc-ping.com/uuid"}
+	cfg.MetricsFile = "/tmp/gorestic.prom"
+
+	runner := mocks.runner(cfg)
+	runner.now = func() time.Time { return now }
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, written.Skipped)
+	assert.Empty(t, written.SnapshotID)
+}
+
+func TestRun_BacksUpWhenNewestSnapshotIsOlderThanMinInterval(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Snapshots(mock.Anything, mock.Anything, mock.Anything).Return([]models.Snapshot{
+		{ID: "old", Time: now.Add(-13 * time.Hour)},
+	}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "new"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.MinInterval = 12 * time.Hour
+
+	runner := mocks.runner(cfg)
+	runner.now = func() time.Time { return now }
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.NoError(t, err)
+}
+
+func TestRun_ForceIgnoresMinInterval(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "new"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+	// Snapshots is not listed with --force
+
+	cfg := minimalConfig()
+	cfg.Backup.MinInterval = 12 * time.Hour
+	cfg.Force = true
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	assert.NoError(t, err)
+}
+
+func TestRun_MinIntervalSnapshotsFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Snapshots(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("repository not found"))
+
+	cfg := minimalConfig()
+	cfg.Backup.MinInterval = 12 * time.Hour
+
+	runner := mocks.runner(cfg)
+
+	summary, err := runner.RunWithSummary(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to list snapshots")
+	assert.Equal(t, "min_interval", summary.FailedStep)
+}
+
+func TestRun_BackupTargetFailureAfterSnapshotIsPartial(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "snap1"}, nil).Once()
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("no space left on device")).Once()
+
+	cfg := minimalConfig()
+	cfg.Backup.Paths = nil
+	cfg.Backup.Targets = []models.BackupTarget{
+		{Paths: []string{"/etc"}},
+		{Paths: []string{"/var/lib/docker"}},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	var partialErr *PartialError
+	require.ErrorAs(t, err, &partialErr)
+	assert.Equal(t, 1, partialErr.Snapshots)
+	assert.Contains(t, err.Error(), "no space left on device")
+}
+
+func TestRun_FirstBackupFailureIsNotPartial(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("no space left on device")).Once()
+
+	cfg := minimalConfig()
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	var partialErr *PartialError
+	assert.False(t, errors.As(err, &partialErr))
+}
+
+func TestRun_PostgresStreamToRestic(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var streamed []string
+	var stdinFilenames []string
+
+	// testify formats the pipe while matching BackupStdin, so each dump waits until it was called
+	readerReady := make(chan struct{}, 2)
+
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().DumpTo(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.PostgresConfig, w io.Writer) (*models.PostgresDumpResult, error) {
+		<-readerReady
+		n, err := io.WriteString(w, "dump of "+cfg.Database)
+		return &models.PostgresDumpResult{SizeBytes: int64(n)}, err
+	})
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "files"}, nil)
+	mocks.restic.EXPECT().BackupStdin(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader) (*models.BackupResult, error) {
+		readerReady <- struct{}{}
+		data, err := io.ReadAll(stdin)
+		require.NoError(t, err)
+		streamed = append(streamed, string(data))
+		stdinFilenames = append(stdinFilenames, settings.StdinFilename)
+		return &models.BackupResult{SnapshotID: settings.StdinFilename}, nil
+	})
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+
+	cfg := minimalConfig()
+	cfg.Postgres = &models.PostgresConfig{
+		Databases:      []string{"app", "wiki"},
+		Format:         "custom",
+		StreamToRestic: true,
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dump of app", "dump of wiki"}, streamed)
+	assert.Equal(t, []string{"app.dump", "wiki.dump"}, stdinFilenames)
+	mocks.postgres.AssertNotCalled(t, "Dump", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRun_PostgresStreamDumpFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	// testify formats the pipe while matching BackupStdin, so each dump waits until it was called
+	readerReady := make(chan struct{}, 2)
+
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().DumpTo(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.PostgresConfig, w io.Writer) (*models.PostgresDumpResult, error) {
+		<-readerReady
+		n, _ := io.WriteString(w, "partial")
+		return &models.PostgresDumpResult{SizeBytes: int64(n), Error: errors.New("connection reset")}, nil
+	})
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "files"}, nil)
+	mocks.restic.EXPECT().BackupStdin(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.ResticConfig, settings models.BackupSettings, stdin io.Reader) (*models.BackupResult, error) {
+		readerReady <- struct{}{}
+		// restic must see the dump error instead of a clean EOF
+		_, err := io.ReadAll(stdin)
+		return nil, err
+	})
+
+	cfg := minimalConfig()
+	cfg.Postgres = &models.PostgresConfig{
+		Database:       "app",
+		Format:         "custom",
+		StreamToRestic: true,
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PostgreSQL dump failed for app: connection reset")
+	var partial *PartialError
+	assert.ErrorAs(t, err, &partial)
+}
+
+func TestRun_TelegramSnapshotCountStatsFailure(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	var capturedMsg models.TelegramMessage
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+	mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(nil, errors.New("repository busy"))
+
+	// The run and its notification go on without the count
+	mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).Run(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) {
+		capturedMsg = msg
+	}).Return(&models.TelegramResult{MessageSent: true}, nil)
+
+	cfg := minimalConfig()
+	cfg.Telegram = &models.TelegramConfig{
+		BotToken: "123456:ABC",
+		ChatIDs:  []string{"-100123"},
+	}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, capturedMsg.Success)
+	assert.Zero(t, capturedMsg.SnapshotCount)
+}
+
+func TestRun_NotifyBeforeShutdownOrder(t *testing.T) {
+	tests := []struct {
+		name           string
+		beforeShutdown bool
+		want           []string
+	}{
+		{"default", false, []string{"shutdown", "notify"}},
+		{"before shutdown", true, []string{"notify", "shutdown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mocks := newTestMocks(t)
+
+			var calls []string
+
+			mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+			mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+			mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+			mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&models.BackupResult{SnapshotID: "test"}, nil)
+			mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{SnapshotsKept: 5}, nil)
+			mocks.restic.EXPECT().Stats(mock.Anything, mock.Anything, models.StatsModeRawData).Return(&models.StatsResult{}, nil)
+			mocks.ssh.EXPECT().Shutdown(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.SSHShutdownConfig) (*models.SSHResult, error) {
+				calls = append(calls, "shutdown")
+				return &models.SSHResult{CommandRun: true}, nil
+			})
+			mocks.telegram.EXPECT().SendNotification(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) (*models.TelegramResult, error) {
+				calls = append(calls, "notify")
+				return &models.TelegramResult{MessageSent: true}, nil
+			})
+
+			cfg := minimalConfig()
+			cfg.NotifyBeforeShutdown = tt.beforeShutdown
+			cfg.Telegram = &models.TelegramConfig{
+				BotToken: "123456:ABC",
+				ChatIDs:  []string{"-100123"},
+			}
+			cfg.SSHShutdown = &models.SSHShutdownConfig{
+				Host:       "192.168.1.100",
+				PrivateKey: []byte("test-key"),
+			}
+
+			runner := mocks.runner(cfg)
+
+			err := runner.Run(context.Background(), cfg)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, calls)
+		})
+	}
+}
+
+// argsExecutor records the restic arguments of the last command.
+type argsExecutor struct {
+	args []string
+}
+
+func (e *argsExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	e.args = args
+	return nil, nil
+}
+
+func (e *argsExecutor) ExecuteWithEnv(ctx context.Context, env []string, name string, args ...string) ([]byte, error) {
+	e.args = args
+	return []byte(`{"message_type":"summary","snapshot_id":"test"}`), nil
+}
+
+func (e *argsExecutor) ExecuteWithEnvStreaming(ctx context.Context, env []string, progressCb models.ResticProgressCallback, name string, args ...string) ([]byte, error) {
+	return e.ExecuteWithEnv(ctx, env, name, args...)
+}
+
+func (e *argsExecutor) ExecuteWithStdin(ctx context.Context, env []string, stdin io.Reader, name string, args ...string) ([]byte, error) {
+	return e.ExecuteWithEnv(ctx, env, name, args...)
+}
+
+func TestRun_FilesFromWithPostgresDump(t *testing.T) {
+	mocks := newTestMocks(t)
+
+	executor := &argsExecutor{}
+	backupArgs := restic.NewWithExecutor(testLogger(), executor)
+
+	mocks.postgres.EXPECT().CheckVersion(mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().ServerVersion(mock.Anything, mock.Anything).Return(16, nil)
+	mocks.postgres.EXPECT().Dump(mock.Anything, mock.Anything, mock.Anything).Return(&models.PostgresDumpResult{OutputPath: "/tmp/dump.sql", SizeBytes: 1024}, nil)
+
+	mocks.restic.EXPECT().CheckBinary(mock.Anything).Return("0.16.4", nil)
+	mocks.restic.EXPECT().Init(mock.Anything, mock.Anything).Return(&models.InitResult{}, nil)
+	mocks.restic.EXPECT().Unlock(mock.Anything, mock.Anything).Return(&models.UnlockResult{}, nil)
+	mocks.restic.EXPECT().Backup(mock.Anything, mock.Anything, mock.Anything, mock.Anything).RunAndReturn(backupArgs.Backup)
+	mocks.restic.EXPECT().Forget(mock.Anything, mock.Anything, mock.Anything).Return(&models.ForgetResult{}, nil)
+
+	cfg := minimalConfig()
+	cfg.Backup.FilesFrom = "/etc/gorestic/files.txt"
+	cfg.Postgres = &models.PostgresConfig{Host: "localhost", Port: 5432, Database: "testdb", Username: "postgres", Format: "custom"}
+
+	runner := mocks.runner(cfg)
+
+	err := runner.Run(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Contains(t, executor.args, "--files-from")
+	assert.Contains(t, executor.args, "/etc/gorestic/files.txt")
+	assert.Contains(t, executor.args, "/tmp/dump.sql")
+	assert.NotContains(t, executor.args, "/data", "paths are replaced by files_from")
+}
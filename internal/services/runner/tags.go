@@ -0,0 +1,27 @@
+package runner
+
+import (
+	"strings"
+	"time"
+)
+
+// expandTags replaces the placeholders {date}, {time}, {weekday} and {host}
+// in snapshot tags, e.g. "daily-{date}" becomes "daily-2024-01-15".
+func expandTags(tags []string, now time.Time, host string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+
+	replacer := strings.NewReplacer(
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("15-04-05"),
+		"{weekday}", strings.ToLower(now.Weekday().String()),
+		"{host}", host,
+	)
+
+	expanded := make([]string, len(tags))
+	for i, tag := range tags {
+		expanded[i] = replacer.Replace(tag)
+	}
+	return expanded
+}
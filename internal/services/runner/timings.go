@@ -0,0 +1,16 @@
+package runner
+
+import "time"
+
+// stepTimings records how long each step of a run took.
+type stepTimings map[string]time.Duration
+
+// start begins timing step and returns a function that records the elapsed
+// time. Call it right after the step, before checking its error, so failed
+// steps are timed too.
+func (t stepTimings) start(step string) func() {
+	begin := time.Now()
+	return func() {
+		t[step] += time.Since(begin)
+	}
+}
@@ -0,0 +1,140 @@
+// Package scheduler runs a job repeatedly on a cron or interval schedule.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+)
+
+// Job is the work executed on every scheduled run.
+type Job func() error
+
+// Clock abstracts time for testing.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock uses the time package.
+type realClock struct{}
+
+// Now returns the current time.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to elapse.
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// ParseSchedule builds a schedule from a standard five-field cron expression
+// or a fixed interval. Exactly one of them must be set.
+func ParseSchedule(cronExpr string, every time.Duration) (cron.Schedule, error) {
+	switch {
+	case cronExpr != "" && every != 0:
+		return nil, errors.New("cron expression and interval are mutually exclusive")
+	case cronExpr != "":
+		schedule, err := cron.ParseStandard(cronExpr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", cronExpr, err)
+		}
+		return schedule, nil
+	case every < 0:
+		return nil, fmt.Errorf("interval must be positive, got %s", every)
+	case every > 0:
+		if every < time.Second {
+			return nil, fmt.Errorf("interval must be at least 1s, got %s", every)
+		}
+		return cron.Every(every), nil
+	default:
+		return nil, errors.New("either a cron expression or an interval is required")
+	}
+}
+
+// Scheduler runs a job on a schedule, skipping runs while the previous one is in flight.
+type Scheduler struct {
+	logger   zerolog.Logger
+	schedule cron.Schedule
+	job      Job
+	clock    Clock
+	running  atomic.Bool
+	wg       sync.WaitGroup
+}
+
+// New creates a new Scheduler.
+func New(logger zerolog.Logger, schedule cron.Schedule, job Job) *Scheduler {
+	return NewWithClock(logger, schedule, job, realClock{})
+}
+
+// NewWithClock creates a new Scheduler with a custom clock (for testing).
+func NewWithClock(logger zerolog.Logger, schedule cron.Schedule, job Job, clock Clock) *Scheduler {
+	return &Scheduler{
+		logger:   logger,
+		schedule: schedule,
+		job:      job,
+		clock:    clock,
+	}
+}
+
+// Next returns the time of the next scheduled run.
+func (s *Scheduler) Next() time.Time {
+	return s.schedule.Next(s.clock.Now())
+}
+
+// Run triggers the job on schedule until ctx is cancelled.
+// It then waits for an in-flight run to finish before returning.
+func (s *Scheduler) Run(ctx context.Context) error {
+	defer s.wg.Wait()
+
+	for {
+		next := s.Next()
+		s.logger.Info().Time("next_run", next).Msg("waiting for next scheduled run")
+
+		select {
+		case <-ctx.Done():
+			if s.running.Load() {
+				s.logger.Info().Msg("scheduler stopping, waiting for in-flight run")
+			}
+			return nil
+		case <-s.clock.After(next.Sub(s.clock.Now())):
+			s.Trigger()
+		}
+	}
+}
+
+// Trigger starts the job in the background unless a run is already in flight.
+// It returns false if the run was skipped.
+func (s *Scheduler) Trigger() bool {
+	if !s.running.CompareAndSwap(false, true) {
+		s.logger.Warn().Msg("previous run still in progress, skipping scheduled run")
+		return false
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.running.Store(false)
+
+		start := s.clock.Now()
+		if err := s.job(); err != nil {
+			s.logger.Error().Err(err).Msg("scheduled run failed")
+			return
+		}
+		s.logger.Info().Dur("duration", s.clock.Now().Sub(start)).Msg("scheduled run completed")
+	}()
+
+	return true
+}
+
+// Wait blocks until an in-flight run has finished.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
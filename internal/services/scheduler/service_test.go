@@ -0,0 +1,194 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() zerolog.Logger {
+	return zerolog.Nop()
+}
+
+// fakeClock returns a fixed time and fires After only when the test sends a tick.
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits chan time.Duration
+	ticks chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{
+		now:   now,
+		waits: make(chan time.Duration, 10),
+		ticks: make(chan time.Time),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits <- d
+	return c.ticks
+}
+
+func TestParseSchedule_Cron(t *testing.T) {
+	schedule, err := ParseSchedule("0 3 * * *", 0)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC), schedule.Next(now))
+
+	early := time.Date(2024, 1, 15, 1, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC), schedule.Next(early))
+}
+
+func TestParseSchedule_Every(t *testing.T) {
+	schedule, err := ParseSchedule("", 6*time.Hour)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	assert.Equal(t, now.Add(6*time.Hour), schedule.Next(now))
+}
+
+func TestParseSchedule_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		cronExpr string
+		every    time.Duration
+		errMsg   string
+	}{
+		{name: "neither", errMsg: "either a cron expression or an interval is required"},
+		{name: "both", cronExpr: "0 3 * * *", every: time.Hour, errMsg: "mutually exclusive"},
+		{name: "invalid cron", cronExpr: "every day", errMsg: "invalid cron expression"},
+		{name: "negative interval", every: -time.Hour, errMsg: "must be positive"},
+		{name: "sub-second interval", every: 500 * time.Millisecond, errMsg: "at least 1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSchedule(tt.cronExpr, tt.every)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestNext_UsesClock(t *testing.T) {
+	schedule, err := ParseSchedule("0 3 * * *", 0)
+	require.NoError(t, err)
+
+	clock := newFakeClock(time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC))
+	s := NewWithClock(testLogger(), schedule, func() error { return nil }, clock)
+
+	// A run exactly at the scheduled time is not repeated
+	assert.Equal(t, time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC), s.Next())
+}
+
+func TestTrigger_SkipsOverlappingRun(t *testing.T) {
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	runs := 0
+
+	job := func() error {
+		runs++
+		started <- struct{}{}
+		<-release
+		return nil
+	}
+
+	schedule, err := ParseSchedule("", time.Hour)
+	require.NoError(t, err)
+	s := NewWithClock(testLogger(), schedule, job, newFakeClock(time.Now()))
+
+	require.True(t, s.Trigger())
+	<-started
+
+	assert.False(t, s.Trigger(), "second run must be skipped while the first is in flight")
+
+	close(release)
+	s.Wait()
+
+	assert.True(t, s.Trigger(), "a new run starts once the previous one finished")
+	<-started
+	s.Wait()
+
+	assert.Equal(t, 2, runs)
+}
+
+func TestTrigger_JobErrorDoesNotBlockNextRun(t *testing.T) {
+	schedule, err := ParseSchedule("", time.Hour)
+	require.NoError(t, err)
+	s := NewWithClock(testLogger(), schedule, func() error { return errors.New("backup failed") }, newFakeClock(time.Now()))
+
+	require.True(t, s.Trigger())
+	s.Wait()
+
+	assert.True(t, s.Trigger())
+	s.Wait()
+}
+
+func TestRun_WaitsForNextRun(t *testing.T) {
+	now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	clock := newFakeClock(now)
+
+	schedule, err := ParseSchedule("0 3 * * *", 0)
+	require.NoError(t, err)
+	s := NewWithClock(testLogger(), schedule, func() error { return nil }, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	assert.Equal(t, 16*time.Hour+30*time.Minute, <-clock.waits)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestRun_StopsAfterInFlightRun(t *testing.T) {
+	clock := newFakeClock(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC))
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	job := func() error {
+		close(started)
+		<-release
+		return nil
+	}
+
+	schedule, err := ParseSchedule("", 6*time.Hour)
+	require.NoError(t, err)
+	s := NewWithClock(testLogger(), schedule, job, clock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	<-clock.waits
+	clock.ticks <- clock.Now()
+	<-started
+	<-clock.waits
+
+	cancel()
+
+	select {
+	case <-done:
+		t.Fatal("Run returned before the in-flight run finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-done)
+}
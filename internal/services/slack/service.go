@@ -0,0 +1,164 @@
+// Package slack provides Slack notification services.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// maxFieldText is the length limit Slack enforces on section field texts,
+// below the 3000 allowed for a section's own text.
+const maxFieldText = 2000
+
+// Service defines the interface for Slack notification operations.
+type Service interface {
+	SendNotification(ctx context.Context, cfg models.SlackConfig, msg models.TelegramMessage) (*models.SlackResult, error)
+}
+
+// HTTPClient allows mocking HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Impl implements the Slack Service interface.
+type Impl struct {
+	httpClient HTTPClient
+	logger     zerolog.Logger
+}
+
+// New creates a new Slack service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// NewWithClient creates a new Slack service with a custom HTTP client (for testing).
+func NewWithClient(logger zerolog.Logger, httpClient HTTPClient) *Impl {
+	return &Impl{
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// webhookRequest is the request body for a Slack incoming webhook.
+type webhookRequest struct {
+	Channel string  `json:"channel,omitempty"`
+	Text    string  `json:"text"`
+	Blocks  []block `json:"blocks"`
+}
+
+type block struct {
+	Type   string  `json:"type"`
+	Text   *text   `json:"text,omitempty"`
+	Fields []*text `json:"fields,omitempty"`
+}
+
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// SendNotification sends a backup notification to a Slack incoming webhook.
+func (s *Impl) SendNotification(ctx context.Context, cfg models.SlackConfig, msg models.TelegramMessage) (*models.SlackResult, error) {
+	result := &models.SlackResult{}
+
+	s.logger.Info().
+		Bool("success", msg.Success).
+		Msg("sending Slack notification")
+
+	reqBody := buildRequest(msg)
+	reqBody.Channel = cfg.Channel
+
+	// Slack uses mrkdwn, so <, > and & must reach it unescaped.
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(reqBody); err != nil {
+		result.Error = fmt.Errorf("failed to marshal request: %w", err)
+		return result, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.WebhookURL, &body)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create request: %w", err)
+		return result, nil
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to send request: %w", err)
+		return result, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Errorf("slack API returned status %d", resp.StatusCode)
+		return result, nil
+	}
+
+	result.MessageSent = true
+	s.logger.Info().Msg("Slack notification sent successfully")
+
+	return result, nil
+}
+
+func buildRequest(msg models.TelegramMessage) webhookRequest {
+	title := "✅ Backup Successful"
+	if !msg.Success {
+		title = "❌ Backup Failed"
+	}
+	if msg.DryRun {
+		title += " (dry-run)"
+	}
+
+	fields := []*text{
+		mrkdwn("Host", msg.Host),
+		mrkdwn("Duration", msg.Duration.Round(time.Second).String()),
+	}
+	if msg.Success {
+		fields = append(fields,
+			mrkdwn("Snapshot", "`"+msg.SnapshotID+"`"),
+			mrkdwn("Data added", format.Bytes(msg.DataAdded)),
+			mrkdwn("Files", fmt.Sprintf("%d new, %d changed, %d unmodified", msg.FilesNew, msg.FilesChanged, msg.FilesUnmodified)),
+			mrkdwn("Retention", fmt.Sprintf("%d kept, %d removed", msg.SnapshotsKept, msg.SnapshotsRemoved)),
+		)
+		if msg.RepoTotalSize > 0 {
+			fields = append(fields, mrkdwn("Repository size", format.Bytes(msg.RepoTotalSize)))
+		}
+		if msg.SnapshotCount > 0 {
+			fields = append(fields, mrkdwn("Snapshots in repo", strconv.Itoa(msg.SnapshotCount)))
+		}
+	} else {
+		fields = append(fields,
+			mrkdwn("Failed step", msg.FailedStep),
+			mrkdwn("Error", "`"+format.Truncate(msg.ErrorMessage, maxFieldText-len("*Error:*\n``"))+"`"),
+		)
+	}
+
+	return webhookRequest{
+		Text: fmt.Sprintf("%s on %s", title, msg.Host),
+		Blocks: []block{
+			{Type: "header", Text: &text{Type: "plain_text", Text: title}},
+			{Type: "section", Fields: fields},
+		},
+	}
+}
+
+func mrkdwn(name, value string) *text {
+	return &text{Type: "mrkdwn", Text: fmt.Sprintf("*%s:*\n%s", name, value)}
+}
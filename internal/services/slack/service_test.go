@@ -0,0 +1,207 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHTTPClient struct {
+	doFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.doFunc != nil {
+		return m.doFunc(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("ok")),
+	}, nil
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func testConfig() models.SlackConfig {
+	return models.SlackConfig{
+		WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX",
+		Channel:    "#backups",
+	}
+}
+
+func captureClient(rawBody *string) *mockHTTPClient {
+	return &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			*rawBody = string(body)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("ok")),
+			}, nil
+		},
+	}
+}
+
+func TestSendNotification_SuccessBlocks(t *testing.T) {
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&rawBody))
+
+	msg := models.TelegramMessage{
+		Success:          true,
+		Host:             "server1",
+		Duration:         5 * time.Minute,
+		SnapshotID:       "abc123",
+		FilesNew:         10,
+		FilesChanged:     5,
+		FilesUnmodified:  100,
+		DataAdded:        1024 * 1024,
+		SnapshotsKept:    7,
+		SnapshotsRemoved: 2,
+	}
+
+	result, err := svc.SendNotification(context.Background(), testConfig(), msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Nil(t, result.Error)
+
+	expected := `{
+		"channel": "#backups",
+		"text": "✅ Backup Successful on server1",
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "✅ Backup Successful"}},
+			{"type": "section", "fields": [
+				{"type": "mrkdwn", "text": "*Host:*\nserver1"},
+				{"type": "mrkdwn", "text": "*Duration:*\n5m0s"},
+				{"type": "mrkdwn", "text": "*Snapshot:*\n` + "`abc123`" + `"},
+				{"type": "mrkdwn", "text": "*Data added:*\n1.0 MiB"},
+				{"type": "mrkdwn", "text": "*Files:*\n10 new, 5 changed, 100 unmodified"},
+				{"type": "mrkdwn", "text": "*Retention:*\n7 kept, 2 removed"}
+			]}
+		]
+	}`
+	assert.JSONEq(t, expected, rawBody)
+}
+
+func TestSendNotification_FailureBlocks(t *testing.T) {
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&rawBody))
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		Duration:     30 * time.Second,
+		FailedStep:   "backup",
+		ErrorMessage: "repository not found",
+	}
+
+	_, err := svc.SendNotification(context.Background(), models.SlackConfig{WebhookURL: "https://hooks.slack.com/x"}, msg)
+	require.NoError(t, err)
+
+	expected := `{
+		"text": "❌ Backup Failed on server1",
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "❌ Backup Failed"}},
+			{"type": "section", "fields": [
+				{"type": "mrkdwn", "text": "*Host:*\nserver1"},
+				{"type": "mrkdwn", "text": "*Duration:*\n30s"},
+				{"type": "mrkdwn", "text": "*Failed step:*\nbackup"},
+				{"type": "mrkdwn", "text": "*Error:*\n` + "`repository not found`" + `"}
+			]}
+		]
+	}`
+	assert.JSONEq(t, expected, rawBody)
+}
+
+func TestBuildRequest_RepositorySize(t *testing.T) {
+	req := buildRequest(models.TelegramMessage{Success: true, RepoTotalSize: 5 << 30, SnapshotCount: 42})
+
+	fields := req.Blocks[1].Fields
+	assert.Equal(t, "*Repository size:*\n5.0 GiB", fields[len(fields)-2].Text)
+	assert.Equal(t, "*Snapshots in repo:*\n42", fields[len(fields)-1].Text)
+}
+
+func TestBuildRequest_LongError(t *testing.T) {
+	msg := models.TelegramMessage{
+		FailedStep:   "backup",
+		ErrorMessage: "restic backup failed: " + strings.Repeat("x", 5000),
+	}
+
+	req := buildRequest(msg)
+
+	fields := req.Blocks[1].Fields
+	errorText := fields[len(fields)-1].Text
+	assert.Equal(t, maxFieldText, utf8.RuneCountInString(errorText))
+	assert.True(t, strings.HasPrefix(errorText, "*Error:*\n`restic backup failed: "))
+	assert.True(t, strings.HasSuffix(errorText, "…`"))
+}
+
+func TestSendNotification_NoHTMLEscaping(t *testing.T) {
+	var rawBody string
+	svc := NewWithClient(testLogger(), captureClient(&rawBody))
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "web<1>&db",
+		FailedStep:   "backup",
+		ErrorMessage: "exit status 1: <nil>",
+	}
+
+	_, err := svc.SendNotification(context.Background(), testConfig(), msg)
+	require.NoError(t, err)
+
+	assert.Contains(t, rawBody, "web<1>&db")
+	assert.Contains(t, rawBody, "<nil>")
+	assert.NotContains(t, rawBody, "&lt;")
+	assert.NotContains(t, rawBody, `\u003c`)
+
+	var decoded webhookRequest
+	require.NoError(t, json.Unmarshal([]byte(rawBody), &decoded))
+	assert.Equal(t, "*Host:*\nweb<1>&db", decoded.Blocks[1].Fields[0].Text)
+}
+
+func TestSendNotification_HTTPError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.SendNotification(context.Background(), testConfig(), models.TelegramMessage{})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Contains(t, result.Error.Error(), "connection refused")
+}
+
+func TestSendNotification_APIError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       io.NopCloser(strings.NewReader("invalid_token")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.SendNotification(context.Background(), testConfig(), models.TelegramMessage{})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Contains(t, result.Error.Error(), "403")
+}
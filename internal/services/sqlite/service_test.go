@@ -0,0 +1,165 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecutor struct {
+	calls       [][]string
+	executeFunc func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+func (m *mockExecutor) Execute(ctx context.Context, name string, args ...string) ([]byte, error) {
+	m.calls = append(m.calls, append([]string{name}, args...))
+	if m.executeFunc != nil {
+		return m.executeFunc(ctx, name, args...)
+	}
+	return nil, nil
+}
+
+// writeSnapshot emulates sqlite3 by creating the file named in the .backup command.
+func writeSnapshot(_ context.Context, _ string, args ...string) ([]byte, error) {
+	path := strings.Trim(strings.TrimPrefix(args[1], ".backup "), "'")
+	return nil, os.WriteFile(path, []byte("SQLite format 3"), 0o600)
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+// createDatabases creates empty database files under a temp dir and returns their paths.
+func createDatabases(t *testing.T, names ...string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("SQLite format 3"), 0o600))
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestBackup_Success(t *testing.T) {
+	outputDir := t.TempDir()
+	executor := &mockExecutor{executeFunc: writeSnapshot}
+	dbs := createDatabases(t, "vaultwarden/db.sqlite3", "kuma/kuma.db")
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Backup(context.Background(), models.SQLiteConfig{
+		Databases: dbs,
+		OutputDir: outputDir,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Nil(t, result.Error)
+
+	first := filepath.Join(outputDir, GetOutputFilename(dbs[0]))
+	second := filepath.Join(outputDir, GetOutputFilename(dbs[1]))
+	assert.Equal(t, []string{first, second}, result.OutputPaths)
+	assert.Equal(t, int64(30), result.SizeBytes)
+
+	require.Len(t, executor.calls, 2)
+	assert.Equal(t, []string{"sqlite3", dbs[0], ".backup '" + first + "'"}, executor.calls[0])
+	assert.Equal(t, []string{"sqlite3", dbs[1], ".backup '" + second + "'"}, executor.calls[1])
+}
+
+func TestBackup_ExecutorError(t *testing.T) {
+	outputDir := t.TempDir()
+	dbs := createDatabases(t, "ok.db", "broken.db", "never.db")
+	executor := &mockExecutor{
+		executeFunc: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			if args[0] == dbs[1] {
+				return []byte("Error: database is locked\n"), errors.New("exit status 1")
+			}
+			return writeSnapshot(ctx, name, args...)
+		},
+	}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Backup(context.Background(), models.SQLiteConfig{
+		Databases: dbs,
+		OutputDir: outputDir,
+	})
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), dbs[1])
+	assert.Contains(t, result.Error.Error(), "database is locked")
+
+	// Completed snapshots are reported so the caller can clean them up
+	assert.Equal(t, []string{filepath.Join(outputDir, GetOutputFilename(dbs[0]))}, result.OutputPaths)
+	assert.Len(t, executor.calls, 2)
+}
+
+func TestBackup_CreatesOutputDir(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "nested", "sqlite")
+
+	dbs := createDatabases(t, "app.db")
+
+	svc := NewWithExecutor(testLogger(), &mockExecutor{executeFunc: writeSnapshot})
+	result, err := svc.Backup(context.Background(), models.SQLiteConfig{
+		Databases: dbs,
+		OutputDir: outputDir,
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.FileExists(t, filepath.Join(outputDir, GetOutputFilename(dbs[0])))
+}
+
+func TestBackup_MissingDatabase(t *testing.T) {
+	outputDir := t.TempDir()
+	dbs := createDatabases(t, "app.db")
+	missing := filepath.Join(filepath.Dir(dbs[0]), "typo.db")
+	executor := &mockExecutor{executeFunc: writeSnapshot}
+
+	svc := NewWithExecutor(testLogger(), executor)
+	result, err := svc.Backup(context.Background(), models.SQLiteConfig{
+		Databases: []string{dbs[0], missing},
+		OutputDir: outputDir,
+	})
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "sqlite database "+missing+" does not exist")
+	assert.Empty(t, executor.calls, "no database is backed up when one is missing")
+	assert.NoFileExists(t, missing, "sqlite3 must not create the missing database")
+}
+
+func TestBackupCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{"simple path", "/tmp/app.db", ".backup '/tmp/app.db'"},
+		{"path with spaces", "/tmp/my app.db", ".backup '/tmp/my app.db'"},
+		{"path with quote", "/tmp/bob's.db", `.backup "/tmp/bob's.db"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, BackupCommand(tt.path))
+		})
+	}
+}
+
+func TestGetOutputFilename(t *testing.T) {
+	assert.Equal(t, "srv_app1_data.db", GetOutputFilename("/srv/app1/data.db"))
+	assert.Equal(t, "srv_app2_data.db", GetOutputFilename("/srv/app2/data.db"))
+	assert.Equal(t, "data.db", GetOutputFilename("data.db"))
+}
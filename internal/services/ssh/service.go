@@ -5,12 +5,14 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	"github.com/rs/zerolog"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 // Service defines the interface for SSH operations.
@@ -36,6 +38,16 @@ type ClientFactory interface {
 	NewClient(network, addr string, config *ssh.ClientConfig) (Client, error)
 }
 
+// Dialer allows mocking TCP connections used to verify the host is down.
+type Dialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// HTTPClient allows mocking HTTP requests used to verify the host is down.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 // DefaultClientFactory is the default SSH client factory.
 type DefaultClientFactory struct{}
 
@@ -79,21 +91,27 @@ func (s *defaultSession) Close() error {
 // Impl implements the SSH Service interface.
 type Impl struct {
 	clientFactory ClientFactory
+	dialer        Dialer
+	httpClient    HTTPClient
 	logger        zerolog.Logger
 }
 
 // New creates a new SSH service.
 func New(logger zerolog.Logger) *Impl {
-	return &Impl{
-		clientFactory: &DefaultClientFactory{},
-		logger:        logger,
-	}
+	return NewWithClientFactory(logger, &DefaultClientFactory{})
 }
 
 // NewWithClientFactory creates a new SSH service with a custom client factory (for testing).
 func NewWithClientFactory(logger zerolog.Logger, factory ClientFactory) *Impl {
+	return NewWithClients(logger, factory, &net.Dialer{Timeout: 5 * time.Second}, &http.Client{Timeout: 5 * time.Second})
+}
+
+// NewWithClients creates a new SSH service with custom clients (for testing).
+func NewWithClients(logger zerolog.Logger, factory ClientFactory, dialer Dialer, httpClient HTTPClient) *Impl {
 	return &Impl{
 		clientFactory: factory,
+		dialer:        dialer,
+		httpClient:    httpClient,
 		logger:        logger,
 	}
 }
@@ -120,16 +138,39 @@ func (s *Impl) buildConfig(cfg models.SSHShutdownConfig) (*ssh.ClientConfig, err
 		return nil, fmt.Errorf("failed to parse private key: %w", err)
 	}
 
+	hostKeyCallback, err := s.hostKeyCallback(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ssh.ClientConfig{
 		User: cfg.Username,
 		Auth: []ssh.AuthMethod{
 			ssh.PublicKeys(signer),
 		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // homelab environment
+		HostKeyCallback: hostKeyCallback,
 		Timeout:         30 * time.Second,
 	}, nil
 }
 
+// hostKeyCallback verifies host keys against known_hosts when strict checking
+// is enabled. Otherwise any key is accepted, which is kept for backwards
+// compatibility.
+func (s *Impl) hostKeyCallback(cfg models.SSHShutdownConfig) (ssh.HostKeyCallback, error) {
+	if !cfg.StrictHostKeyChecking {
+		s.logger.Warn().
+			Str("host", cfg.Host).
+			Msg("SSH host key verification disabled, set ssh_shutdown.strict_host_key_checking to enable it")
+		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // opt-in verification for backwards compatibility
+	}
+
+	callback, err := knownhosts.New(cfg.KnownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts from %s: %w", cfg.KnownHostsPath, err)
+	}
+	return callback, nil
+}
+
 // Shutdown initiates a system shutdown via SSH.
 func (s *Impl) Shutdown(ctx context.Context, cfg models.SSHShutdownConfig) (*models.SSHResult, error) {
 	result := &models.SSHResult{}
@@ -139,6 +180,7 @@ func (s *Impl) Shutdown(ctx context.Context, cfg models.SSHShutdownConfig) (*mod
 		Int("port", cfg.Port).
 		Str("user", cfg.Username).
 		Int("delay", cfg.ShutdownDelay).
+		Str("action", cfg.Action).
 		Msg("initiating remote shutdown")
 
 	sshConfig, err := s.buildConfig(cfg)
@@ -177,6 +219,11 @@ func (s *Impl) Shutdown(ctx context.Context, cfg models.SSHShutdownConfig) (*mod
 	}
 	defer func() { _ = client.Close() }()
 
+	if err := s.runPreShutdownCommands(client, cfg, result); err != nil {
+		result.Error = err
+		return result, nil
+	}
+
 	session, err := client.NewSession()
 	if err != nil {
 		result.Error = fmt.Errorf("failed to create session: %w", err)
@@ -184,22 +231,7 @@ func (s *Impl) Shutdown(ctx context.Context, cfg models.SSHShutdownConfig) (*mod
 	}
 	defer func() { _ = session.Close() }()
 
-	// Build shutdown command based on OS
-	var cmd string
-	if cfg.OS == "windows" {
-		// Windows: shutdown /s /t <seconds>
-		delaySeconds := cfg.ShutdownDelay * 60 // Convert minutes to seconds
-		if delaySeconds == 0 {
-			delaySeconds = 60 // Default 60 seconds for safety
-		}
-		cmd = fmt.Sprintf("shutdown /s /t %d", delaySeconds)
-	} else {
-		// Linux/Unix: sudo shutdown -h +<minutes>
-		cmd = fmt.Sprintf("sudo shutdown -h +%d", cfg.ShutdownDelay)
-		if cfg.ShutdownDelay == 0 {
-			cmd = "sudo shutdown -h now"
-		}
-	}
+	cmd := buildShutdownCommand(cfg)
 
 	s.logger.Debug().Str("command", cmd).Msg("executing shutdown command")
 
@@ -223,9 +255,149 @@ func (s *Impl) Shutdown(ctx context.Context, cfg models.SSHShutdownConfig) (*mod
 		Str("output", result.Output).
 		Msg("shutdown command completed")
 
+	if cfg.VerifyDown && result.Error == nil {
+		result.Confirmed = s.waitForDown(ctx, cfg)
+	}
+
 	return result, nil
 }
 
+// waitForDown polls the host until it stops responding or VerifyTimeout passes.
+func (s *Impl) waitForDown(ctx context.Context, cfg models.SSHShutdownConfig) bool {
+	deadline := time.Now().Add(cfg.VerifyTimeout)
+
+	s.logger.Info().
+		Str("host", cfg.Host).
+		Str("url", cfg.VerifyDownURL).
+		Dur("timeout", cfg.VerifyTimeout).
+		Msg("waiting for host to go down")
+
+	for {
+		// A failed probe only means "down" if it was not cut short by a
+		// cancelled or expired context
+		if ctx.Err() != nil {
+			s.logger.Warn().Err(ctx.Err()).Str("host", cfg.Host).Msg("shutdown verification cancelled")
+			return false
+		}
+		reachable := s.isReachable(ctx, cfg)
+		if ctx.Err() != nil {
+			s.logger.Warn().Err(ctx.Err()).Str("host", cfg.Host).Msg("shutdown verification cancelled")
+			return false
+		}
+		if !reachable {
+			return true
+		}
+
+		if time.Now().After(deadline) {
+			s.logger.Warn().Str("host", cfg.Host).Msg("host still reachable after shutdown")
+			return false
+		}
+
+		s.logger.Debug().Msg("host still up")
+
+		// Wait before next poll
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(cfg.VerifyInterval):
+		}
+	}
+}
+
+// isReachable reports whether the host still answers HTTP on VerifyDownURL,
+// or TCP on its SSH port when no URL is configured.
+func (s *Impl) isReachable(ctx context.Context, cfg models.SSHShutdownConfig) bool {
+	if cfg.VerifyDownURL != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.VerifyDownURL, nil)
+		if err != nil {
+			return false
+		}
+		resp, err := s.httpClient.Do(req)
+		if err != nil {
+			return false
+		}
+		_ = resp.Body.Close()
+		// Any response means the host is still up
+		return true
+	}
+
+	addr := net.JoinHostPort(cfg.Host, fmt.Sprintf("%d", cfg.Port))
+	conn, err := s.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// runPreShutdownCommands runs each pre-shutdown command in its own session and
+// records the outputs in result. The first failure is returned unless
+// cfg.IgnorePreErrors is set.
+func (s *Impl) runPreShutdownCommands(client Client, cfg models.SSHShutdownConfig, result *models.SSHResult) error {
+	for _, cmd := range cfg.PreShutdownCommands {
+		s.logger.Info().Str("command", cmd).Msg("running pre-shutdown command")
+
+		cmdResult := models.SSHCommandResult{Command: cmd}
+		output, err := runCommand(client, cmd)
+		cmdResult.Output = string(output)
+		cmdResult.Error = err
+		result.PreCommands = append(result.PreCommands, cmdResult)
+
+		if err == nil {
+			continue
+		}
+		if !cfg.IgnorePreErrors {
+			return fmt.Errorf("pre-shutdown command %q failed, shutdown aborted: %w", cmd, err)
+		}
+		s.logger.Warn().Err(err).Str("command", cmd).Str("output", cmdResult.Output).Msg("pre-shutdown command failed, continuing")
+	}
+	return nil
+}
+
+// runCommand runs cmd in a new session on client.
+func runCommand(client Client, cmd string) ([]byte, error) {
+	session, err := client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	return session.CombinedOutput(cmd)
+}
+
+// buildShutdownCommand returns the configured command, or builds one from the
+// action and OS.
+func buildShutdownCommand(cfg models.SSHShutdownConfig) string {
+	if cfg.ShutdownCommand != "" {
+		return cfg.ShutdownCommand
+	}
+
+	reboot := cfg.Action == models.SSHActionReboot
+
+	if cfg.OS == "windows" {
+		// Windows: shutdown /s|/r /t <seconds>
+		delaySeconds := cfg.ShutdownDelay * 60 // Convert minutes to seconds
+		if delaySeconds == 0 {
+			delaySeconds = 60 // Default 60 seconds for safety
+		}
+		flag := "/s"
+		if reboot {
+			flag = "/r"
+		}
+		return fmt.Sprintf("shutdown %s /t %d", flag, delaySeconds)
+	}
+
+	// Linux/Unix: sudo shutdown -h|-r +<minutes>
+	flag := "-h"
+	if reboot {
+		flag = "-r"
+	}
+	if cfg.ShutdownDelay == 0 {
+		return fmt.Sprintf("sudo shutdown %s now", flag)
+	}
+	return fmt.Sprintf("sudo shutdown %s +%d", flag, cfg.ShutdownDelay)
+}
+
 // TestConnection verifies SSH connectivity without executing shutdown.
 func (s *Impl) TestConnection(ctx context.Context, cfg models.SSHShutdownConfig) (*models.SSHResult, error) {
 	result := &models.SSHResult{}
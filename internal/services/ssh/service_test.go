@@ -7,7 +7,12 @@ import (
 	"encoding/pem"
 	"errors"
 	"io"
+	"net"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 )
 
 // Mock implementations.
@@ -68,6 +74,29 @@ func (m *mockClientFactory) NewClient(network, addr string, config *ssh.ClientCo
 	return &mockClient{}, nil
 }
 
+// mockDialer accepts the first upPolls connections and refuses all later ones.
+type mockDialer struct {
+	upPolls int32
+	calls   atomic.Int32
+}
+
+func (m *mockDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if m.calls.Add(1) > m.upPolls {
+		return nil, errors.New("connection refused")
+	}
+	client, server := net.Pipe()
+	_ = server.Close()
+	return client, nil
+}
+
+type mockHTTPClient struct {
+	doFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return m.doFunc(req)
+}
+
 func testLogger() zerolog.Logger {
 	return zerolog.New(io.Discard)
 }
@@ -156,6 +185,140 @@ func TestShutdown_ImmediateShutdown(t *testing.T) {
 	assert.Equal(t, "sudo shutdown -h now", capturedCommand)
 }
 
+func TestShutdown_CustomCommand(t *testing.T) {
+	var capturedCommand string
+
+	factory := &mockClientFactory{
+		newClientFunc: func(network, addr string, config *ssh.ClientConfig) (Client, error) {
+			return &mockClient{
+				newSessionFunc: func() (Session, error) {
+					return &mockSession{
+						combinedOutputFunc: func(cmd string) ([]byte, error) {
+							capturedCommand = cmd
+							return []byte(""), nil
+						},
+					}, nil
+				},
+			}, nil
+		},
+	}
+
+	svc := NewWithClientFactory(testLogger(), factory)
+	cfg := testConfig(t)
+	cfg.Action = models.SSHActionReboot
+	cfg.ShutdownCommand = "systemctl poweroff"
+
+	result, err := svc.Shutdown(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, result.CommandRun)
+	assert.Equal(t, "systemctl poweroff", capturedCommand)
+}
+
+// recordingFactory returns a factory whose sessions record every command in
+// order and fail for commands listed in failing.
+func recordingFactory(commands *[]string, failing map[string]bool) *mockClientFactory {
+	return &mockClientFactory{
+		newClientFunc: func(network, addr string, config *ssh.ClientConfig) (Client, error) {
+			return &mockClient{
+				newSessionFunc: func() (Session, error) {
+					return &mockSession{
+						combinedOutputFunc: func(cmd string) ([]byte, error) {
+							*commands = append(*commands, cmd)
+							if failing[cmd] {
+								return []byte(cmd + ": not found"), errors.New("Process exited with status 1")
+							}
+							return []byte(cmd + " ok"), nil
+						},
+					}, nil
+				},
+			}, nil
+		},
+	}
+}
+
+func TestShutdown_PreShutdownCommands(t *testing.T) {
+	var commands []string
+	svc := NewWithClientFactory(testLogger(), recordingFactory(&commands, nil))
+
+	cfg := testConfig(t)
+	cfg.PreShutdownCommands = []string{"docker stop immich", "sync"}
+
+	result, err := svc.Shutdown(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.CommandRun)
+	assert.Equal(t, []string{"docker stop immich", "sync", "sudo shutdown -h +1"}, commands)
+
+	require.Len(t, result.PreCommands, 2)
+	assert.Equal(t, "docker stop immich", result.PreCommands[0].Command)
+	assert.Equal(t, "docker stop immich ok", result.PreCommands[0].Output)
+	assert.Nil(t, result.PreCommands[0].Error)
+	assert.Equal(t, "sync ok", result.PreCommands[1].Output)
+}
+
+func TestShutdown_PreShutdownCommandFailureAborts(t *testing.T) {
+	var commands []string
+	svc := NewWithClientFactory(testLogger(), recordingFactory(&commands, map[string]bool{"docker stop immich": true}))
+
+	cfg := testConfig(t)
+	cfg.PreShutdownCommands = []string{"docker stop immich", "sync"}
+
+	result, err := svc.Shutdown(context.Background(), cfg)
+
+	require.NoError(t, err)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "shutdown aborted")
+	assert.False(t, result.CommandRun)
+	// Neither later commands nor the shutdown were run
+	assert.Equal(t, []string{"docker stop immich"}, commands)
+	require.Len(t, result.PreCommands, 1)
+	assert.Equal(t, "docker stop immich: not found", result.PreCommands[0].Output)
+	assert.Error(t, result.PreCommands[0].Error)
+}
+
+func TestShutdown_PreShutdownCommandFailureIgnored(t *testing.T) {
+	var commands []string
+	svc := NewWithClientFactory(testLogger(), recordingFactory(&commands, map[string]bool{"docker stop immich": true}))
+
+	cfg := testConfig(t)
+	cfg.PreShutdownCommands = []string{"docker stop immich", "sync"}
+	cfg.IgnorePreErrors = true
+
+	result, err := svc.Shutdown(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.CommandRun)
+	assert.Equal(t, []string{"docker stop immich", "sync", "sudo shutdown -h +1"}, commands)
+	require.Len(t, result.PreCommands, 2)
+	assert.Error(t, result.PreCommands[0].Error)
+}
+
+func TestBuildShutdownCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      models.SSHShutdownConfig
+		expected string
+	}{
+		{"linux shutdown", models.SSHShutdownConfig{OS: "linux", ShutdownDelay: 2}, "sudo shutdown -h +2"},
+		{"linux shutdown now", models.SSHShutdownConfig{OS: "linux"}, "sudo shutdown -h now"},
+		{"linux reboot", models.SSHShutdownConfig{OS: "linux", Action: models.SSHActionReboot, ShutdownDelay: 1}, "sudo shutdown -r +1"},
+		{"linux reboot now", models.SSHShutdownConfig{OS: "linux", Action: models.SSHActionReboot}, "sudo shutdown -r now"},
+		{"windows shutdown", models.SSHShutdownConfig{OS: "windows", ShutdownDelay: 2}, "shutdown /s /t 120"},
+		{"windows reboot", models.SSHShutdownConfig{OS: "windows", Action: models.SSHActionReboot, ShutdownDelay: 1}, "shutdown /r /t 60"},
+		{"windows reboot default delay", models.SSHShutdownConfig{OS: "windows", Action: models.SSHActionReboot}, "shutdown /r /t 60"},
+		{"custom command", models.SSHShutdownConfig{OS: "windows", ShutdownCommand: "poweroff"}, "poweroff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildShutdownCommand(tt.cfg))
+		})
+	}
+}
+
 func TestShutdown_ConnectionFailed(t *testing.T) {
 	factory := &mockClientFactory{
 		newClientFunc: func(network, addr string, config *ssh.ClientConfig) (Client, error) {
@@ -332,3 +495,178 @@ func TestBuildConfig_KeyPathNotFound(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to read private key")
 }
+
+// generateHostKey returns a fresh ed25519 host public key.
+func generateHostKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	require.NoError(t, err)
+	return sshPub
+}
+
+// writeKnownHosts writes a known_hosts file containing key for host.
+func writeKnownHosts(t *testing.T, host string, key ssh.PublicKey) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	line := knownhosts.Line([]string{knownhosts.Normalize(host)}, key)
+	require.NoError(t, os.WriteFile(path, []byte(line+"\n"), 0o600))
+	return path
+}
+
+func TestBuildConfig_KnownHostsAccepted(t *testing.T) {
+	hostKey := generateHostKey(t)
+
+	cfg := testConfig(t)
+	cfg.StrictHostKeyChecking = true
+	cfg.KnownHostsPath = writeKnownHosts(t, "192.168.1.100:22", hostKey)
+
+	svc := NewWithClientFactory(testLogger(), &mockClientFactory{})
+	sshConfig, err := svc.buildConfig(cfg)
+	require.NoError(t, err)
+
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.1.100"), Port: 22}
+	assert.NoError(t, sshConfig.HostKeyCallback("192.168.1.100:22", remote, hostKey))
+}
+
+func TestBuildConfig_KnownHostsMismatchRejected(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.StrictHostKeyChecking = true
+	cfg.KnownHostsPath = writeKnownHosts(t, "192.168.1.100:22", generateHostKey(t))
+
+	svc := NewWithClientFactory(testLogger(), &mockClientFactory{})
+	sshConfig, err := svc.buildConfig(cfg)
+	require.NoError(t, err)
+
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.1.100"), Port: 22}
+	err = sshConfig.HostKeyCallback("192.168.1.100:22", remote, generateHostKey(t))
+
+	require.Error(t, err)
+	var keyErr *knownhosts.KeyError
+	require.ErrorAs(t, err, &keyErr)
+	assert.NotEmpty(t, keyErr.Want, "a mismatch reports the known key")
+}
+
+func TestBuildConfig_KnownHostsNotFound(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.StrictHostKeyChecking = true
+	cfg.KnownHostsPath = "/nonexistent/known_hosts"
+
+	svc := NewWithClientFactory(testLogger(), &mockClientFactory{})
+	_, err := svc.buildConfig(cfg)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load known_hosts")
+}
+
+func TestBuildConfig_InsecureByDefault(t *testing.T) {
+	svc := NewWithClientFactory(testLogger(), &mockClientFactory{})
+	sshConfig, err := svc.buildConfig(testConfig(t))
+	require.NoError(t, err)
+
+	remote := &net.TCPAddr{IP: net.ParseIP("192.168.1.100"), Port: 22}
+	assert.NoError(t, sshConfig.HostKeyCallback("192.168.1.100:22", remote, generateHostKey(t)))
+}
+
+func verifyDownConfig(t *testing.T) models.SSHShutdownConfig {
+	cfg := testConfig(t)
+	cfg.VerifyDown = true
+	cfg.VerifyTimeout = time.Second
+	cfg.VerifyInterval = time.Millisecond
+	return cfg
+}
+
+func TestShutdown_VerifyDownTCP(t *testing.T) {
+	dialer := &mockDialer{upPolls: 3}
+	svc := NewWithClients(testLogger(), &mockClientFactory{}, dialer, nil)
+
+	result, err := svc.Shutdown(context.Background(), verifyDownConfig(t))
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.CommandRun)
+	assert.True(t, result.Confirmed)
+	// Three polls found the host up, the fourth was refused
+	assert.Equal(t, int32(4), dialer.calls.Load())
+}
+
+func TestShutdown_VerifyDownTimeout(t *testing.T) {
+	dialer := &mockDialer{upPolls: 1 << 30}
+	svc := NewWithClients(testLogger(), &mockClientFactory{}, dialer, nil)
+
+	cfg := verifyDownConfig(t)
+	cfg.VerifyTimeout = 20 * time.Millisecond
+
+	result, err := svc.Shutdown(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.CommandRun)
+	assert.False(t, result.Confirmed)
+	assert.Greater(t, dialer.calls.Load(), int32(1))
+}
+
+func TestShutdown_VerifyDownHTTP(t *testing.T) {
+	var polls int
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "http://192.168.1.100:5000/", req.URL.String())
+			polls++
+			if polls > 2 {
+				return nil, errors.New("connection refused")
+			}
+			// Any response, even an error status, means the host is up
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader(""))}, nil
+		},
+	}
+	dialer := &mockDialer{}
+	svc := NewWithClients(testLogger(), &mockClientFactory{}, dialer, httpClient)
+
+	cfg := verifyDownConfig(t)
+	cfg.VerifyDownURL = "http://192.168.1.100:5000/"
+
+	result, err := svc.Shutdown(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, result.Confirmed)
+	assert.Equal(t, 3, polls)
+	assert.Zero(t, dialer.calls.Load(), "TCP is not used when a URL is configured")
+}
+
+func TestShutdown_VerifyDownCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			// The run is cancelled while the probe is in flight
+			cancel()
+			return nil, req.Context().Err()
+		},
+	}
+	svc := NewWithClients(testLogger(), &mockClientFactory{}, &mockDialer{}, httpClient)
+
+	cfg := verifyDownConfig(t)
+	cfg.VerifyDownURL = "http://192.168.1.100:5000/"
+
+	result, err := svc.Shutdown(ctx, cfg)
+
+	require.NoError(t, err)
+	assert.True(t, result.CommandRun)
+	assert.False(t, result.Confirmed, "a cancelled probe does not prove the host is down")
+}
+
+func TestShutdown_NoVerifyDown(t *testing.T) {
+	dialer := &mockDialer{}
+	svc := NewWithClients(testLogger(), &mockClientFactory{}, dialer, nil)
+
+	result, err := svc.Shutdown(context.Background(), testConfig(t))
+
+	require.NoError(t, err)
+	assert.True(t, result.CommandRun)
+	assert.False(t, result.Confirmed)
+	assert.Zero(t, dialer.calls.Load())
+}
@@ -38,6 +38,72 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 	return &MockService_Expecter{mock: &_m.Mock}
 }
 
+// GetMe provides a mock function for the type MockService
+func (_mock *MockService) GetMe(ctx context.Context, cfg models.TelegramConfig) (string, error) {
+	ret := _mock.Called(ctx, cfg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMe")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.TelegramConfig) (string, error)); ok {
+		return returnFunc(ctx, cfg)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, models.TelegramConfig) string); ok {
+		r0 = returnFunc(ctx, cfg)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, models.TelegramConfig) error); ok {
+		r1 = returnFunc(ctx, cfg)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetMe_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMe'
+type MockService_GetMe_Call struct {
+	*mock.Call
+}
+
+// GetMe is a helper method to define mock.On call
+//   - ctx context.Context
+//   - cfg models.TelegramConfig
+func (_e *MockService_Expecter) GetMe(ctx interface{}, cfg interface{}) *MockService_GetMe_Call {
+	return &MockService_GetMe_Call{Call: _e.mock.On("GetMe", ctx, cfg)}
+}
+
+func (_c *MockService_GetMe_Call) Run(run func(ctx context.Context, cfg models.TelegramConfig)) *MockService_GetMe_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 models.TelegramConfig
+		if args[1] != nil {
+			arg1 = args[1].(models.TelegramConfig)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetMe_Call) Return(s string, err error) *MockService_GetMe_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockService_GetMe_Call) RunAndReturn(run func(ctx context.Context, cfg models.TelegramConfig) (string, error)) *MockService_GetMe_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SendNotification provides a mock function for the type MockService
 func (_mock *MockService) SendNotification(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) (*models.TelegramResult, error) {
 	ret := _mock.Called(ctx, cfg, msg)
@@ -5,17 +5,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/fgeck/gorestic-homelab/internal/format"
 	"github.com/fgeck/gorestic-homelab/internal/models"
 	"github.com/rs/zerolog"
 )
 
+// Supported Telegram parse modes.
+const (
+	ParseModeHTML       = "HTML"
+	ParseModeMarkdownV2 = "MarkdownV2"
+)
+
 // Service defines the interface for Telegram notification operations.
 type Service interface {
 	SendNotification(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) (*models.TelegramResult, error)
+	GetMe(ctx context.Context, cfg models.TelegramConfig) (string, error)
 }
 
 // HTTPClient allows mocking HTTP requests.
@@ -23,21 +34,30 @@ type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// DefaultHTTPTimeout bounds each Telegram API request unless configured.
+const DefaultHTTPTimeout = 30 * time.Second
+
 // Impl implements the Telegram Service interface.
 type Impl struct {
 	httpClient HTTPClient
 	logger     zerolog.Logger
 	baseURL    string
+	retryDelay time.Duration // initial backoff, doubled after each attempt
 }
 
-// New creates a new Telegram service.
-func New(logger zerolog.Logger) *Impl {
+// New creates a new Telegram service whose API requests time out after
+// httpTimeout, or DefaultHTTPTimeout if it is not positive.
+func New(logger zerolog.Logger, httpTimeout time.Duration) *Impl {
+	if httpTimeout <= 0 {
+		httpTimeout = DefaultHTTPTimeout
+	}
 	return &Impl{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout: httpTimeout,
 		},
-		logger:  logger,
-		baseURL: "https://api.telegram.org",
+		logger:     logger,
+		baseURL:    "https://api.telegram.org",
+		retryDelay: time.Second,
 	}
 }
 
@@ -47,6 +67,7 @@ func NewWithClient(logger zerolog.Logger, httpClient HTTPClient, baseURL string)
 		httpClient: httpClient,
 		logger:     logger,
 		baseURL:    baseURL,
+		retryDelay: time.Second,
 	}
 }
 
@@ -57,71 +78,205 @@ type sendMessageRequest struct {
 	ParseMode string `json:"parse_mode"`
 }
 
-// SendNotification sends a backup notification via Telegram.
+// SendNotification sends a backup notification to every configured Telegram chat.
+// MessageSent is only true if all chats received the message.
 func (s *Impl) SendNotification(ctx context.Context, cfg models.TelegramConfig, msg models.TelegramMessage) (*models.TelegramResult, error) {
 	result := &models.TelegramResult{}
 
 	s.logger.Info().
-		Str("chat_id", cfg.ChatID).
+		Strs("chat_ids", cfg.ChatIDs).
 		Bool("success", msg.Success).
 		Msg("sending Telegram notification")
 
 	// Format message
-	text := s.formatMessage(msg)
-
-	// Build request
-	reqBody := sendMessageRequest{
-		ChatID:    cfg.ChatID,
-		Text:      text,
-		ParseMode: "HTML",
+	parseMode := cfg.ParseMode
+	if parseMode == "" {
+		parseMode = ParseModeHTML
 	}
 
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		result.Error = fmt.Errorf("failed to marshal request: %w", err)
-		return result, nil
+	var text string
+	if parseMode == ParseModeMarkdownV2 {
+		text = s.formatMessageMarkdown(msg)
+	} else {
+		text = s.formatMessage(msg)
 	}
 
 	url := fmt.Sprintf("%s/bot%s/sendMessage", s.baseURL, cfg.BotToken)
 
+	// Messages over Telegram's length limit are sent in several parts
+	parts := splitMessage(text, parseMode)
+
+	var errs []error
+	for _, chatID := range cfg.ChatIDs {
+		if err := s.sendParts(ctx, url, chatID, parts, parseMode, cfg.MaxRetries, result); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+		}
+	}
+
+	if len(cfg.ChatIDs) == 0 {
+		errs = append(errs, fmt.Errorf("no chat IDs configured"))
+	}
+
+	result.Error = errors.Join(errs...)
+	result.MessageSent = result.Error == nil
+
+	return result, nil
+}
+
+// sendParts sends the parts of a message to one chat in order, stopping at the
+// first part that cannot be sent.
+func (s *Impl) sendParts(
+	ctx context.Context,
+	url, chatID string,
+	parts []string,
+	parseMode string,
+	maxRetries int,
+	result *models.TelegramResult,
+) error {
+	for _, part := range parts {
+		jsonBody, err := json.Marshal(sendMessageRequest{
+			ChatID:    chatID,
+			Text:      part,
+			ParseMode: parseMode,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+
+		attempts, err := s.sendWithRetry(ctx, url, jsonBody, maxRetries)
+		result.Attempts += attempts
+		if err != nil {
+			return err
+		}
+		result.MessagesSent++
+	}
+	s.logger.Info().Str("chat_id", chatID).Int("parts", len(parts)).Msg("Telegram notification sent successfully")
+	return nil
+}
+
+// sendWithRetry sends a message, retrying rate-limited and server errors
+// with exponential backoff. It returns the number of attempts made.
+func (s *Impl) sendWithRetry(ctx context.Context, url string, jsonBody []byte, maxRetries int) (int, error) {
+	maxAttempts := max(maxRetries, 1)
+	delay := s.retryDelay
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, retryable, err := s.send(ctx, url, jsonBody)
+		if err == nil {
+			return attempt, nil
+		}
+
+		if !retryable || attempt == maxAttempts {
+			return attempt, err
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		s.logger.Warn().Err(err).
+			Int("attempt", attempt).
+			Str("retry_in", wait.String()).
+			Msg("Telegram notification failed, retrying")
+
+		select {
+		case <-ctx.Done():
+			return attempt, fmt.Errorf("retry aborted: %w", ctx.Err())
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// send performs a single sendMessage request. It reports whether the
+// failure is worth retrying and the delay requested via Retry-After.
+func (s *Impl) send(ctx context.Context, url string, jsonBody []byte) (time.Duration, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
 	if err != nil {
-		result.Error = fmt.Errorf("failed to create request: %w", err)
-		return result, nil
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		result.Error = fmt.Errorf("failed to send request: %w", err)
-		return result, nil
+		return 0, ctx.Err() == nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		result.Error = fmt.Errorf("telegram API returned status %d", resp.StatusCode)
-		return result, nil
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return 0, false, nil
+	case resp.StatusCode == http.StatusTooManyRequests:
+		var retryAfter time.Duration
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return retryAfter, true, fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return 0, true, fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	default:
+		return 0, false, fmt.Errorf("telegram API returned status %d", resp.StatusCode)
 	}
+}
 
-	result.MessageSent = true
-	s.logger.Info().Msg("Telegram notification sent successfully")
+// getMeResponse is the response body of the Telegram getMe API.
+type getMeResponse struct {
+	OK     bool `json:"ok"`
+	Result struct {
+		Username string `json:"username"`
+	} `json:"result"`
+	Description string `json:"description"`
+}
 
-	return result, nil
+// GetMe checks the bot token with the getMe API and returns the bot's username.
+// Nothing is sent to any chat.
+func (s *Impl) GetMe(ctx context.Context, cfg models.TelegramConfig) (string, error) {
+	url := fmt.Sprintf("%s/bot%s/getMe", s.baseURL, cfg.BotToken)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var body getMeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("failed to parse getMe response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK || !body.OK {
+		if body.Description != "" {
+			return "", fmt.Errorf("telegram API returned status %d: %s", resp.StatusCode, body.Description)
+		}
+		return "", fmt.Errorf("telegram API returned status %d", resp.StatusCode)
+	}
+
+	return body.Result.Username, nil
 }
 
 func (s *Impl) formatMessage(msg models.TelegramMessage) string {
 	var b bytes.Buffer
 
+	var marker string
+	if msg.DryRun {
+		marker = " (dry-run)"
+	}
+
 	if msg.Success {
-		b.WriteString("✅ <b>Backup Successful</b>\n\n")
+		fmt.Fprintf(&b, "✅ <b>Backup Successful%s</b>\n\n", marker)
 	} else {
-		b.WriteString("❌ <b>Backup Failed</b>\n\n")
+		fmt.Fprintf(&b, "❌ <b>Backup Failed%s</b>\n\n", marker)
 	}
 
 	// Basic info
-	fmt.Fprintf(&b, "🖥 <b>Host:</b> %s\n", escapeHTML(msg.Host))
-	fmt.Fprintf(&b, "📁 <b>Repository:</b> %s\n", escapeHTML(msg.Repository))
+	fmt.Fprintf(&b, "🖥 <b>Host:</b> %s\n", format.EscapeHTML(msg.Host))
+	fmt.Fprintf(&b, "📁 <b>Repository:</b> %s\n", format.EscapeHTML(msg.Repository))
 	fmt.Fprintf(&b, "⏰ <b>Started:</b> %s\n", msg.StartTime.Format("2006-01-02 15:04:05"))
 	fmt.Fprintf(&b, "⏱ <b>Duration:</b> %s\n", msg.Duration.Round(time.Second))
 
@@ -131,52 +286,138 @@ func (s *Impl) formatMessage(msg models.TelegramMessage) string {
 		fmt.Fprintf(&b, "  • Files new: %d\n", msg.FilesNew)
 		fmt.Fprintf(&b, "  • Files changed: %d\n", msg.FilesChanged)
 		fmt.Fprintf(&b, "  • Files unmodified: %d\n", msg.FilesUnmodified)
-		fmt.Fprintf(&b, "  • Data added: %s\n", formatBytes(msg.DataAdded))
+		fmt.Fprintf(&b, "  • Data added: %s\n", format.Bytes(msg.DataAdded))
 		fmt.Fprintf(&b, "  • Total files: %d\n", msg.TotalFiles)
-		fmt.Fprintf(&b, "  • Total size: %s\n", formatBytes(msg.TotalBytes))
+		fmt.Fprintf(&b, "  • Total size: %s\n", format.Bytes(msg.TotalBytes))
+		if msg.ThroughputBytesPerSec > 0 {
+			fmt.Fprintf(&b, "  • Throughput: %s/s\n", format.Bytes(msg.ThroughputBytesPerSec))
+		}
+		if msg.UnreadableFiles > 0 {
+			fmt.Fprintf(&b, "  • ⚠️ %s\n", unreadableFilesText(msg.UnreadableFiles))
+		}
 
 		if msg.SnapshotsRemoved > 0 || msg.SnapshotsKept > 0 {
 			b.WriteString("\n<b>🗑 Retention:</b>\n")
 			fmt.Fprintf(&b, "  • Snapshots kept: %d\n", msg.SnapshotsKept)
 			fmt.Fprintf(&b, "  • Snapshots removed: %d\n", msg.SnapshotsRemoved)
+			if msg.SpaceFreed > 0 {
+				fmt.Fprintf(&b, "  • Space freed: %s\n", format.Bytes(msg.SpaceFreed))
+			}
+		}
+
+		if msg.RepoTotalSize > 0 {
+			b.WriteString("\n<b>💾 Repository:</b>\n")
+			fmt.Fprintf(&b, "  • Repository size: %s\n", format.Bytes(msg.RepoTotalSize))
+			if msg.RepoFileCount > 0 {
+				fmt.Fprintf(&b, "  • Files: %d\n", msg.RepoFileCount)
+			}
+			if msg.SnapshotCount > 0 {
+				fmt.Fprintf(&b, "  • Snapshots in repo: %d\n", msg.SnapshotCount)
+			}
 		}
 	} else {
 		b.WriteString("\n<b>⚠️ Error Details:</b>\n")
-		fmt.Fprintf(&b, "  • Failed step: %s\n", escapeHTML(msg.FailedStep))
-		fmt.Fprintf(&b, "  • Error: <code>%s</code>\n", escapeHTML(msg.ErrorMessage))
+		fmt.Fprintf(&b, "  • Failed step: %s\n", format.EscapeHTML(msg.FailedStep))
+		fmt.Fprintf(&b, "  • Error: <code>%s</code>\n", format.EscapeHTML(msg.ErrorMessage))
+	}
+
+	if msg.LogExcerpt != "" {
+		b.WriteString(formatLogExcerpt(msg.LogExcerpt, ParseModeHTML))
 	}
 
 	return b.String()
 }
 
-// escapeHTML escapes HTML special characters.
-func escapeHTML(s string) string {
+func (s *Impl) formatMessageMarkdown(msg models.TelegramMessage) string {
 	var b bytes.Buffer
-	for _, r := range s {
-		switch r {
-		case '<':
-			b.WriteString("&lt;")
-		case '>':
-			b.WriteString("&gt;")
-		case '&':
-			b.WriteString("&amp;")
-		default:
-			b.WriteRune(r)
+
+	var marker string
+	if msg.DryRun {
+		marker = escapeMarkdown(" (dry-run)")
+	}
+
+	if msg.Success {
+		fmt.Fprintf(&b, "✅ *Backup Successful%s*\n\n", marker)
+	} else {
+		fmt.Fprintf(&b, "❌ *Backup Failed%s*\n\n", marker)
+	}
+
+	// Basic info
+	fmt.Fprintf(&b, "🖥 *Host:* %s\n", escapeMarkdown(msg.Host))
+	fmt.Fprintf(&b, "📁 *Repository:* %s\n", escapeMarkdown(msg.Repository))
+	fmt.Fprintf(&b, "⏰ *Started:* %s\n", escapeMarkdown(msg.StartTime.Format("2006-01-02 15:04:05")))
+	fmt.Fprintf(&b, "⏱ *Duration:* %s\n", escapeMarkdown(msg.Duration.Round(time.Second).String()))
+
+	if msg.Success {
+		b.WriteString("\n*📊 Backup Statistics:*\n")
+		fmt.Fprintf(&b, "  • Snapshot: `%s`\n", escapeMarkdownCode(msg.SnapshotID))
+		fmt.Fprintf(&b, "  • Files new: %d\n", msg.FilesNew)
+		fmt.Fprintf(&b, "  • Files changed: %d\n", msg.FilesChanged)
+		fmt.Fprintf(&b, "  • Files unmodified: %d\n", msg.FilesUnmodified)
+		fmt.Fprintf(&b, "  • Data added: %s\n", escapeMarkdown(format.Bytes(msg.DataAdded)))
+		fmt.Fprintf(&b, "  • Total files: %d\n", msg.TotalFiles)
+		fmt.Fprintf(&b, "  • Total size: %s\n", escapeMarkdown(format.Bytes(msg.TotalBytes)))
+		if msg.ThroughputBytesPerSec > 0 {
+			fmt.Fprintf(&b, "  • Throughput: %s/s\n", escapeMarkdown(format.Bytes(msg.ThroughputBytesPerSec)))
+		}
+		if msg.UnreadableFiles > 0 {
+			fmt.Fprintf(&b, "  • ⚠️ %s\n", escapeMarkdown(unreadableFilesText(msg.UnreadableFiles)))
+		}
+
+		if msg.SnapshotsRemoved > 0 || msg.SnapshotsKept > 0 {
+			b.WriteString("\n*🗑 Retention:*\n")
+			fmt.Fprintf(&b, "  • Snapshots kept: %d\n", msg.SnapshotsKept)
+			fmt.Fprintf(&b, "  • Snapshots removed: %d\n", msg.SnapshotsRemoved)
+			if msg.SpaceFreed > 0 {
+				fmt.Fprintf(&b, "  • Space freed: %s\n", escapeMarkdown(format.Bytes(msg.SpaceFreed)))
+			}
+		}
+
+		if msg.RepoTotalSize > 0 {
+			b.WriteString("\n*💾 Repository:*\n")
+			fmt.Fprintf(&b, "  • Repository size: %s\n", escapeMarkdown(format.Bytes(msg.RepoTotalSize)))
+			if msg.RepoFileCount > 0 {
+				fmt.Fprintf(&b, "  • Files: %d\n", msg.RepoFileCount)
+			}
+			if msg.SnapshotCount > 0 {
+				fmt.Fprintf(&b, "  • Snapshots in repo: %d\n", msg.SnapshotCount)
+			}
 		}
+	} else {
+		b.WriteString("\n*⚠️ Error Details:*\n")
+		fmt.Fprintf(&b, "  • Failed step: %s\n", escapeMarkdown(msg.FailedStep))
+		fmt.Fprintf(&b, "  • Error: `%s`\n", escapeMarkdownCode(msg.ErrorMessage))
+	}
+
+	if msg.LogExcerpt != "" {
+		b.WriteString(formatLogExcerpt(msg.LogExcerpt, ParseModeMarkdownV2))
 	}
+
 	return b.String()
 }
 
-// formatBytes formats bytes into human-readable format.
-func formatBytes(bytes int64) string {
-	const unit = 1024
-	if bytes < unit {
-		return fmt.Sprintf("%d B", bytes)
-	}
-	div, exp := int64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
-		div *= unit
-		exp++
+// markdownReplacer escapes the characters reserved in MarkdownV2 text.
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`,
+	"=", `\=`, "|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// escapeMarkdown escapes MarkdownV2 special characters.
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+// escapeMarkdownCode escapes text inside a MarkdownV2 code span.
+func escapeMarkdownCode(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "`", "\\`").Replace(s)
+}
+
+// unreadableFilesText describes how many files restic could not back up.
+func unreadableFilesText(n int) string {
+	if n == 1 {
+		return "1 file could not be read"
 	}
-	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+	return fmt.Sprintf("%d files could not be read", n)
 }
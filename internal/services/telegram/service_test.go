@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -37,7 +38,7 @@ func testLogger() zerolog.Logger {
 func testConfig() models.TelegramConfig {
 	return models.TelegramConfig{
 		BotToken: "123456:ABC-DEF",
-		ChatID:   "-100123456789",
+		ChatIDs:  []string{"-100123456789"},
 	}
 }
 
@@ -171,7 +172,7 @@ func TestSendNotification_APIError(t *testing.T) {
 }
 
 func TestFormatMessage_Success(t *testing.T) {
-	svc := New(testLogger())
+	svc := New(testLogger(), 0)
 
 	msg := models.TelegramMessage{
 		Success:          true,
@@ -204,7 +205,7 @@ func TestFormatMessage_Success(t *testing.T) {
 }
 
 func TestFormatMessage_Failure(t *testing.T) {
-	svc := New(testLogger())
+	svc := New(testLogger(), 0)
 
 	msg := models.TelegramMessage{
 		Success:      false,
@@ -223,46 +224,344 @@ func TestFormatMessage_Failure(t *testing.T) {
 	assert.Contains(t, result, "timeout waiting for target")
 }
 
-func TestEscapeHTML(t *testing.T) {
+func TestFormatMessage_DryRun(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:   true,
+		DryRun:    true,
+		Host:      "myserver",
+		StartTime: time.Now(),
+	}
+
+	result := svc.formatMessage(msg)
+
+	assert.Contains(t, result, "Backup Successful (dry-run)")
+}
+
+func TestFormatMessage_RepositorySize(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:       true,
+		Host:          "myserver",
+		StartTime:     time.Now(),
+		RepoTotalSize: 1024 * 1024 * 1024 * 5, // 5 GiB
+		RepoFileCount: 1200,
+	}
+
+	result := svc.formatMessage(msg)
+
+	assert.Contains(t, result, "Repository size: 5.0 GiB")
+	assert.Contains(t, result, "Files: 1200")
+	assert.NotContains(t, result, "Snapshots in repo")
+}
+
+func TestFormatMessage_SnapshotCount(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:       true,
+		Host:          "myserver",
+		StartTime:     time.Now(),
+		RepoTotalSize: 1024,
+		SnapshotCount: 42,
+	}
+
+	assert.Contains(t, svc.formatMessage(msg), "  • Snapshots in repo: 42\n")
+	assert.Contains(t, svc.formatMessageMarkdown(msg), "  • Snapshots in repo: 42\n")
+}
+
+func TestFormatMessage_Throughput(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:               true,
+		Host:                  "myserver",
+		StartTime:             time.Now(),
+		Duration:              time.Minute,
+		ThroughputBytesPerSec: 1024 * 1024 * 25, // 25 MiB/s
+	}
+
+	assert.Contains(t, svc.formatMessage(msg), "Throughput: 25.0 MiB/s")
+	assert.Contains(t, svc.formatMessageMarkdown(msg), "Throughput: 25\\.0 MiB/s")
+
+	// Zero when the backup duration was zero
+	msg.ThroughputBytesPerSec = 0
+	assert.NotContains(t, svc.formatMessage(msg), "Throughput")
+	assert.NotContains(t, svc.formatMessageMarkdown(msg), "Throughput")
+}
+
+func TestFormatMessage_SpaceFreed(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:          true,
+		Host:             "myserver",
+		StartTime:        time.Now(),
+		SnapshotsKept:    30,
+		SnapshotsRemoved: 3,
+		SpaceFreed:       1024 * 1024 * 150, // 150 MiB
+	}
+
+	assert.Contains(t, svc.formatMessage(msg), "Space freed: 150.0 MiB")
+	assert.Contains(t, svc.formatMessageMarkdown(msg), "Space freed: 150\\.0 MiB")
+
+	msg.SpaceFreed = 0
+	assert.NotContains(t, svc.formatMessage(msg), "Space freed")
+}
+
+func TestFormatMessage_UnreadableFiles(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:         true,
+		Host:            "myserver",
+		StartTime:       time.Now(),
+		UnreadableFiles: 3,
+	}
+
+	assert.Contains(t, svc.formatMessage(msg), "3 files could not be read")
+	assert.Contains(t, svc.formatMessageMarkdown(msg), "3 files could not be read")
+
+	msg.UnreadableFiles = 1
+	assert.Contains(t, svc.formatMessage(msg), "1 file could not be read")
+
+	msg.UnreadableFiles = 0
+	assert.NotContains(t, svc.formatMessage(msg), "could not be read")
+}
+
+func TestEscapeMarkdown(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected string
 	}{
 		{"hello", "hello"},
-		{"<script>", "&lt;script&gt;"},
-		{"a & b", "a &amp; b"},
-		{"<>&", "&lt;&gt;&amp;"},
-		{"normal text", "normal text"},
+		{"my_host", `my\_host`},
+		{"*bold*", `\*bold\*`},
+		{"[link]", `\[link\]`},
+		{"1.5 MiB", `1\.5 MiB`},
+		{"rest:http://nas.local:8000/", `rest:http://nas\.local:8000/`},
+		{`back\slash`, `back\\slash`},
+		{"a-b!", `a\-b\!`},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := escapeHTML(tt.input)
-			assert.Equal(t, tt.expected, result)
+			assert.Equal(t, tt.expected, escapeMarkdown(tt.input))
 		})
 	}
 }
 
-func TestFormatBytes(t *testing.T) {
-	tests := []struct {
-		bytes    int64
-		expected string
-	}{
-		{0, "0 B"},
-		{500, "500 B"},
-		{1024, "1.0 KiB"},
-		{1024 * 1024, "1.0 MiB"},
-		{1024 * 1024 * 1024, "1.0 GiB"},
-		{1024 * 1024 * 1024 * 2, "2.0 GiB"},
-		{1536 * 1024, "1.5 MiB"},
+func TestFormatMessageMarkdown_Success(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:    true,
+		Host:       "my_server.local",
+		Repository: "/backup",
+		Duration:   5 * time.Minute,
+		SnapshotID: "abc_123",
+		DataAdded:  1536 * 1024,
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.expected, func(t *testing.T) {
-			result := formatBytes(tt.bytes)
-			assert.Equal(t, tt.expected, result)
-		})
+	result := svc.formatMessageMarkdown(msg)
+
+	assert.Contains(t, result, "*Backup Successful*")
+	assert.Contains(t, result, `*Host:* my\_server\.local`)
+	assert.Contains(t, result, "Snapshot: `abc_123`")
+	assert.Contains(t, result, `Data added: 1\.5 MiB`)
+	assert.NotContains(t, result, "<b>")
+}
+
+func TestFormatMessageMarkdown_Failure(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		FailedStep:   "pre_hook",
+		ErrorMessage: "exit `status` 1",
 	}
+
+	result := svc.formatMessageMarkdown(msg)
+
+	assert.Contains(t, result, "*Backup Failed*")
+	assert.Contains(t, result, `Failed step: pre\_hook`)
+	assert.Contains(t, result, "Error: `exit \\`status\\` 1`")
+}
+
+func TestSendNotification_MarkdownV2ParseMode(t *testing.T) {
+	var capturedBody sendMessageRequest
+
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(body, &capturedBody)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true}`)),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient, "https://api.telegram.org")
+	cfg := testConfig()
+	cfg.ParseMode = ParseModeMarkdownV2
+
+	_, err := svc.SendNotification(context.Background(), cfg, models.TelegramMessage{Success: true, Host: "server1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "MarkdownV2", capturedBody.ParseMode)
+	assert.Contains(t, capturedBody.Text, "*Backup Successful*")
+}
+
+// sequenceClient returns the given status codes in order, then 200.
+func sequenceClient(calls *int, statuses ...int) *mockHTTPClient {
+	return &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			status := http.StatusOK
+			if *calls < len(statuses) {
+				status = statuses[*calls]
+			}
+			*calls++
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"Retry-After": []string{"0"}},
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}, nil
+		},
+	}
+}
+
+func TestSendNotification_RetriesThenSucceeds(t *testing.T) {
+	var calls int
+	svc := NewWithClient(testLogger(), sequenceClient(&calls, http.StatusTooManyRequests, http.StatusBadGateway), "https://api.telegram.org")
+	svc.retryDelay = time.Millisecond
+
+	cfg := testConfig()
+	cfg.MaxRetries = 3
+
+	result, err := svc.SendNotification(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, 3, calls)
+}
+
+func TestSendNotification_RetriesExhausted(t *testing.T) {
+	var calls int
+	svc := NewWithClient(testLogger(), sequenceClient(&calls, 500, 500, 500, 500), "https://api.telegram.org")
+	svc.retryDelay = time.Millisecond
+
+	cfg := testConfig()
+	cfg.MaxRetries = 3
+
+	result, err := svc.SendNotification(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Equal(t, 3, result.Attempts)
+	assert.Equal(t, 3, calls)
+	assert.Contains(t, result.Error.Error(), "500")
+}
+
+func TestSendNotification_NoRetryOnClientError(t *testing.T) {
+	var calls int
+	svc := NewWithClient(testLogger(), sequenceClient(&calls, http.StatusBadRequest), "https://api.telegram.org")
+	svc.retryDelay = time.Millisecond
+
+	cfg := testConfig()
+	cfg.MaxRetries = 3
+
+	result, err := svc.SendNotification(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Equal(t, 1, result.Attempts)
+	assert.Equal(t, 1, calls)
+}
+
+func TestSendNotification_RetryStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			cancel()
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient, "https://api.telegram.org")
+	svc.retryDelay = time.Hour
+
+	cfg := testConfig()
+	cfg.MaxRetries = 3
+
+	result, err := svc.SendNotification(ctx, cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, result.Error, context.Canceled)
+}
+
+func TestSendNotification_MultipleChatsPartialFailure(t *testing.T) {
+	var chats []string
+
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			var body sendMessageRequest
+			raw, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(raw, &body)
+			chats = append(chats, body.ChatID)
+
+			status := http.StatusOK
+			if body.ChatID == "-100family" {
+				status = http.StatusBadRequest
+			}
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient, "https://api.telegram.org")
+	cfg := testConfig()
+	cfg.ChatIDs = []string{"12345", "-100family"}
+
+	result, err := svc.SendNotification(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"12345", "-100family"}, chats)
+	assert.False(t, result.MessageSent)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "chat -100family")
+	assert.NotContains(t, result.Error.Error(), "chat 12345")
+	assert.Equal(t, 2, result.Attempts)
+}
+
+func TestSendNotification_MultipleChatsSuccess(t *testing.T) {
+	var calls int
+	svc := NewWithClient(testLogger(), sequenceClient(&calls), "https://api.telegram.org")
+	cfg := testConfig()
+	cfg.ChatIDs = []string{"12345", "-100family"}
+
+	result, err := svc.SendNotification(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 2, result.MessagesSent)
 }
 
 func TestSendNotification_ContextCancelled(t *testing.T) {
@@ -288,3 +587,245 @@ func TestSendNotification_ContextCancelled(t *testing.T) {
 	assert.False(t, result.MessageSent)
 	assert.NotNil(t, result.Error)
 }
+
+func TestGetMe_Success(t *testing.T) {
+	var capturedRequest *http.Request
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			capturedRequest = req
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":true,"result":{"id":123456,"is_bot":true,"username":"homelab_backup_bot"}}`)),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient, "https://api.telegram.org")
+	username, err := svc.GetMe(context.Background(), testConfig())
+
+	require.NoError(t, err)
+	assert.Equal(t, "homelab_backup_bot", username)
+	assert.Equal(t, http.MethodGet, capturedRequest.Method)
+	assert.Equal(t, "https://api.telegram.org/bot123456:ABC-DEF/getMe", capturedRequest.URL.String())
+}
+
+func TestGetMe_Unauthorized(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"ok":false,"error_code":401,"description":"Unauthorized"}`)),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient, "https://api.telegram.org")
+	_, err := svc.GetMe(context.Background(), testConfig())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 401: Unauthorized")
+}
+
+func TestFormatMessage_LogExcerpt(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		FailedStep:   "backup",
+		ErrorMessage: "backup failed",
+		LogExcerpt:   "Fatal: unable to open repository\n<no such file>",
+	}
+
+	text := svc.formatMessage(msg)
+	assert.Contains(t, text, "<b>📜 Log excerpt:</b>\n<pre>Fatal: unable to open repository\n&lt;no such file&gt;</pre>\n")
+
+	markdown := svc.formatMessageMarkdown(msg)
+	assert.Contains(t, markdown, "*📜 Log excerpt:*\n```\nFatal: unable to open repository\n<no such file>\n```\n")
+}
+
+func TestFormatLogExcerpt_TruncatesToMessageLimit(t *testing.T) {
+	lines := make([]string, 500)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %03d: pack a1b2c3 & friends could not be saved", i)
+	}
+
+	for _, parseMode := range []string{ParseModeHTML, ParseModeMarkdownV2} {
+		t.Run(parseMode, func(t *testing.T) {
+			block := formatLogExcerpt(strings.Join(lines, "\n"), parseMode)
+
+			assert.LessOrEqual(t, textLength(block), maxMessageLength)
+			assert.Contains(t, block, "line 499:")
+			assert.NotContains(t, block, "line 000:")
+		})
+	}
+}
+
+func TestFormatLogExcerpt_TruncatesLongLine(t *testing.T) {
+	line := strings.Repeat("<", 3000) + strings.Repeat("x", 3000) + "end"
+
+	block := formatLogExcerpt(line, ParseModeHTML)
+
+	assert.LessOrEqual(t, textLength(block), maxMessageLength)
+	assert.True(t, strings.HasSuffix(block, "xend</pre>\n"))
+	assert.NotContains(t, block, "&l</pre>")
+}
+
+func TestSplitMessage_Short(t *testing.T) {
+	assert.Equal(t, []string{"short message"}, splitMessage("short message", ParseModeHTML))
+}
+
+func TestSplitMessage_HTMLReopensBlocks(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("❌ <b>Backup Failed</b>\n\n  • Error: <code>")
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&b, "error line %03d with &amp; entity\n", i)
+	}
+	b.WriteString("</code>\n\n<b>📜 Log excerpt:</b>\n<pre>")
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&b, "log line %03d\n", i)
+	}
+	b.WriteString("</pre>\n")
+	text := b.String()
+
+	chunks := splitMessage(text, ParseModeHTML)
+
+	require.Greater(t, len(chunks), 1)
+	var joined string
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, textLength(chunk), maxMessageLength)
+		assert.Equal(t, strings.Count(chunk, "<code>"), strings.Count(chunk, "</code>"), chunk)
+		assert.Equal(t, strings.Count(chunk, "<pre>"), strings.Count(chunk, "</pre>"), chunk)
+		joined += chunk
+	}
+	assert.Contains(t, joined, "error line 299")
+	assert.Contains(t, joined, "log line 299")
+}
+
+func TestSplitMessage_LongLine(t *testing.T) {
+	text := "<code>" + strings.Repeat("a&amp;b", 2000) + "</code>\n"
+
+	chunks := splitMessage(text, ParseModeHTML)
+
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, textLength(chunk), maxMessageLength)
+		assert.True(t, strings.HasPrefix(chunk, "<code>"), chunk[:20])
+		assert.True(t, strings.HasSuffix(strings.TrimSuffix(chunk, "\n"), "</code>"))
+		// Entities are never cut in half
+		assert.Equal(t, strings.Count(chunk, "&"), strings.Count(chunk, "&amp;"))
+	}
+}
+
+func TestSplitMessage_MarkdownReopensBlocks(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("❌ *Backup Failed*\n\n*📜 Log excerpt:*\n```\n")
+	for i := 0; i < 400; i++ {
+		fmt.Fprintf(&b, "log line %03d with \\` backtick\n", i)
+	}
+	b.WriteString("```\n")
+
+	chunks := splitMessage(b.String(), ParseModeMarkdownV2)
+
+	require.Greater(t, len(chunks), 1)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, textLength(chunk), maxMessageLength)
+		assert.Equal(t, 0, strings.Count(chunk, "```")%2, chunk)
+	}
+}
+
+func TestSendNotification_SplitsLongMessage(t *testing.T) {
+	var texts []string
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			var body sendMessageRequest
+			raw, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(raw, &body)
+			texts = append(texts, body.Text)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("{\"ok\":true}")),
+			}, nil
+		},
+	}
+	svc := NewWithClient(testLogger(), httpClient, "https://api.telegram.org")
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		FailedStep:   "backup",
+		ErrorMessage: "backup failed: exit status 1, output: " + strings.Repeat("Fatal: repository is damaged\n", 200),
+		LogExcerpt:   strings.Repeat("Fatal: repository is damaged\n", 20),
+	}
+
+	result, err := svc.SendNotification(context.Background(), testConfig(), msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	require.Greater(t, len(texts), 1)
+	assert.Equal(t, len(texts), result.Attempts)
+	assert.Equal(t, len(texts), result.MessagesSent)
+	for _, text := range texts {
+		assert.LessOrEqual(t, textLength(text), maxMessageLength)
+	}
+	assert.Contains(t, texts[len(texts)-1], "Log excerpt")
+}
+
+// longFailureMessage returns a failure message whose text needs three parts.
+func longFailureMessage() models.TelegramMessage {
+	return models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		FailedStep:   "backup",
+		ErrorMessage: strings.Repeat("Fatal: repository is damaged\n", 350),
+	}
+}
+
+func TestSendNotification_LongMessageMultipleChats(t *testing.T) {
+	var calls int
+	svc := NewWithClient(testLogger(), sequenceClient(&calls), "https://api.telegram.org")
+	cfg := testConfig()
+	cfg.ChatIDs = []string{"12345", "-100family"}
+
+	msg := longFailureMessage()
+	parts := splitMessage(svc.formatMessage(msg), ParseModeHTML)
+	require.Len(t, parts, 3)
+
+	result, err := svc.SendNotification(context.Background(), cfg, msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.MessageSent)
+	assert.Equal(t, 6, calls)
+	assert.Equal(t, 6, result.MessagesSent)
+}
+
+func TestSendNotification_LongMessagePartFails(t *testing.T) {
+	var calls int
+	// The second part is rejected; the third is never sent
+	svc := NewWithClient(testLogger(), sequenceClient(&calls, http.StatusOK, http.StatusBadRequest), "https://api.telegram.org")
+
+	result, err := svc.SendNotification(context.Background(), testConfig(), longFailureMessage())
+
+	require.NoError(t, err)
+	assert.False(t, result.MessageSent)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "400")
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 1, result.MessagesSent)
+}
+
+func TestNew_DefaultHTTPTimeout(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	client, ok := svc.httpClient.(*http.Client)
+	require.True(t, ok)
+	assert.Equal(t, 30*time.Second, client.Timeout)
+}
+
+func TestNew_ConfiguredHTTPTimeout(t *testing.T) {
+	svc := New(testLogger(), 12*time.Second)
+
+	client, ok := svc.httpClient.(*http.Client)
+	require.True(t, ok)
+	assert.Equal(t, 12*time.Second, client.Timeout)
+}
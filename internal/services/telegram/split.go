@@ -0,0 +1,196 @@
+package telegram
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/fgeck/gorestic-homelab/internal/format"
+)
+
+// maxMessageLength is Telegram's limit for the text of a single message,
+// counted in UTF-16 code units.
+const maxMessageLength = 4096
+
+// splitReserve leaves room for the markup that closes and reopens code blocks
+// at a chunk boundary.
+const splitReserve = 32
+
+// textLength returns the length of s the way Telegram counts it.
+func textLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// formatLogExcerpt renders the log excerpt as a code block. Leading lines are
+// dropped until the block fits into a single message.
+func formatLogExcerpt(excerpt, parseMode string) string {
+	header, open, closing, escape := "\n<b>📜 Log excerpt:</b>\n", "<pre>", "</pre>\n", format.EscapeHTML
+	if parseMode == ParseModeMarkdownV2 {
+		header, open, closing, escape = "\n*📜 Log excerpt:*\n", "```\n", "\n```\n", escapeMarkdownCode
+	}
+	budget := maxMessageLength - textLength(header+open+closing)
+
+	lines := strings.Split(excerpt, "\n")
+	for len(lines) > 1 && textLength(escape(strings.Join(lines, "\n"))) > budget {
+		lines = lines[1:]
+	}
+	if textLength(escape(lines[0])) > budget {
+		// A single line is too long: keep its end, measuring each rune escaped
+		runes := []rune(lines[0])
+		start, n := len(runes), 0
+		for start > 0 && n+textLength(escape(string(runes[start-1]))) <= budget {
+			start--
+			n += textLength(escape(string(runes[start])))
+		}
+		lines[0] = string(runes[start:])
+	}
+
+	return header + open + escape(strings.Join(lines, "\n")) + closing
+}
+
+// splitMessage splits text into chunks that fit into a single message,
+// breaking at line boundaries where possible. A code block cut in two is
+// closed at the end of one chunk and reopened at the start of the next, so
+// every chunk is valid markup on its own.
+func splitMessage(text, parseMode string) []string {
+	if textLength(text) <= maxMessageLength {
+		return []string{text}
+	}
+
+	limit := maxMessageLength - splitReserve
+	var chunks []string
+	var open []string
+	var cur strings.Builder
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for _, piece := range splitLine(line, limit-splitReserve, parseMode) {
+			if cur.Len() > 0 && textLength(cur.String())+textLength(piece) > limit {
+				cur.WriteString(closeBlocks(open, parseMode))
+				chunks = append(chunks, cur.String())
+				cur.Reset()
+				cur.WriteString(openBlocks(open, parseMode))
+			}
+			cur.WriteString(piece)
+			open = scanBlocks(piece, open, parseMode)
+		}
+	}
+	if strings.TrimSpace(cur.String()) != "" {
+		chunks = append(chunks, cur.String())
+	}
+	return chunks
+}
+
+// splitLine cuts a line into pieces of at most limit, never inside an HTML tag,
+// an HTML entity or a MarkdownV2 escape sequence.
+func splitLine(line string, limit int, parseMode string) []string {
+	var pieces []string
+	for textLength(line) > limit {
+		cut, n := 0, 0
+		for i, r := range line {
+			if n+utf16.RuneLen(r) > limit {
+				break
+			}
+			n += utf16.RuneLen(r)
+			cut = i + utf8.RuneLen(r)
+		}
+		if safe := safeCut(line[:cut], parseMode); safe > 0 {
+			cut = safe
+		}
+		pieces = append(pieces, line[:cut])
+		line = line[cut:]
+	}
+	return append(pieces, line)
+}
+
+// safeCut returns the length of the longest prefix of s that does not end
+// inside a markup construct.
+func safeCut(s, parseMode string) int {
+	if parseMode == ParseModeMarkdownV2 {
+		if backslashes := len(s) - len(strings.TrimRight(s, `\`)); backslashes%2 == 1 {
+			return len(s) - 1
+		}
+		return len(s)
+	}
+	if lt := strings.LastIndexByte(s, '<'); lt > strings.LastIndexByte(s, '>') {
+		s = s[:lt]
+	}
+	if amp := strings.LastIndexByte(s, '&'); amp >= 0 && !strings.Contains(s[amp:], ";") {
+		s = s[:amp]
+	}
+	return len(s)
+}
+
+// scanBlocks updates the stack of open code blocks with the markup in s.
+func scanBlocks(s string, open []string, parseMode string) []string {
+	if parseMode == ParseModeMarkdownV2 {
+		for i := 0; i < len(s); i++ {
+			switch {
+			case s[i] == '\\':
+				i++ // skip the escaped character
+			case strings.HasPrefix(s[i:], "```"):
+				open = toggleBlock(open, "```")
+				i += 2
+			case s[i] == '`':
+				open = toggleBlock(open, "`")
+			}
+		}
+		return open
+	}
+
+	for i := strings.IndexByte(s, '<'); i >= 0; {
+		for _, tag := range []string{"pre", "code"} {
+			switch {
+			case strings.HasPrefix(s[i:], "<"+tag+">"):
+				open = append(open, tag)
+			case strings.HasPrefix(s[i:], "</"+tag+">") && len(open) > 0:
+				open = open[:len(open)-1]
+			}
+		}
+		next := strings.IndexByte(s[i+1:], '<')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return open
+}
+
+// toggleBlock closes marker if it is the innermost open block and opens it otherwise.
+func toggleBlock(open []string, marker string) []string {
+	if len(open) > 0 && open[len(open)-1] == marker {
+		return open[:len(open)-1]
+	}
+	return append(open, marker)
+}
+
+// openBlocks returns the markup reopening the given blocks.
+func openBlocks(open []string, parseMode string) string {
+	var b strings.Builder
+	for _, block := range open {
+		switch {
+		case parseMode != ParseModeMarkdownV2:
+			b.WriteString("<" + block + ">")
+		case block == "```":
+			b.WriteString("```\n")
+		default:
+			b.WriteString(block)
+		}
+	}
+	return b.String()
+}
+
+// closeBlocks returns the markup closing the given blocks, innermost first.
+func closeBlocks(open []string, parseMode string) string {
+	var b strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		if parseMode == ParseModeMarkdownV2 {
+			b.WriteString(open[i])
+		} else {
+			b.WriteString("</" + open[i] + ">")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,145 @@
+// Package webhook provides generic JSON webhook notification services.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+)
+
+// Service defines the interface for webhook notification operations.
+type Service interface {
+	Notify(ctx context.Context, cfg models.WebhookConfig, msg models.TelegramMessage) (*models.WebhookResult, error)
+}
+
+// HTTPClient allows mocking HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Impl implements the webhook Service interface.
+type Impl struct {
+	httpClient HTTPClient
+	logger     zerolog.Logger
+}
+
+// New creates a new webhook service.
+func New(logger zerolog.Logger) *Impl {
+	return &Impl{
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// NewWithClient creates a new webhook service with a custom HTTP client (for testing).
+func NewWithClient(logger zerolog.Logger, httpClient HTTPClient) *Impl {
+	return &Impl{
+		httpClient: httpClient,
+		logger:     logger,
+	}
+}
+
+// payload is the JSON body sent to the webhook.
+type payload struct {
+	Success          bool      `json:"success"`
+	DryRun           bool      `json:"dry_run"`
+	Host             string    `json:"host"`
+	Repository       string    `json:"repository"`
+	StartTime        time.Time `json:"start_time"`
+	DurationSeconds  float64   `json:"duration_seconds"`
+	SnapshotID       string    `json:"snapshot_id,omitempty"`
+	FilesNew         int       `json:"files_new"`
+	FilesChanged     int       `json:"files_changed"`
+	FilesUnmodified  int       `json:"files_unmodified"`
+	DataAdded        int64     `json:"data_added"`
+	TotalFiles       int       `json:"total_files"`
+	TotalBytes       int64     `json:"total_bytes"`
+	SnapshotsKept    int       `json:"snapshots_kept"`
+	SnapshotsRemoved int       `json:"snapshots_removed"`
+	RepoTotalSize    int64     `json:"repo_total_size,omitempty"`
+	RepoFileCount    int       `json:"repo_file_count,omitempty"`
+	SnapshotCount    int       `json:"snapshot_count,omitempty"`
+	FailedStep       string    `json:"failed_step,omitempty"`
+	Error            string    `json:"error,omitempty"`
+}
+
+// Notify sends the backup result as JSON to the configured webhook.
+func (s *Impl) Notify(ctx context.Context, cfg models.WebhookConfig, msg models.TelegramMessage) (*models.WebhookResult, error) {
+	result := &models.WebhookResult{}
+
+	s.logger.Info().
+		Bool("success", msg.Success).
+		Msg("sending webhook notification")
+
+	body, err := json.Marshal(buildPayload(msg))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to marshal payload: %w", err)
+		return result, nil
+	}
+
+	method := cfg.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Errorf("failed to create request: %w", err)
+		return result, nil
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range cfg.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to send request: %w", err)
+		return result, nil
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		result.Error = fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		return result, nil
+	}
+
+	result.Sent = true
+	s.logger.Info().Msg("webhook notification sent successfully")
+
+	return result, nil
+}
+
+func buildPayload(msg models.TelegramMessage) payload {
+	return payload{
+		Success:          msg.Success,
+		DryRun:           msg.DryRun,
+		Host:             msg.Host,
+		Repository:       msg.Repository,
+		StartTime:        msg.StartTime,
+		DurationSeconds:  msg.Duration.Seconds(),
+		SnapshotID:       msg.SnapshotID,
+		FilesNew:         msg.FilesNew,
+		FilesChanged:     msg.FilesChanged,
+		FilesUnmodified:  msg.FilesUnmodified,
+		DataAdded:        msg.DataAdded,
+		TotalFiles:       msg.TotalFiles,
+		TotalBytes:       msg.TotalBytes,
+		SnapshotsKept:    msg.SnapshotsKept,
+		SnapshotsRemoved: msg.SnapshotsRemoved,
+		RepoTotalSize:    msg.RepoTotalSize,
+		RepoFileCount:    msg.RepoFileCount,
+		SnapshotCount:    msg.SnapshotCount,
+		FailedStep:       msg.FailedStep,
+		Error:            msg.ErrorMessage,
+	}
+}
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fgeck/gorestic-homelab/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockHTTPClient struct {
+	doFunc func(req *http.Request) (*http.Response, error)
+}
+
+func (m *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.doFunc != nil {
+		return m.doFunc(req)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func testLogger() zerolog.Logger {
+	return zerolog.New(io.Discard)
+}
+
+func TestNotify_Success(t *testing.T) {
+	var capturedRequest *http.Request
+	var capturedBody map[string]any
+
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			capturedRequest = req
+			body, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(body, &capturedBody)
+			return &http.Response{
+				StatusCode: http.StatusNoContent,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+
+	cfg := models.WebhookConfig{
+		URL:     "https://example.com/hook",
+		Headers: map[string]string{"authorization": "Bearer token"},
+	}
+	msg := models.TelegramMessage{
+		Success:          true,
+		Host:             "server1",
+		Repository:       "/backup",
+		StartTime:        time.Date(2024, 1, 15, 3, 0, 0, 0, time.UTC),
+		Duration:         90 * time.Second,
+		SnapshotID:       "abc123",
+		FilesNew:         10,
+		FilesChanged:     5,
+		DataAdded:        1024,
+		SnapshotsKept:    7,
+		SnapshotsRemoved: 2,
+		SnapshotCount:    9,
+	}
+
+	result, err := svc.Notify(context.Background(), cfg, msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.Sent)
+	assert.Nil(t, result.Error)
+
+	assert.Equal(t, http.MethodPost, capturedRequest.Method)
+	assert.Equal(t, "https://example.com/hook", capturedRequest.URL.String())
+	assert.Equal(t, "application/json", capturedRequest.Header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", capturedRequest.Header.Get("Authorization"))
+
+	assert.Equal(t, true, capturedBody["success"])
+	assert.Equal(t, "server1", capturedBody["host"])
+	assert.Equal(t, "/backup", capturedBody["repository"])
+	assert.Equal(t, "2024-01-15T03:00:00Z", capturedBody["start_time"])
+	assert.InDelta(t, 90.0, capturedBody["duration_seconds"], 0.001)
+	assert.Equal(t, "abc123", capturedBody["snapshot_id"])
+	assert.InDelta(t, 10, capturedBody["files_new"], 0)
+	assert.InDelta(t, 5, capturedBody["files_changed"], 0)
+	assert.InDelta(t, 1024, capturedBody["data_added"], 0)
+	assert.InDelta(t, 7, capturedBody["snapshots_kept"], 0)
+	assert.InDelta(t, 2, capturedBody["snapshots_removed"], 0)
+	assert.InDelta(t, 9, capturedBody["snapshot_count"], 0)
+	assert.NotContains(t, capturedBody, "failed_step")
+	assert.NotContains(t, capturedBody, "error")
+}
+
+func TestNotify_FailurePayload(t *testing.T) {
+	var capturedBody map[string]any
+
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			_ = json.Unmarshal(body, &capturedBody)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+
+	msg := models.TelegramMessage{
+		Success:      false,
+		Host:         "server1",
+		FailedStep:   "backup",
+		ErrorMessage: "repository not found",
+	}
+
+	result, err := svc.Notify(context.Background(), models.WebhookConfig{URL: "https://example.com/hook"}, msg)
+
+	require.NoError(t, err)
+	assert.True(t, result.Sent)
+	assert.Equal(t, false, capturedBody["success"])
+	assert.Equal(t, "backup", capturedBody["failed_step"])
+	assert.Equal(t, "repository not found", capturedBody["error"])
+}
+
+func TestNotify_CustomMethod(t *testing.T) {
+	var capturedMethod string
+
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			capturedMethod = req.Method
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	cfg := models.WebhookConfig{URL: "https://example.com/hook", Method: http.MethodPut}
+
+	_, err := svc.Notify(context.Background(), cfg, models.TelegramMessage{Success: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPut, capturedMethod)
+}
+
+func TestNotify_HTTPError(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.Notify(context.Background(), models.WebhookConfig{URL: "https://example.com/hook"}, models.TelegramMessage{})
+
+	require.NoError(t, err)
+	assert.False(t, result.Sent)
+	assert.Contains(t, result.Error.Error(), "connection refused")
+}
+
+func TestNotify_BadStatus(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       io.NopCloser(strings.NewReader("boom")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClient(testLogger(), httpClient)
+	result, err := svc.Notify(context.Background(), models.WebhookConfig{URL: "https://example.com/hook"}, models.TelegramMessage{})
+
+	require.NoError(t, err)
+	assert.False(t, result.Sent)
+	assert.Contains(t, result.Error.Error(), "500")
+}
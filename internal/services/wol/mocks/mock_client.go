@@ -38,8 +38,8 @@ func (_m *MockClient) EXPECT() *MockClient_Expecter {
 }
 
 // Wake provides a mock function for the type MockClient
-func (_mock *MockClient) Wake(broadcastIP string, mac net.HardwareAddr) error {
-	ret := _mock.Called(broadcastIP, mac)
+func (_mock *MockClient) Wake(addr string, mac net.HardwareAddr) error {
+	ret := _mock.Called(addr, mac)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Wake")
@@ -47,7 +47,7 @@ func (_mock *MockClient) Wake(broadcastIP string, mac net.HardwareAddr) error {
 
 	var r0 error
 	if returnFunc, ok := ret.Get(0).(func(string, net.HardwareAddr) error); ok {
-		r0 = returnFunc(broadcastIP, mac)
+		r0 = returnFunc(addr, mac)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,13 +60,13 @@ type MockClient_Wake_Call struct {
 }
 
 // Wake is a helper method to define mock.On call
-//   - broadcastIP string
+//   - addr string
 //   - mac net.HardwareAddr
-func (_e *MockClient_Expecter) Wake(broadcastIP interface{}, mac interface{}) *MockClient_Wake_Call {
-	return &MockClient_Wake_Call{Call: _e.mock.On("Wake", broadcastIP, mac)}
+func (_e *MockClient_Expecter) Wake(addr interface{}, mac interface{}) *MockClient_Wake_Call {
+	return &MockClient_Wake_Call{Call: _e.mock.On("Wake", addr, mac)}
 }
 
-func (_c *MockClient_Wake_Call) Run(run func(broadcastIP string, mac net.HardwareAddr)) *MockClient_Wake_Call {
+func (_c *MockClient_Wake_Call) Run(run func(addr string, mac net.HardwareAddr)) *MockClient_Wake_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 string
 		if args[0] != nil {
@@ -89,7 +89,7 @@ func (_c *MockClient_Wake_Call) Return(err error) *MockClient_Wake_Call {
 	return _c
 }
 
-func (_c *MockClient_Wake_Call) RunAndReturn(run func(broadcastIP string, mac net.HardwareAddr) error) *MockClient_Wake_Call {
+func (_c *MockClient_Wake_Call) RunAndReturn(run func(addr string, mac net.HardwareAddr) error) *MockClient_Wake_Call {
 	_c.Call.Return(run)
 	return _c
 }
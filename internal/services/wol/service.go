@@ -3,9 +3,12 @@ package wol
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"time"
 
 	"github.com/fgeck/gorestic-homelab/internal/models"
@@ -13,6 +16,12 @@ import (
 	"github.com/rs/zerolog"
 )
 
+// DefaultPort is the UDP port magic packets are sent to unless configured.
+const DefaultPort = 9
+
+// DefaultHTTPTimeout bounds each poll request unless configured.
+const DefaultHTTPTimeout = 5 * time.Second
+
 // Service defines the interface for Wake-on-LAN operations.
 type Service interface {
 	Wake(ctx context.Context, cfg models.WOLConfig) (*models.WOLResult, error)
@@ -20,7 +29,7 @@ type Service interface {
 
 // Client wraps the wol library for mocking.
 type Client interface {
-	Wake(broadcastIP string, mac net.HardwareAddr) error
+	Wake(addr string, mac net.HardwareAddr) error
 }
 
 // HTTPClient allows mocking HTTP requests.
@@ -31,22 +40,26 @@ type HTTPClient interface {
 // DefaultClient is the default implementation using mdlayher/wol.
 type DefaultClient struct{}
 
-// Wake sends a magic packet to the specified MAC address.
-func (c *DefaultClient) Wake(broadcastIP string, mac net.HardwareAddr) error {
+// Wake sends a magic packet to the specified MAC address via addr, the broadcast
+// IP and UDP port. IPv6 addresses are bracketed and may carry a zone, e.g. [ff02::1%eth0]:9.
+func (c *DefaultClient) Wake(addr string, mac net.HardwareAddr) error {
+	// Validate broadcast IP, netip keeps the zone net.ParseIP rejects
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid WOL address %s: %w", addr, err)
+	}
+	if _, err := netip.ParseAddr(host); err != nil {
+		return fmt.Errorf("invalid broadcast IP: %s", host)
+	}
+
 	client, err := wol.NewClient()
 	if err != nil {
 		return fmt.Errorf("failed to create WOL client: %w", err)
 	}
 	defer func() { _ = client.Close() }()
 
-	// Parse broadcast IP
-	ip := net.ParseIP(broadcastIP)
-	if ip == nil {
-		return fmt.Errorf("invalid broadcast IP: %s", broadcastIP)
-	}
-
 	// Send wake packet
-	if err := client.Wake(ip.String()+":9", mac); err != nil {
+	if err := client.Wake(addr, mac); err != nil {
 		return fmt.Errorf("failed to send WOL packet: %w", err)
 	}
 
@@ -60,12 +73,16 @@ type Impl struct {
 	logger     zerolog.Logger
 }
 
-// New creates a new WOL service.
-func New(logger zerolog.Logger) *Impl {
+// New creates a new WOL service whose poll requests time out after
+// httpTimeout, or DefaultHTTPTimeout if it is not positive.
+func New(logger zerolog.Logger, httpTimeout time.Duration) *Impl {
+	if httpTimeout <= 0 {
+		httpTimeout = DefaultHTTPTimeout
+	}
 	return &Impl{
 		wolClient: &DefaultClient{},
 		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout: httpTimeout,
 		},
 		logger: logger,
 	}
@@ -85,26 +102,12 @@ func (s *Impl) Wake(ctx context.Context, cfg models.WOLConfig) (*models.WOLResul
 	result := &models.WOLResult{}
 	start := time.Now()
 
-	// Parse MAC address
-	mac, err := net.ParseMAC(cfg.MACAddress)
-	if err != nil {
-		result.Error = fmt.Errorf("invalid MAC address %q: %w", cfg.MACAddress, err)
-		return result, nil
-	}
-
-	s.logger.Info().
-		Str("mac", cfg.MACAddress).
-		Str("broadcast", cfg.BroadcastIP).
-		Msg("sending WOL packet")
-
-	// Send WOL packet
-	if err := s.wolClient.Wake(cfg.BroadcastIP, mac); err != nil {
+	if err := s.sendPackets(ctx, cfg, result); err != nil {
+		result.WaitDuration = time.Since(start)
 		result.Error = err
-		return result, nil //nolint:nilerr // error is stored in result struct by design
+		return result, nil
 	}
-
-	result.PacketSent = true
-	s.logger.Info().Msg("WOL packet sent successfully")
+	s.logger.Info().Int("packets", result.PacketsSent).Msg("WOL packets sent successfully")
 
 	// If no target URL specified, we're done
 	if cfg.PollURL == "" {
@@ -147,6 +150,78 @@ func (s *Impl) Wake(ctx context.Context, cfg models.WOLConfig) (*models.WOLResul
 	return result, nil
 }
 
+// sendPackets sends cfg.PacketCount rounds of magic packets, spaced by
+// cfg.PacketInterval, to every MAC so one bad address does not keep the
+// others asleep. A MAC is only reported as failed if none of its packets
+// could be sent.
+func (s *Impl) sendPackets(ctx context.Context, cfg models.WOLConfig, result *models.WOLResult) error {
+	macAddresses := cfg.MACs()
+	macs := make([]net.HardwareAddr, len(macAddresses))
+	macErrs := make([]error, len(macAddresses))
+	for i, macAddress := range macAddresses {
+		mac, err := net.ParseMAC(macAddress)
+		if err != nil {
+			macErrs[i] = fmt.Errorf("invalid MAC address %q: %w", macAddress, err)
+			continue
+		}
+		macs[i] = mac
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = DefaultPort
+	}
+	addr := net.JoinHostPort(cfg.BroadcastIP, strconv.Itoa(port))
+
+	sent := make([]bool, len(macAddresses))
+	for round := range max(cfg.PacketCount, 1) {
+		if round > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(cfg.PacketInterval):
+			}
+		}
+
+		for i, mac := range macs {
+			if mac == nil {
+				continue
+			}
+
+			s.logger.Info().
+				Str("mac", macAddresses[i]).
+				Str("addr", addr).
+				Int("packet", round+1).
+				Msg("sending WOL packet")
+
+			if err := s.wolClient.Wake(addr, mac); err != nil {
+				macErrs[i] = fmt.Errorf("MAC %s: %w", macAddresses[i], err)
+				continue
+			}
+			sent[i] = true
+			result.PacketSent = true
+			result.PacketsSent++
+		}
+	}
+
+	var errs []error
+	for i, err := range macErrs {
+		if err != nil && !sent[i] {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// isReadyStatus reports whether an HTTP status means the target is ready:
+// exactly expect if set, any 2xx or 3xx otherwise.
+func isReadyStatus(status, expect int) bool {
+	if expect != 0 {
+		return status == expect
+	}
+	return status >= 200 && status < 400
+}
+
 func (s *Impl) waitForTarget(ctx context.Context, cfg models.WOLConfig) error {
 	deadline := time.Now().Add(cfg.Timeout)
 
@@ -170,12 +245,14 @@ func (s *Impl) waitForTarget(ctx context.Context, cfg models.WOLConfig) error {
 		resp, err := s.httpClient.Do(req)
 		if err == nil {
 			_ = resp.Body.Close()
-			// Any response means the target is up
-			return nil
+			if isReadyStatus(resp.StatusCode, cfg.ExpectStatus) {
+				return nil
+			}
+			s.logger.Debug().Int("status", resp.StatusCode).Msg("target not ready yet")
+		} else {
+			s.logger.Debug().Err(err).Msg("target not ready yet")
 		}
 
-		s.logger.Debug().Err(err).Msg("target not ready yet")
-
 		// Wait before next poll
 		select {
 		case <-ctx.Done():
@@ -17,12 +17,12 @@ import (
 )
 
 type mockWOLClient struct {
-	wakeFunc func(broadcastIP string, mac net.HardwareAddr) error
+	wakeFunc func(addr string, mac net.HardwareAddr) error
 }
 
-func (m *mockWOLClient) Wake(broadcastIP string, mac net.HardwareAddr) error {
+func (m *mockWOLClient) Wake(addr string, mac net.HardwareAddr) error {
 	if m.wakeFunc != nil {
-		return m.wakeFunc(broadcastIP, mac)
+		return m.wakeFunc(addr, mac)
 	}
 	return nil
 }
@@ -47,12 +47,12 @@ func testLogger() zerolog.Logger {
 
 func TestWake_Success_NoTargetURL(t *testing.T) {
 	var capturedMAC net.HardwareAddr
-	var capturedBroadcastIP string
+	var capturedAddr string
 
 	wolClient := &mockWOLClient{
-		wakeFunc: func(broadcastIP string, mac net.HardwareAddr) error {
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
 			capturedMAC = mac
-			capturedBroadcastIP = broadcastIP
+			capturedAddr = addr
 			return nil
 		},
 	}
@@ -73,7 +73,120 @@ func TestWake_Success_NoTargetURL(t *testing.T) {
 
 	expectedMAC, _ := net.ParseMAC("AA:BB:CC:DD:EE:FF")
 	assert.Equal(t, expectedMAC, capturedMAC)
-	assert.Equal(t, "192.168.1.255", capturedBroadcastIP)
+	assert.Equal(t, "192.168.1.255:9", capturedAddr)
+}
+
+func TestWake_CustomPort(t *testing.T) {
+	var capturedAddr string
+
+	wolClient := &mockWOLClient{
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
+			capturedAddr = addr
+			return nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), wolClient, nil)
+
+	cfg := models.WOLConfig{
+		MACAddress:  "AA:BB:CC:DD:EE:FF",
+		BroadcastIP: "192.168.1.255",
+		Port:        7,
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, "192.168.1.255:7", capturedAddr)
+}
+
+func TestWake_IPv6BroadcastAddress(t *testing.T) {
+	tests := []struct {
+		broadcastIP string
+		want        string
+	}{
+		{"ff02::1%eth0", "[ff02::1%eth0]:9"},
+		{"2001:db8::ff", "[2001:db8::ff]:9"},
+		{"192.168.1.255", "192.168.1.255:9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.broadcastIP, func(t *testing.T) {
+			var capturedAddr string
+			wolClient := &mockWOLClient{
+				wakeFunc: func(addr string, mac net.HardwareAddr) error {
+					capturedAddr = addr
+					return nil
+				},
+			}
+
+			svc := NewWithClients(testLogger(), wolClient, nil)
+			result, err := svc.Wake(context.Background(), models.WOLConfig{
+				MACAddress:  "AA:BB:CC:DD:EE:FF",
+				BroadcastIP: tt.broadcastIP,
+			})
+
+			require.NoError(t, err)
+			assert.Nil(t, result.Error)
+			assert.Equal(t, tt.want, capturedAddr)
+		})
+	}
+}
+
+func TestWake_IPv6PollURL(t *testing.T) {
+	var capturedHost string
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			capturedHost = req.URL.Host
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), &mockWOLClient{}, httpClient)
+	result, err := svc.Wake(context.Background(), models.WOLConfig{
+		MACAddress:   "AA:BB:CC:DD:EE:FF",
+		BroadcastIP:  "ff02::1%eth0",
+		PollURL:      "http://[2001:db8::10]:8000/health",
+		Timeout:      time.Second,
+		PollInterval: 10 * time.Millisecond,
+	})
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.TargetReady)
+	assert.Equal(t, "[2001:db8::10]:8000", capturedHost)
+}
+
+func TestDefaultClient_IPv6(t *testing.T) {
+	conn, err := net.ListenPacket("udp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback not available: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	mac, err := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	require.NoError(t, err)
+
+	client := &DefaultClient{}
+	require.NoError(t, client.Wake(conn.LocalAddr().String(), mac))
+
+	buf := make([]byte, 256)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 102, n) // 6 x 0xff followed by 16 repetitions of the MAC
+}
+
+func TestDefaultClient_InvalidBroadcastIP(t *testing.T) {
+	client := &DefaultClient{}
+	mac, err := net.ParseMAC("AA:BB:CC:DD:EE:FF")
+	require.NoError(t, err)
+
+	err = client.Wake("not-an-ip:9", mac)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid broadcast IP")
 }
 
 func TestWake_InvalidMAC(t *testing.T) {
@@ -92,9 +205,162 @@ func TestWake_InvalidMAC(t *testing.T) {
 	assert.Contains(t, result.Error.Error(), "invalid MAC address")
 }
 
+func TestWake_MultipleMACs(t *testing.T) {
+	var capturedMACs []string
+
+	wolClient := &mockWOLClient{
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
+			capturedMACs = append(capturedMACs, mac.String())
+			return nil
+		},
+	}
+
+	var polls int
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			polls++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), wolClient, httpClient)
+
+	cfg := models.WOLConfig{
+		MACAddresses: []string{"AA:BB:CC:DD:EE:01", "AA:BB:CC:DD:EE:02", "AA:BB:CC:DD:EE:03"},
+		BroadcastIP:  "192.168.1.255",
+		PollURL:      "http://192.168.1.100:8000",
+		Timeout:      time.Second,
+		PollInterval: time.Millisecond,
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.PacketSent)
+	assert.True(t, result.TargetReady)
+	assert.Equal(t, []string{"aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:02", "aa:bb:cc:dd:ee:03"}, capturedMACs)
+	assert.Equal(t, 1, polls, "the poll URL is checked once for all MACs")
+}
+
+func TestWake_MultipleMACs_OneInvalid(t *testing.T) {
+	var capturedMACs []string
+
+	wolClient := &mockWOLClient{
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
+			capturedMACs = append(capturedMACs, mac.String())
+			return nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), wolClient, nil)
+
+	cfg := models.WOLConfig{
+		MACAddresses: []string{"AA:BB:CC:DD:EE:01", "not-a-mac", "AA:BB:CC:DD:EE:03"},
+		BroadcastIP:  "192.168.1.255",
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	// Valid MACs still got their packet
+	assert.True(t, result.PacketSent)
+	assert.Equal(t, []string{"aa:bb:cc:dd:ee:01", "aa:bb:cc:dd:ee:03"}, capturedMACs)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), `invalid MAC address "not-a-mac"`)
+	assert.False(t, result.TargetReady)
+}
+
+func TestWake_PacketCount(t *testing.T) {
+	var calls int
+
+	wolClient := &mockWOLClient{
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
+			calls++
+			return nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), wolClient, nil)
+
+	cfg := models.WOLConfig{
+		MACAddresses:   []string{"AA:BB:CC:DD:EE:01", "AA:BB:CC:DD:EE:02"},
+		BroadcastIP:    "192.168.1.255",
+		PacketCount:    3,
+		PacketInterval: time.Millisecond,
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.Equal(t, 6, calls)
+	assert.Equal(t, 6, result.PacketsSent)
+}
+
+func TestWake_PacketCount_PartialFailure(t *testing.T) {
+	var calls int
+
+	// The first packet is dropped, the retry gets through
+	wolClient := &mockWOLClient{
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
+			calls++
+			if calls == 1 {
+				return errors.New("network unreachable")
+			}
+			return nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), wolClient, nil)
+
+	cfg := models.WOLConfig{
+		MACAddress:     "AA:BB:CC:DD:EE:FF",
+		BroadcastIP:    "192.168.1.255",
+		PacketCount:    2,
+		PacketInterval: time.Millisecond,
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.Nil(t, result.Error)
+	assert.True(t, result.PacketSent)
+	assert.Equal(t, 1, result.PacketsSent)
+}
+
+func TestWake_PacketCount_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls int
+	wolClient := &mockWOLClient{
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
+			calls++
+			cancel()
+			return nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), wolClient, nil)
+
+	cfg := models.WOLConfig{
+		MACAddress:     "AA:BB:CC:DD:EE:FF",
+		BroadcastIP:    "192.168.1.255",
+		PacketCount:    5,
+		PacketInterval: time.Hour,
+	}
+
+	result, err := svc.Wake(ctx, cfg)
+
+	require.NoError(t, err)
+	assert.ErrorIs(t, result.Error, context.Canceled)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, result.PacketsSent)
+}
+
 func TestWake_SendFailed(t *testing.T) {
 	wolClient := &mockWOLClient{
-		wakeFunc: func(broadcastIP string, mac net.HardwareAddr) error {
+		wakeFunc: func(addr string, mac net.HardwareAddr) error {
 			return errors.New("network error")
 		},
 	}
@@ -144,6 +410,90 @@ func TestWake_WithTargetURL_ImmediateSuccess(t *testing.T) {
 	assert.Nil(t, result.Error)
 }
 
+func TestWake_WithTargetURL_WrongStatusKeepsPolling(t *testing.T) {
+	statuses := []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusNoContent, http.StatusOK}
+	callCount := 0
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			status := statuses[callCount]
+			callCount++
+			return &http.Response{
+				StatusCode: status,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), &mockWOLClient{}, httpClient)
+
+	cfg := models.WOLConfig{
+		MACAddress:   "AA:BB:CC:DD:EE:FF",
+		BroadcastIP:  "192.168.1.255",
+		PollURL:      "http://192.168.1.100:8000",
+		Timeout:      10 * time.Second,
+		PollInterval: time.Millisecond,
+		ExpectStatus: http.StatusOK,
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.True(t, result.TargetReady)
+	assert.Nil(t, result.Error)
+	// 204 is a success status but not the expected one
+	assert.Equal(t, 4, callCount)
+}
+
+func TestWake_WithTargetURL_DefaultRejectsServerErrors(t *testing.T) {
+	httpClient := &mockHTTPClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       io.NopCloser(strings.NewReader("")),
+			}, nil
+		},
+	}
+
+	svc := NewWithClients(testLogger(), &mockWOLClient{}, httpClient)
+
+	cfg := models.WOLConfig{
+		MACAddress:   "AA:BB:CC:DD:EE:FF",
+		BroadcastIP:  "192.168.1.255",
+		PollURL:      "http://192.168.1.100:8000",
+		Timeout:      20 * time.Millisecond,
+		PollInterval: time.Millisecond,
+	}
+
+	result, err := svc.Wake(context.Background(), cfg)
+
+	require.NoError(t, err)
+	assert.False(t, result.TargetReady)
+	require.Error(t, result.Error)
+	assert.Contains(t, result.Error.Error(), "timeout")
+}
+
+func TestIsReadyStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		expect   int
+		expected bool
+	}{
+		{"default 200", 200, 0, true},
+		{"default 302", 302, 0, true},
+		{"default 404", 404, 0, false},
+		{"default 503", 503, 0, false},
+		{"expected match", 401, 401, true},
+		{"expected mismatch", 200, 401, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isReadyStatus(tt.status, tt.expect))
+		})
+	}
+}
+
 func TestWake_WithTargetURL_DelayedSuccess(t *testing.T) {
 	wolClient := &mockWOLClient{}
 
@@ -271,3 +621,19 @@ func TestWake_WithStabilizeWait(t *testing.T) {
 	// Duration should be at least the stabilize wait time
 	assert.GreaterOrEqual(t, duration, stabilizeWait)
 }
+
+func TestNew_DefaultHTTPTimeout(t *testing.T) {
+	svc := New(testLogger(), 0)
+
+	client, ok := svc.httpClient.(*http.Client)
+	require.True(t, ok)
+	assert.Equal(t, 5*time.Second, client.Timeout)
+}
+
+func TestNew_ConfiguredHTTPTimeout(t *testing.T) {
+	svc := New(testLogger(), 12*time.Second)
+
+	client, ok := svc.httpClient.(*http.Client)
+	require.True(t, ok)
+	assert.Equal(t, 12*time.Second, client.Timeout)
+}